@@ -1,7 +1,6 @@
 package astar
 
 import (
-	"github.com/ielm/neostd/collections"
 	"github.com/ielm/neostd/collections/graph"
 	"github.com/ielm/neostd/collections/heap"
 	"github.com/ielm/neostd/collections/maps"
@@ -16,73 +15,20 @@ type AStarResult[V comparable, E any] struct {
 	Explored int
 }
 
-// AStar performs A* search algorithm on the given graph
+// AStar performs A* search algorithm on the given graph. The heuristic
+// estimates the cost from a vertex to the goal; if it never overestimates,
+// the path found is a shortest one.
+//
+// Example:
+//
+//	result := astar.AStar[string, int](g, "a", "z", func(v string) int { return 0 }, astar.Number[int]())
 func AStar[V comparable, E any](
 	g graph.Graph[V, E],
 	start, goal V,
 	heuristic func(V) E,
-	less func(E, E) bool,
-	zero E,
-	add func(E, E) E,
+	num Numeric[E],
 ) res.Result[AStarResult[V, E]] {
-	openSet := heap.NewMinBinaryHeap(func(a, b collections.Pair[V, E]) int {
-		if less(a.Value, b.Value) {
-			return -1
-		}
-		if less(b.Value, a.Value) {
-			return 1
-		}
-		return 0
-	})
-	openSet.Push(collections.Pair[V, E]{Key: start, Value: zero})
-
-	cameFrom := maps.NewHashMap[V, V](g.Comparator()).Unwrap()
-	gScore := maps.NewHashMap[V, E](g.Comparator()).Unwrap()
-	gScore.Put(start, zero)
-
-	fScore := maps.NewHashMap[V, E](g.Comparator()).Unwrap()
-	fScore.Put(start, heuristic(start))
-
-	explored := 0
-
-	for !openSet.IsEmpty() {
-		current := openSet.Pop().Unwrap().Key
-		explored++
-
-		if g.Comparator()(current, goal) == 0 {
-			path := reconstructPath(cameFrom, current)
-			cost, _ := gScore.Get(current)
-			return res.Ok(AStarResult[V, E]{
-				Path:     path,
-				Cost:     cost,
-				Explored: explored,
-			})
-		}
-
-		for _, neighbor := range g.GetNeighbors(current) {
-			weight, ok := g.GetWeight(current, neighbor)
-			if !ok {
-				return res.Err[AStarResult[V, E]](errors.New(errors.ErrInternal, "edge weight not found"))
-			}
-			currentGScore, _ := gScore.Get(current)
-
-			tentativeGScore := add(currentGScore, weight)
-
-			neighborGScore, exists := gScore.Get(neighbor)
-			if !exists || less(tentativeGScore, neighborGScore) {
-				cameFrom.Put(neighbor, current)
-				gScore.Put(neighbor, tentativeGScore)
-				newFScore := add(tentativeGScore, heuristic(neighbor))
-				fScore.Put(neighbor, newFScore)
-
-				if !openSet.Contains(collections.Pair[V, E]{Key: neighbor}) {
-					openSet.Push(collections.Pair[V, E]{Key: neighbor, Value: newFScore})
-				}
-			}
-		}
-	}
-
-	return res.Err[AStarResult[V, E]](errors.New(errors.ErrNotFound, "no path found"))
+	return astarWithConfig(g, start, goal, heuristic, num, defaultAStarConfig[V, E]())
 }
 
 // reconstructPath reconstructs the path from start to goal
@@ -104,88 +50,113 @@ func AStarWithOptions[V comparable, E any](
 	g graph.Graph[V, E],
 	start, goal V,
 	heuristic func(V) E,
-	less func(E, E) bool,
-	zero E,
-	add func(E, E) E,
+	num Numeric[E],
 	options ...AStarOption[V, E],
 ) res.Result[AStarResult[V, E]] {
-	config := defaultAStarConfig[V, E]()
-	for _, option := range options {
-		option(&config)
-	}
-
-	return astarWithConfig(g, start, goal, heuristic, less, zero, add, config)
+	return astarWithConfig(g, start, goal, heuristic, num, NewAStarConfig(options...))
 }
 
 // AStarOption represents an option for configuring A* search
-type AStarOption[V comparable, E any] func(*astarConfig[V, E])
+type AStarOption[V comparable, E any] func(*AStarConfig[V, E])
 
-type astarConfig[V comparable, E any] struct {
+// AStarConfig holds the configuration for A* search
+type AStarConfig[V comparable, E any] struct {
 	maxIterations int
 	earlyExit     func(V, E) bool
 	onExplore     func(V, E)
 }
 
-func defaultAStarConfig[V comparable, E any]() astarConfig[V, E] {
-	return astarConfig[V, E]{
+func defaultAStarConfig[V comparable, E any]() AStarConfig[V, E] {
+	return AStarConfig[V, E]{
 		maxIterations: -1,
 		earlyExit:     func(V, E) bool { return false },
 		onExplore:     func(V, E) {},
 	}
 }
 
+// NewAStarConfig applies options to the default configuration. It lets
+// other searches, such as grid.JPS, accept the same options as A*.
+func NewAStarConfig[V comparable, E any](options ...AStarOption[V, E]) AStarConfig[V, E] {
+	config := defaultAStarConfig[V, E]()
+	for _, option := range options {
+		option(&config)
+	}
+	return config
+}
+
+// MaxIterations returns the maximum number of vertices to explore, or -1 if
+// there is no limit
+func (c AStarConfig[V, E]) MaxIterations() int {
+	return c.maxIterations
+}
+
+// EarlyExit reports whether the search should stop at vertex v, reached at
+// cost e, as if it were the goal
+func (c AStarConfig[V, E]) EarlyExit(v V, e E) bool {
+	return c.earlyExit(v, e)
+}
+
+// OnExplore calls the exploration callback for vertex v, reached at cost e
+func (c AStarConfig[V, E]) OnExplore(v V, e E) {
+	c.onExplore(v, e)
+}
+
 // WithMaxIterations sets the maximum number of iterations for A* search
 func WithMaxIterations[V comparable, E any](maxIterations int) AStarOption[V, E] {
-	return func(c *astarConfig[V, E]) {
+	return func(c *AStarConfig[V, E]) {
 		c.maxIterations = maxIterations
 	}
 }
 
 // WithEarlyExit sets an early exit condition for A* search
 func WithEarlyExit[V comparable, E any](earlyExit func(V, E) bool) AStarOption[V, E] {
-	return func(c *astarConfig[V, E]) {
+	return func(c *AStarConfig[V, E]) {
 		c.earlyExit = earlyExit
 	}
 }
 
 // WithOnExplore sets a callback function to be called when a node is explored
 func WithOnExplore[V comparable, E any](onExplore func(V, E)) AStarOption[V, E] {
-	return func(c *astarConfig[V, E]) {
+	return func(c *AStarConfig[V, E]) {
 		c.onExplore = onExplore
 	}
 }
 
+// frontierEntry is a vertex in the open set, with its estimated total cost
+type frontierEntry[V any, E any] struct {
+	vertex V
+	fScore E
+}
+
 func astarWithConfig[V comparable, E any](
 	g graph.Graph[V, E],
 	start, goal V,
 	heuristic func(V) E,
-	less func(E, E) bool,
-	zero E,
-	add func(E, E) E,
-	config astarConfig[V, E],
+	num Numeric[E],
+	config AStarConfig[V, E],
 ) res.Result[AStarResult[V, E]] {
-	openSet := heap.NewMinBinaryHeap(func(a, b collections.Pair[V, E]) int {
-		if less(a.Value, b.Value) {
+	openSet := heap.NewMinIndexedHeap(func(a, b *frontierEntry[V, E]) int {
+		if num.Less(a.fScore, b.fScore) {
 			return -1
 		}
-		if less(b.Value, a.Value) {
+		if num.Less(b.fScore, a.fScore) {
 			return 1
 		}
 		return 0
 	})
-	openSet.Push(collections.Pair[V, E]{Key: start, Value: zero})
+	// handles locates each vertex of the open set, so that a cheaper path
+	// updates its entry in place instead of adding a duplicate
+	handles := maps.NewHashMap[V, *heap.Handle[*frontierEntry[V, E]]](g.Comparator()).Unwrap()
+	handles.Put(start, openSet.Push(&frontierEntry[V, E]{vertex: start, fScore: heuristic(start)}))
 
 	cameFrom := maps.NewHashMap[V, V](g.Comparator()).Unwrap()
 	gScore := maps.NewHashMap[V, E](g.Comparator()).Unwrap()
-	gScore.Put(start, zero)
-
-	fScore := maps.NewHashMap[V, E](g.Comparator()).Unwrap()
-	fScore.Put(start, heuristic(start))
+	gScore.Put(start, num.Zero())
 
 	explored := 0
 
 	for !openSet.IsEmpty() && (config.maxIterations == -1 || explored < config.maxIterations) {
-		current := openSet.Pop().Unwrap().Key
+		current := openSet.Pop().Unwrap().vertex
 		currentGScore, _ := gScore.Get(current)
 		explored++
 
@@ -200,24 +171,26 @@ func astarWithConfig[V comparable, E any](
 			})
 		}
 
-		for _, neighbor := range g.GetNeighbors(current) {
-			weight, ok := g.GetWeight(current, neighbor)
-			if !ok {
-				return res.Err[AStarResult[V, E]](errors.New(errors.ErrInternal, "edge weight not found"))
-			}
-
-			tentativeGScore := add(currentGScore, weight)
+		for _, edge := range g.GetEdges(current) {
+			neighbor := edge.Destination
+			tentativeGScore := num.Add(currentGScore, edge.Weight)
 
 			neighborGScore, exists := gScore.Get(neighbor)
-			if !exists || less(tentativeGScore, neighborGScore) {
-				cameFrom.Put(neighbor, current)
-				gScore.Put(neighbor, tentativeGScore)
-				newFScore := add(tentativeGScore, heuristic(neighbor))
-				fScore.Put(neighbor, newFScore)
-
-				if !openSet.Contains(collections.Pair[V, E]{Key: neighbor}) {
-					openSet.Push(collections.Pair[V, E]{Key: neighbor, Value: newFScore})
+			if exists && !num.Less(tentativeGScore, neighborGScore) {
+				continue
+			}
+			cameFrom.Put(neighbor, current)
+			gScore.Put(neighbor, tentativeGScore)
+			fScore := num.Add(tentativeGScore, heuristic(neighbor))
+
+			if handle, ok := handles.Get(neighbor); ok && handle.InHeap() {
+				if err := openSet.Update(handle, &frontierEntry[V, E]{vertex: neighbor, fScore: fScore}); err != nil {
+					return res.Err[AStarResult[V, E]](errors.NewWithCause(errors.ErrInternal, "failed to update open set", err))
 				}
+			} else {
+				// New, or closed but reached more cheaply through an
+				// inconsistent heuristic, so it must be explored again
+				handles.Put(neighbor, openSet.Push(&frontierEntry[V, E]{vertex: neighbor, fScore: fScore}))
 			}
 		}
 	}
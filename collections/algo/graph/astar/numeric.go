@@ -0,0 +1,49 @@
+package astar
+
+import "golang.org/x/exp/constraints"
+
+// Numeric describes the path costs of a search: how to compare and add them,
+// and the cost of the empty path.
+type Numeric[E any] interface {
+	Less(a, b E) bool
+	Zero() E
+	Add(a, b E) E
+}
+
+// Number returns the Numeric for a built-in integer or floating-point type.
+//
+// Example:
+//
+//	result := astar.AStar(g, "a", "z", heuristic, astar.Number[float64]())
+func Number[E constraints.Integer | constraints.Float]() Numeric[E] {
+	return number[E]{}
+}
+
+type number[E constraints.Integer | constraints.Float] struct{}
+
+func (number[E]) Less(a, b E) bool { return a < b }
+func (number[E]) Zero() E          { return 0 }
+func (number[E]) Add(a, b E) E     { return a + b }
+
+// NumericFuncs returns a Numeric built from separate functions, for cost
+// types other than built-in numbers.
+//
+// Example:
+//
+//	costs := astar.NumericFuncs(
+//		func(a, b Cost) bool { return a.Total() < b.Total() },
+//		Cost{},
+//		func(a, b Cost) Cost { return a.Plus(b) })
+func NumericFuncs[E any](less func(E, E) bool, zero E, add func(E, E) E) Numeric[E] {
+	return numericFuncs[E]{less: less, zero: zero, add: add}
+}
+
+type numericFuncs[E any] struct {
+	less func(E, E) bool
+	zero E
+	add  func(E, E) E
+}
+
+func (n numericFuncs[E]) Less(a, b E) bool { return n.less(a, b) }
+func (n numericFuncs[E]) Zero() E          { return n.zero }
+func (n numericFuncs[E]) Add(a, b E) E     { return n.add(a, b) }
@@ -0,0 +1,162 @@
+package bellmanford
+
+import (
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// BellmanFordResult represents the result of the Bellman-Ford algorithm.
+// Only vertices reachable from the source appear in Distances.
+type BellmanFordResult[V comparable, E any] struct {
+	Distances    map[V]E
+	Predecessors map[V]V
+}
+
+// BellmanFord computes the shortest paths from source to every reachable
+// vertex of the given graph. Unlike Dijkstra, it handles negative edge
+// weights, at a cost of O(V·E) time.
+//
+// If a cycle of negative total weight is reachable from source, shortest
+// paths are undefined and an ErrInvalidArgument error is returned; use
+// NegativeCycle to find the cycle. In an undirected graph every negative
+// edge forms such a cycle.
+//
+// Example:
+//
+//	result := bellmanford.BellmanFord[string, int](g, "a",
+//		func(a, b int) bool { return a < b }, 0,
+//		func(a, b int) int { return a + b })
+//	if result.IsOk() {
+//		fmt.Println(result.Unwrap().Distances["d"])
+//	}
+func BellmanFord[V comparable, E any](
+	g graph.Graph[V, E],
+	source V,
+	less func(E, E) bool,
+	zero E,
+	add func(E, E) E,
+) res.Result[BellmanFordResult[V, E]] {
+	if !g.Contains(source) {
+		return res.Err[BellmanFordResult[V, E]](errors.New(errors.ErrNotFound, "source vertex not found"))
+	}
+
+	result, _, cyclic := relax(g, source, less, zero, add)
+	if cyclic {
+		return res.Err[BellmanFordResult[V, E]](errors.New(errors.ErrInvalidArgument, "graph contains a negative cycle reachable from source"))
+	}
+	return res.Ok(result)
+}
+
+// NegativeCycle returns the vertices of a negative cycle reachable from
+// source, in order along the cycle, or None if there is no such cycle.
+//
+// Example:
+//
+//	if cycle := bellmanford.NegativeCycle[string, int](g, "a", less, 0, add); cycle.IsSome() {
+//		fmt.Println("arbitrage:", cycle.Unwrap())
+//	}
+func NegativeCycle[V comparable, E any](
+	g graph.Graph[V, E],
+	source V,
+	less func(E, E) bool,
+	zero E,
+	add func(E, E) E,
+) res.Option[[]V] {
+	if !g.Contains(source) {
+		return res.None[[]V]()
+	}
+
+	result, cycleEnd, cyclic := relax(g, source, less, zero, add)
+	if !cyclic {
+		return res.None[[]V]()
+	}
+
+	// Walking back |V| predecessors from a vertex relaxed in the extra pass
+	// is guaranteed to land on the cycle.
+	v := cycleEnd
+	for i := 0; i < len(result.Distances); i++ {
+		v = result.Predecessors[v]
+	}
+
+	cycle := []V{v}
+	for u := result.Predecessors[v]; u != v; u = result.Predecessors[u] {
+		cycle = append(cycle, u)
+	}
+	// The cycle was collected against the direction of its edges
+	for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+		cycle[i], cycle[j] = cycle[j], cycle[i]
+	}
+	return res.Some(cycle)
+}
+
+// ShortestPath reconstructs the shortest path from the source to the end
+// vertex.
+func ShortestPath[V comparable, E any](result BellmanFordResult[V, E], end V) res.Result[[]V] {
+	if _, ok := result.Distances[end]; !ok {
+		return res.Err[[]V](errors.New(errors.ErrNotFound, "no path found"))
+	}
+
+	path := []V{end}
+	for current := end; ; {
+		prev, ok := result.Predecessors[current]
+		if !ok {
+			break
+		}
+		path = append(path, prev)
+		current = prev
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return res.Ok(path)
+}
+
+// relax runs the relaxation passes of the algorithm. If an edge can still be
+// relaxed after |V|-1 passes, it reports a negative cycle and returns the
+// destination of that edge, whose predecessor chain leads into the cycle.
+func relax[V comparable, E any](
+	g graph.Graph[V, E],
+	source V,
+	less func(E, E) bool,
+	zero E,
+	add func(E, E) E,
+) (BellmanFordResult[V, E], V, bool) {
+	vertices := g.GetVertices()
+	var edges []graph.Edge[V, E]
+	for _, v := range vertices {
+		edges = append(edges, g.GetEdges(v)...)
+	}
+
+	distances := map[V]E{source: zero}
+	predecessors := make(map[V]V)
+
+	// relaxEdges performs one pass over the edges and returns the
+	// destination of the last edge relaxed, if any.
+	relaxEdges := func() (V, bool) {
+		var last V
+		relaxed := false
+		for _, edge := range edges {
+			d, ok := distances[edge.Source]
+			if !ok {
+				continue
+			}
+			newDist := add(d, edge.Weight)
+			if current, ok := distances[edge.Destination]; !ok || less(newDist, current) {
+				distances[edge.Destination] = newDist
+				predecessors[edge.Destination] = edge.Source
+				last, relaxed = edge.Destination, true
+			}
+		}
+		return last, relaxed
+	}
+
+	for i := 1; i < len(vertices); i++ {
+		if _, relaxed := relaxEdges(); !relaxed {
+			break
+		}
+	}
+
+	last, relaxed := relaxEdges()
+	return BellmanFordResult[V, E]{Distances: distances, Predecessors: predecessors}, last, relaxed
+}
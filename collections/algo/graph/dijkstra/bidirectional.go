@@ -0,0 +1,176 @@
+package dijkstra
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/collections/heap"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// BidirectionalResult represents the result of a bidirectional search
+type BidirectionalResult[V comparable, E any] struct {
+	Path     []V
+	Distance E
+	Explored int // Number of vertices settled by both searches
+}
+
+// Bidirectional finds a shortest path from source to target by running
+// Dijkstra's algorithm forward from source and backward from target at the
+// same time, alternating between them, and stopping once the two searches
+// meet and no shorter path can exist. Each search explores a ball of about
+// half the radius of a single search, so it typically settles far fewer
+// vertices on point-to-point queries. Edge weights must not be negative.
+//
+// The backward search follows edges in reverse; for a directed graph, it
+// first collects the incoming edges of every vertex, in O(E) time.
+//
+// Example:
+//
+//	result := dijkstra.Bidirectional[string, int](g, "a", "z",
+//		func(a, b int) bool { return a < b }, 0,
+//		func(a, b int) int { return a + b })
+//	if result.IsOk() {
+//		fmt.Println(result.Unwrap().Path, result.Unwrap().Distance)
+//	}
+func Bidirectional[V comparable, E any](
+	g graph.Graph[V, E],
+	source, target V,
+	less func(E, E) bool,
+	zero E,
+	add func(E, E) E,
+) res.Result[BidirectionalResult[V, E]] {
+	if !g.Contains(source) {
+		return res.Err[BidirectionalResult[V, E]](errors.New(errors.ErrNotFound, "source vertex not found"))
+	}
+	if !g.Contains(target) {
+		return res.Err[BidirectionalResult[V, E]](errors.New(errors.ErrNotFound, "target vertex not found"))
+	}
+	if source == target {
+		return res.Ok(BidirectionalResult[V, E]{Path: []V{source}, Distance: zero})
+	}
+
+	forward := newSearch(source, less, zero, func(v V) []graph.Edge[V, E] { return g.GetEdges(v) })
+	var incoming func(V) []graph.Edge[V, E]
+	if graph.IsUndirected(g) {
+		incoming = g.GetEdges
+	} else {
+		reverse := make(map[V][]graph.Edge[V, E])
+		for _, edge := range g.GetAllEdges() {
+			reverse[edge.Destination] = append(reverse[edge.Destination],
+				graph.Edge[V, E]{Source: edge.Destination, Destination: edge.Source, Weight: edge.Weight})
+		}
+		incoming = func(v V) []graph.Edge[V, E] { return reverse[v] }
+	}
+	backward := newSearch(target, less, zero, incoming)
+
+	// best is the length of the shortest path found so far, through meet
+	var best E
+	var meet V
+	found := false
+
+	for !forward.queue.IsEmpty() && !backward.queue.IsEmpty() {
+		// Once the two frontiers together are at least as long as the best
+		// path, no unexplored path can be shorter.
+		if found && !less(add(forward.queue.Peek().Unwrap().Value, backward.queue.Peek().Unwrap().Value), best) {
+			break
+		}
+
+		current, other := forward, backward
+		if less(backward.queue.Peek().Unwrap().Value, forward.queue.Peek().Unwrap().Value) {
+			current, other = backward, forward
+		}
+		for _, v := range current.step(add) {
+			d := current.dist[v]
+			if od, ok := other.dist[v]; ok {
+				if total := add(d, od); !found || less(total, best) {
+					best, meet, found = total, v, true
+				}
+			}
+		}
+	}
+
+	if !found {
+		return res.Err[BidirectionalResult[V, E]](errors.New(errors.ErrNotFound, "no path found"))
+	}
+
+	path := forward.pathTo(meet)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	path = append(path, backward.pathTo(meet)[1:]...)
+	return res.Ok(BidirectionalResult[V, E]{
+		Path:     path,
+		Distance: best,
+		Explored: len(forward.settled) + len(backward.settled),
+	})
+}
+
+// search is one direction of a bidirectional search.
+type search[V comparable, E any] struct {
+	edges        func(V) []graph.Edge[V, E]
+	queue        *heap.BinaryHeap[collections.Pair[V, E]]
+	dist         map[V]E
+	predecessors map[V]V
+	settled      map[V]bool
+	less         func(E, E) bool
+}
+
+func newSearch[V comparable, E any](start V, less func(E, E) bool, zero E, edges func(V) []graph.Edge[V, E]) *search[V, E] {
+	s := &search[V, E]{
+		edges:        edges,
+		dist:         map[V]E{start: zero},
+		predecessors: make(map[V]V),
+		settled:      make(map[V]bool),
+		less:         less,
+		queue: heap.NewMinBinaryHeap(func(a, b collections.Pair[V, E]) int {
+			if less(a.Value, b.Value) {
+				return -1
+			}
+			if less(b.Value, a.Value) {
+				return 1
+			}
+			return 0
+		}),
+	}
+	s.queue.Push(collections.Pair[V, E]{Key: start, Value: zero})
+	return s
+}
+
+// step settles the nearest unsettled vertex and relaxes its edges, returning
+// the vertices whose distances it touched.
+func (s *search[V, E]) step(add func(E, E) E) []V {
+	current := s.queue.Pop().Unwrap()
+	if s.settled[current.Key] {
+		return nil
+	}
+	s.settled[current.Key] = true
+
+	touched := []V{current.Key}
+	for _, edge := range s.edges(current.Key) {
+		if s.settled[edge.Destination] {
+			continue
+		}
+		newDist := add(current.Value, edge.Weight)
+		if d, ok := s.dist[edge.Destination]; !ok || s.less(newDist, d) {
+			s.dist[edge.Destination] = newDist
+			s.predecessors[edge.Destination] = current.Key
+			s.queue.Push(collections.Pair[V, E]{Key: edge.Destination, Value: newDist})
+			touched = append(touched, edge.Destination)
+		}
+	}
+	return touched
+}
+
+// pathTo returns the path from v back to the start of the search.
+func (s *search[V, E]) pathTo(v V) []V {
+	path := []V{v}
+	for {
+		prev, ok := s.predecessors[v]
+		if !ok {
+			return path
+		}
+		path = append(path, prev)
+		v = prev
+	}
+}
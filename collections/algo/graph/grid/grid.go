@@ -0,0 +1,144 @@
+package grid
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ielm/neostd/errors"
+)
+
+// Cell is a cell of a grid, by column and row
+type Cell struct {
+	X, Y int
+}
+
+// Connectivity is the set of moves allowed between cells
+type Connectivity int
+
+const (
+	// FourWay allows moves to the four orthogonally adjacent cells
+	FourWay Connectivity = 4
+	// EightWay also allows diagonal moves, but not across the corner of a
+	// blocked cell: both orthogonal cells next to the move must be open
+	EightWay Connectivity = 8
+)
+
+// GridGraph is a uniform-cost grid of open and blocked cells, seen as an
+// implicit graph whose vertices are the open cells. Orthogonal moves cost 1
+// and diagonal moves cost √2.
+//
+// Example:
+//
+//	g, _ := grid.NewGridGraph(100, 100, grid.EightWay)
+//	g.SetBlocked(grid.Cell{X: 50, Y: 10}, true)
+type GridGraph struct {
+	width        int
+	height       int
+	connectivity Connectivity
+	blocked      []bool
+}
+
+// NewGridGraph creates a grid of the given size with all cells open
+func NewGridGraph(width, height int, connectivity Connectivity) (*GridGraph, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New(errors.ErrInvalidArgument, "grid dimensions must be positive")
+	}
+	if connectivity != FourWay && connectivity != EightWay {
+		return nil, errors.New(errors.ErrInvalidArgument, "connectivity must be FourWay or EightWay")
+	}
+	return &GridGraph{
+		width:        width,
+		height:       height,
+		connectivity: connectivity,
+		blocked:      make([]bool, width*height),
+	}, nil
+}
+
+// Width returns the number of columns of the grid
+func (g *GridGraph) Width() int {
+	return g.width
+}
+
+// Height returns the number of rows of the grid
+func (g *GridGraph) Height() int {
+	return g.height
+}
+
+// Connectivity returns the moves allowed between cells
+func (g *GridGraph) Connectivity() Connectivity {
+	return g.connectivity
+}
+
+// InBounds reports whether c is a cell of the grid
+func (g *GridGraph) InBounds(c Cell) bool {
+	return c.X >= 0 && c.X < g.width && c.Y >= 0 && c.Y < g.height
+}
+
+// SetBlocked blocks or opens a cell
+func (g *GridGraph) SetBlocked(c Cell, blocked bool) error {
+	if !g.InBounds(c) {
+		return errors.New(errors.ErrOutOfBounds, fmt.Sprintf("cell %v is outside the grid", c))
+	}
+	g.blocked[c.Y*g.width+c.X] = blocked
+	return nil
+}
+
+// IsBlocked reports whether a cell is blocked
+func (g *GridGraph) IsBlocked(c Cell) bool {
+	return !g.Walkable(c)
+}
+
+// Walkable reports whether c is an open cell of the grid
+func (g *GridGraph) Walkable(c Cell) bool {
+	return g.InBounds(c) && !g.blocked[c.Y*g.width+c.X]
+}
+
+func (g *GridGraph) walkable(x, y int) bool {
+	return g.Walkable(Cell{X: x, Y: y})
+}
+
+// Neighbors returns the open cells reachable from c in one move
+func (g *GridGraph) Neighbors(c Cell) []Cell {
+	var neighbors []Cell
+	for _, d := range [4]Cell{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+		if g.walkable(c.X+d.X, c.Y+d.Y) {
+			neighbors = append(neighbors, Cell{X: c.X + d.X, Y: c.Y + d.Y})
+		}
+	}
+	if g.connectivity == EightWay {
+		for _, d := range [4]Cell{{1, -1}, {1, 1}, {-1, 1}, {-1, -1}} {
+			if g.walkable(c.X+d.X, c.Y) && g.walkable(c.X, c.Y+d.Y) && g.walkable(c.X+d.X, c.Y+d.Y) {
+				neighbors = append(neighbors, Cell{X: c.X + d.X, Y: c.Y + d.Y})
+			}
+		}
+	}
+	return neighbors
+}
+
+// Distance returns the cost of the shortest path from a to b on an empty
+// grid: the Manhattan distance for FourWay and the octile distance for
+// EightWay. It is an admissible heuristic for A* on the grid.
+func (g *GridGraph) Distance(a, b Cell) float64 {
+	dx, dy := abs(a.X-b.X), abs(a.Y-b.Y)
+	if g.connectivity == FourWay {
+		return float64(dx + dy)
+	}
+	return float64(max(dx, dy)-min(dx, dy)) + math.Sqrt2*float64(min(dx, dy))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
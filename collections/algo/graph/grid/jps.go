@@ -0,0 +1,256 @@
+package grid
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/algo/graph/astar"
+	"github.com/ielm/neostd/collections/heap"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// JPS finds a shortest path from start to goal on the grid with Jump Point
+// Search: A* that, instead of adding every neighbor of a cell to the open
+// set, jumps along straight and diagonal lines until it reaches a cell where
+// an obstacle could make a different path optimal. On open grids, it
+// expands a small fraction of the cells A* would.
+//
+// The path lists every cell from start to goal. Explored counts the jump
+// points expanded, and the A* options apply to them: WithMaxIterations
+// limits their number, and WithOnExplore and WithEarlyExit see each of them.
+//
+// Example:
+//
+//	result := grid.JPS(g, grid.Cell{X: 0, Y: 0}, grid.Cell{X: 99, Y: 99})
+//	if result.IsOk() {
+//		fmt.Println(result.Unwrap().Cost, len(result.Unwrap().Path))
+//	}
+func JPS(g *GridGraph, start, goal Cell, options ...astar.AStarOption[Cell, float64]) res.Result[astar.AStarResult[Cell, float64]] {
+	if !g.Walkable(start) {
+		return res.Err[astar.AStarResult[Cell, float64]](errors.New(errors.ErrInvalidArgument, "start cell is blocked or outside the grid"))
+	}
+	if !g.Walkable(goal) {
+		return res.Err[astar.AStarResult[Cell, float64]](errors.New(errors.ErrInvalidArgument, "goal cell is blocked or outside the grid"))
+	}
+	config := astar.NewAStarConfig(options...)
+
+	s := &jpsSearch{
+		grid:   g,
+		goal:   goal,
+		gScore: make(map[Cell]float64),
+		parent: make(map[Cell]Cell),
+		closed: make(map[Cell]bool),
+		open: heap.NewMinBinaryHeap(func(a, b collections.Pair[Cell, float64]) int {
+			switch {
+			case a.Value < b.Value:
+				return -1
+			case a.Value > b.Value:
+				return 1
+			}
+			return 0
+		}),
+	}
+	s.gScore[start] = 0
+	s.open.Push(collections.Pair[Cell, float64]{Key: start, Value: g.Distance(start, goal)})
+
+	explored := 0
+	for !s.open.IsEmpty() && (config.MaxIterations() == -1 || explored < config.MaxIterations()) {
+		current := s.open.Pop().Unwrap().Key
+		if s.closed[current] {
+			continue // A stale entry, superseded by a cheaper one
+		}
+		s.closed[current] = true
+		cost := s.gScore[current]
+		explored++
+		config.OnExplore(current, cost)
+
+		if current == goal || config.EarlyExit(current, cost) {
+			return res.Ok(astar.AStarResult[Cell, float64]{
+				Path:     s.path(current),
+				Cost:     cost,
+				Explored: explored,
+			})
+		}
+
+		for _, next := range s.successors(current) {
+			if s.closed[next] {
+				continue
+			}
+			tentative := cost + g.Distance(current, next)
+			if old, ok := s.gScore[next]; !ok || tentative < old {
+				s.gScore[next] = tentative
+				s.parent[next] = current
+				s.open.Push(collections.Pair[Cell, float64]{Key: next, Value: tentative + g.Distance(next, goal)})
+			}
+		}
+	}
+
+	return res.Err[astar.AStarResult[Cell, float64]](errors.New(errors.ErrNotFound, "no path found"))
+}
+
+// jpsSearch holds the state of a JPS search
+type jpsSearch struct {
+	grid   *GridGraph
+	goal   Cell
+	open   *heap.BinaryHeap[collections.Pair[Cell, float64]]
+	gScore map[Cell]float64
+	parent map[Cell]Cell
+	closed map[Cell]bool
+}
+
+// successors returns the jump points reached from c, searching in the
+// directions left after pruning those the parent of c already covers.
+func (s *jpsSearch) successors(c Cell) []Cell {
+	var result []Cell
+	for _, d := range s.directions(c) {
+		if jp, ok := s.jump(c.X+d.X, c.Y+d.Y, d.X, d.Y); ok {
+			result = append(result, jp)
+		}
+	}
+	return result
+}
+
+// directions returns the directions worth searching from c: all of them
+// from the start, and otherwise the natural and forced neighbors given the
+// direction of travel from c's parent.
+func (s *jpsSearch) directions(c Cell) []Cell {
+	g := s.grid
+	p, ok := s.parent[c]
+	if !ok {
+		dirs := make([]Cell, 0, 8)
+		for _, n := range g.Neighbors(c) {
+			dirs = append(dirs, Cell{X: n.X - c.X, Y: n.Y - c.Y})
+		}
+		return dirs
+	}
+
+	dx, dy := sign(c.X-p.X), sign(c.Y-p.Y)
+	x, y := c.X, c.Y
+	var dirs []Cell
+	if g.connectivity == FourWay {
+		if dx != 0 {
+			dirs = append(dirs, Cell{0, -1}, Cell{0, 1}, Cell{dx, 0})
+		} else {
+			dirs = append(dirs, Cell{-1, 0}, Cell{1, 0}, Cell{0, dy})
+		}
+		return dirs
+	}
+
+	switch {
+	case dx != 0 && dy != 0:
+		dirs = append(dirs, Cell{0, dy}, Cell{dx, 0})
+		if g.walkable(x, y+dy) && g.walkable(x+dx, y) {
+			dirs = append(dirs, Cell{dx, dy})
+		}
+	case dx != 0:
+		up, down := g.walkable(x, y-1), g.walkable(x, y+1)
+		if g.walkable(x+dx, y) {
+			dirs = append(dirs, Cell{dx, 0})
+			if up {
+				dirs = append(dirs, Cell{dx, -1})
+			}
+			if down {
+				dirs = append(dirs, Cell{dx, 1})
+			}
+		}
+		if up {
+			dirs = append(dirs, Cell{0, -1})
+		}
+		if down {
+			dirs = append(dirs, Cell{0, 1})
+		}
+	default:
+		left, right := g.walkable(x-1, y), g.walkable(x+1, y)
+		if g.walkable(x, y+dy) {
+			dirs = append(dirs, Cell{0, dy})
+			if left {
+				dirs = append(dirs, Cell{-1, dy})
+			}
+			if right {
+				dirs = append(dirs, Cell{1, dy})
+			}
+		}
+		if left {
+			dirs = append(dirs, Cell{-1, 0})
+		}
+		if right {
+			dirs = append(dirs, Cell{1, 0})
+		}
+	}
+	return dirs
+}
+
+// jump moves from (x, y) in direction (dx, dy) until it reaches the goal or
+// a jump point, a cell with a neighbor that only a path through the cell
+// reaches optimally, and reports false if it hits an obstacle first.
+func (s *jpsSearch) jump(x, y, dx, dy int) (Cell, bool) {
+	g := s.grid
+	for {
+		if !g.walkable(x, y) {
+			return Cell{}, false
+		}
+		if x == s.goal.X && y == s.goal.Y {
+			return s.goal, true
+		}
+
+		switch {
+		case dx != 0 && dy != 0:
+			// A diagonal move stops where a straight jump finds something
+			if _, ok := s.jump(x+dx, y, dx, 0); ok {
+				return Cell{x, y}, true
+			}
+			if _, ok := s.jump(x, y+dy, 0, dy); ok {
+				return Cell{x, y}, true
+			}
+		case dx != 0:
+			if (g.walkable(x, y-1) && !g.walkable(x-dx, y-1)) ||
+				(g.walkable(x, y+1) && !g.walkable(x-dx, y+1)) {
+				return Cell{x, y}, true
+			}
+		default:
+			if (g.walkable(x-1, y) && !g.walkable(x-1, y-dy)) ||
+				(g.walkable(x+1, y) && !g.walkable(x+1, y-dy)) {
+				return Cell{x, y}, true
+			}
+			if g.connectivity == FourWay {
+				// Without diagonal moves, a vertical jump must also stop
+				// where a horizontal one would find something
+				if _, ok := s.jump(x+1, y, 1, 0); ok {
+					return Cell{x, y}, true
+				}
+				if _, ok := s.jump(x-1, y, -1, 0); ok {
+					return Cell{x, y}, true
+				}
+			}
+		}
+
+		if g.connectivity == EightWay && !(g.walkable(x+dx, y) && g.walkable(x, y+dy)) {
+			return Cell{}, false // Cannot cut the corner of a blocked cell
+		}
+		x, y = x+dx, y+dy
+	}
+}
+
+// path returns the cells from the start to c, filling in the cells between
+// consecutive jump points.
+func (s *jpsSearch) path(c Cell) []Cell {
+	points := []Cell{c}
+	for {
+		p, ok := s.parent[c]
+		if !ok {
+			break
+		}
+		points = append(points, p)
+		c = p
+	}
+
+	path := []Cell{points[len(points)-1]}
+	for i := len(points) - 1; i > 0; i-- {
+		from, to := points[i], points[i-1]
+		dx, dy := sign(to.X-from.X), sign(to.Y-from.Y)
+		for from != to {
+			from = Cell{from.X + dx, from.Y + dy}
+			path = append(path, from)
+		}
+	}
+	return path
+}
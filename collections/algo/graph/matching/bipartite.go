@@ -0,0 +1,82 @@
+package matching
+
+import (
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/res"
+)
+
+// Bipartition represents a two-coloring of a graph: every edge joins a
+// vertex of Left to a vertex of Right.
+type Bipartition[V comparable] struct {
+	Left  []V
+	Right []V
+}
+
+// Bipartite two-colors the given graph by breadth-first search, returning
+// its two sides, or None if the graph is not bipartite, that is, if it has a
+// cycle of odd length. Edge directions are ignored. In each connected
+// component, the first vertex returned by GetVertices goes to Left.
+//
+// Example:
+//
+//	if parts := matching.Bipartite[string, int](g); parts.IsSome() {
+//		fmt.Println(parts.Unwrap().Left, parts.Unwrap().Right)
+//	}
+func Bipartite[V comparable, E any](g graph.Graph[V, E]) res.Option[Bipartition[V]] {
+	adjacency := undirectedAdjacency(g)
+	side := make(map[V]bool) // true for Right
+	var parts Bipartition[V]
+
+	for _, root := range g.GetVertices() {
+		if _, colored := side[root]; colored {
+			continue
+		}
+		side[root] = false
+		queue := []V{root}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			if side[v] {
+				parts.Right = append(parts.Right, v)
+			} else {
+				parts.Left = append(parts.Left, v)
+			}
+			for _, w := range adjacency[v] {
+				s, colored := side[w]
+				if !colored {
+					side[w] = !side[v]
+					queue = append(queue, w)
+				} else if s == side[v] {
+					return res.None[Bipartition[V]]()
+				}
+			}
+		}
+	}
+	return res.Some(parts)
+}
+
+// IsBipartite reports whether the given graph is bipartite.
+func IsBipartite[V comparable, E any](g graph.Graph[V, E]) bool {
+	return Bipartite(g).IsSome()
+}
+
+// undirectedAdjacency returns the neighbors of each vertex, following edges
+// in both directions.
+func undirectedAdjacency[V comparable, E any](g graph.Graph[V, E]) map[V][]V {
+	adjacency := make(map[V][]V)
+	seen := make(map[[2]V]bool)
+	for _, v := range g.GetVertices() {
+		for _, w := range g.GetNeighbors(v) {
+			if seen[[2]V{v, w}] {
+				continue
+			}
+			seen[[2]V{v, w}] = true
+			seen[[2]V{w, v}] = true
+			adjacency[v] = append(adjacency[v], w)
+			if w != v {
+				adjacency[w] = append(adjacency[w], v)
+			}
+		}
+	}
+	return adjacency
+}
@@ -0,0 +1,125 @@
+package matching
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// unmatched marks a vertex without a partner
+const unmatched = -1
+
+// HopcroftKarp computes a maximum matching of the given bipartite graph
+// with the Hopcroft–Karp algorithm, in O(E·√V) time: a largest set of edges
+// no two of which share a vertex. Each matched pair has its Key in the Left
+// side of the graph's Bipartition and its Value in the Right side.
+//
+// Edge directions are ignored. If the graph is not bipartite, an
+// ErrInvalidArgument error is returned.
+//
+// Example:
+//
+//	pairs := matching.HopcroftKarp[string, int](g)
+//	for _, pair := range pairs.Unwrap() {
+//		fmt.Println(pair.Key, "-", pair.Value)
+//	}
+func HopcroftKarp[V comparable, E any](g graph.Graph[V, E]) res.Result[[]collections.Pair[V, V]] {
+	parts := Bipartite(g)
+	if parts.IsNone() {
+		return res.Err[[]collections.Pair[V, V]](errors.New(errors.ErrInvalidArgument, "graph is not bipartite"))
+	}
+	left, right := parts.Unwrap().Left, parts.Unwrap().Right
+
+	rightIndex := make(map[V]int, len(right))
+	for i, v := range right {
+		rightIndex[v] = i
+	}
+	adjacency := undirectedAdjacency(g)
+	hk := &hopcroftKarp{
+		adjacency:  make([][]int, len(left)),
+		matchLeft:  make([]int, len(left)),
+		matchRight: make([]int, len(right)),
+		dist:       make([]int, len(left)),
+	}
+	for u, v := range left {
+		for _, w := range adjacency[v] {
+			hk.adjacency[u] = append(hk.adjacency[u], rightIndex[w])
+		}
+		hk.matchLeft[u] = unmatched
+	}
+	for i := range hk.matchRight {
+		hk.matchRight[i] = unmatched
+	}
+
+	for hk.layer() {
+		for u := range left {
+			if hk.matchLeft[u] == unmatched {
+				hk.augment(u)
+			}
+		}
+	}
+
+	var pairs []collections.Pair[V, V]
+	for u, w := range hk.matchLeft {
+		if w != unmatched {
+			pairs = append(pairs, collections.Pair[V, V]{Key: left[u], Value: right[w]})
+		}
+	}
+	return res.Ok(pairs)
+}
+
+// hopcroftKarp holds the state of the algorithm over vertex indices.
+type hopcroftKarp struct {
+	adjacency  [][]int // Right neighbors of each left vertex
+	matchLeft  []int   // Partner of each left vertex
+	matchRight []int   // Partner of each right vertex
+	dist       []int   // BFS layer of each left vertex
+}
+
+// layer builds the BFS layers of alternating paths starting at the free
+// left vertices, and reports whether any of them reaches a free right
+// vertex, that is, whether the matching can still grow.
+func (hk *hopcroftKarp) layer() bool {
+	var queue []int
+	for u := range hk.matchLeft {
+		if hk.matchLeft[u] == unmatched {
+			hk.dist[u] = 0
+			queue = append(queue, u)
+		} else {
+			hk.dist[u] = unmatched
+		}
+	}
+
+	found := false
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for _, w := range hk.adjacency[u] {
+			next := hk.matchRight[w]
+			if next == unmatched {
+				found = true
+			} else if hk.dist[next] == unmatched {
+				hk.dist[next] = hk.dist[u] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return found
+}
+
+// augment searches the layers for an augmenting path from the left vertex
+// u, flipping the matching along it if one is found.
+func (hk *hopcroftKarp) augment(u int) bool {
+	for _, w := range hk.adjacency[u] {
+		next := hk.matchRight[w]
+		if next == unmatched || (hk.dist[next] == hk.dist[u]+1 && hk.augment(next)) {
+			hk.matchLeft[u] = w
+			hk.matchRight[w] = u
+			return true
+		}
+	}
+	// No augmenting path goes through u in this phase
+	hk.dist[u] = unmatched
+	return false
+}
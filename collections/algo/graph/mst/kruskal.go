@@ -0,0 +1,84 @@
+package mst
+
+import (
+	"slices"
+
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/collections/set"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// MSTResult represents a minimum spanning tree as a list of edges and their
+// total weight.
+type MSTResult[V comparable, E any] struct {
+	Edges  []graph.Edge[V, E]
+	Weight E
+}
+
+// Kruskal computes a minimum spanning tree of the given graph with
+// Kruskal's algorithm: it considers the edges in order of increasing weight
+// and keeps each edge that joins two different trees, tracking the trees
+// with a DisjointSet. It runs in O(E log E) time, which suits sparse graphs.
+//
+// If the graph is not connected, the result is a minimum spanning forest,
+// with one tree per connected component. Edge directions are ignored.
+// The total weight is summed with add, and is the zero value of E if the
+// tree has no edges.
+//
+// Example:
+//
+//	result := mst.Kruskal[string, int](g,
+//		func(a, b int) bool { return a < b },
+//		func(a, b int) int { return a + b })
+//	for _, edge := range result.Unwrap().Edges {
+//		fmt.Println(edge.Source, edge.Destination, edge.Weight)
+//	}
+func Kruskal[V comparable, E any](
+	g graph.Graph[V, E],
+	less func(E, E) bool,
+	add func(E, E) E,
+) res.Result[MSTResult[V, E]] {
+	vertices := g.GetVertices()
+	forest := set.NewDisjointSet[V]()
+	var edges []graph.Edge[V, E]
+	for _, v := range vertices {
+		forest.MakeSet(v)
+		edges = append(edges, g.GetEdges(v)...)
+	}
+
+	slices.SortStableFunc(edges, func(a, b graph.Edge[V, E]) int {
+		if less(a.Weight, b.Weight) {
+			return -1
+		}
+		if less(b.Weight, a.Weight) {
+			return 1
+		}
+		return 0
+	})
+
+	var result MSTResult[V, E]
+	for _, edge := range edges {
+		if len(result.Edges) == len(vertices)-1 {
+			break
+		}
+		connected, err := forest.Connected(edge.Source, edge.Destination)
+		if err != nil {
+			return res.Err[MSTResult[V, E]](errors.NewWithCause(errors.ErrInternal, "edge endpoint not found", err))
+		}
+		if connected {
+			continue
+		}
+		if err := forest.Union(edge.Source, edge.Destination); err != nil {
+			return res.Err[MSTResult[V, E]](errors.NewWithCause(errors.ErrInternal, "failed to merge trees", err))
+		}
+		if len(result.Edges) == 0 {
+			result.Weight = edge.Weight
+		} else {
+			result.Weight = add(result.Weight, edge.Weight)
+		}
+		result.Edges = append(result.Edges, edge)
+	}
+
+	return res.Ok(result)
+}
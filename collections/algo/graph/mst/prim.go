@@ -0,0 +1,83 @@
+package mst
+
+import (
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/collections/heap"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// PrimResult represents a minimum spanning tree as an undirected graph and
+// its total weight.
+type PrimResult[V comparable, E any] struct {
+	Tree   *graph.UGraph[V, E]
+	Weight E
+}
+
+// Prim computes a minimum spanning tree of the component of the given graph
+// that contains start, with Prim's algorithm: it grows the tree from start,
+// repeatedly adding the lightest edge that leaves it, found with a binary
+// heap. It runs in O(E log E) time and, unlike Kruskal, never sorts the
+// whole edge list, which suits dense graphs.
+//
+// Edges are followed from source to destination, so for a directed graph
+// the tree only spans the vertices reachable from start. The total weight is
+// summed with add, and is the zero value of E if the tree has no edges.
+//
+// Example:
+//
+//	result := mst.Prim[string, int](g, "a",
+//		func(a, b int) bool { return a < b },
+//		func(a, b int) int { return a + b })
+//	tree := result.Unwrap().Tree
+func Prim[V comparable, E any](
+	g graph.Graph[V, E],
+	start V,
+	less func(E, E) bool,
+	add func(E, E) E,
+) res.Result[PrimResult[V, E]] {
+	if !g.Contains(start) {
+		return res.Err[PrimResult[V, E]](errors.New(errors.ErrNotFound, "start vertex not found"))
+	}
+
+	result := PrimResult[V, E]{Tree: graph.NewUGraph[V, E](g.Comparator())}
+	result.Tree.Add(start)
+
+	frontier := heap.NewMinBinaryHeap(func(a, b graph.Edge[V, E]) int {
+		if less(a.Weight, b.Weight) {
+			return -1
+		}
+		if less(b.Weight, a.Weight) {
+			return 1
+		}
+		return 0
+	})
+	for _, edge := range g.GetEdges(start) {
+		frontier.Push(edge)
+	}
+
+	edges := 0
+	for !frontier.IsEmpty() {
+		edge := frontier.Pop().Unwrap()
+		if !result.Tree.Add(edge.Destination) {
+			continue // Both endpoints are already in the tree
+		}
+		if err := result.Tree.AddEdge(edge.Source, edge.Destination, edge.Weight); err != nil {
+			return res.Err[PrimResult[V, E]](errors.NewWithCause(errors.ErrInternal, "failed to add tree edge", err))
+		}
+		if edges == 0 {
+			result.Weight = edge.Weight
+		} else {
+			result.Weight = add(result.Weight, edge.Weight)
+		}
+		edges++
+
+		for _, next := range g.GetEdges(edge.Destination) {
+			if !result.Tree.Contains(next.Destination) {
+				frontier.Push(next)
+			}
+		}
+	}
+
+	return res.Ok(result)
+}
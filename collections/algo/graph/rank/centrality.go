@@ -0,0 +1,135 @@
+package rank
+
+import (
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/collections/maps"
+)
+
+// DegreeCentrality returns the degree of each vertex of the given graph,
+// divided by the largest possible degree, n-1. In a directed graph, the
+// degree of a vertex counts both its outgoing and its incoming edges, so
+// centralities can exceed 1.
+//
+// Example:
+//
+//	degrees := rank.DegreeCentrality[string, int](g)
+func DegreeCentrality[V comparable, E any](g graph.Graph[V, E]) *maps.HashMap[V, float64] {
+	vertices, out := indexGraph(g)
+	scores := make([]float64, len(vertices))
+	for i, neighbors := range out {
+		scores[i] += float64(len(neighbors))
+		if !graph.IsUndirected(g) {
+			for _, j := range neighbors {
+				scores[j]++
+			}
+		}
+	}
+	if n := len(vertices); n > 1 {
+		for i := range scores {
+			scores[i] /= float64(n - 1)
+		}
+	}
+	return toHashMap(g, vertices, scores)
+}
+
+// BetweennessCentrality returns, for each vertex of the given graph, the
+// fraction of shortest paths between other pairs of vertices that pass
+// through it, normalized to lie between 0 and 1. It uses Brandes' algorithm,
+// in O(V·E) time. Edge weights are ignored: path lengths count edges.
+//
+// Example:
+//
+//	bridges := rank.BetweennessCentrality[string, int](g)
+func BetweennessCentrality[V comparable, E any](g graph.Graph[V, E]) *maps.HashMap[V, float64] {
+	vertices, out := indexGraph(g)
+	n := len(vertices)
+	scores := make([]float64, n)
+
+	sigma := make([]float64, n) // Number of shortest paths from the source
+	dist := make([]int, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+	for s := 0; s < n; s++ {
+		for i := range sigma {
+			sigma[i], dist[i], delta[i] = 0, -1, 0
+			preds[i] = preds[i][:0]
+		}
+		sigma[s], dist[s] = 1, 0
+
+		// Breadth-first search, recording vertices in order of distance
+		order := []int{s}
+		for k := 0; k < len(order); k++ {
+			v := order[k]
+			for _, w := range out[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					order = append(order, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+
+		// Accumulate dependencies from the farthest vertices back
+		for k := len(order) - 1; k > 0; k-- {
+			w := order[k]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			scores[w] += delta[w]
+		}
+	}
+
+	// Each pair is counted once per direction; an undirected graph has half
+	// as many distinct pairs but counts each of them twice, so the same
+	// scale applies to both.
+	if n > 2 {
+		scale := 1 / float64((n-1)*(n-2))
+		for i := range scores {
+			scores[i] *= scale
+		}
+	}
+	return toHashMap(g, vertices, scores)
+}
+
+// ClosenessCentrality returns, for each vertex of the given graph, the
+// inverse of the mean distance from it to the vertices it can reach. In a
+// graph that is not strongly connected, this is scaled by the fraction of
+// other vertices it can reach (Wasserman and Faust), so that a vertex close
+// to a few vertices does not outrank one close to many. Edge weights are
+// ignored: distances count edges.
+//
+// Example:
+//
+//	central := rank.ClosenessCentrality[string, int](g)
+func ClosenessCentrality[V comparable, E any](g graph.Graph[V, E]) *maps.HashMap[V, float64] {
+	vertices, out := indexGraph(g)
+	n := len(vertices)
+	scores := make([]float64, n)
+
+	dist := make([]int, n)
+	for s := 0; s < n; s++ {
+		for i := range dist {
+			dist[i] = -1
+		}
+		dist[s] = 0
+		queue := []int{s}
+		total := 0
+		for k := 0; k < len(queue); k++ {
+			v := queue[k]
+			total += dist[v]
+			for _, w := range out[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+			}
+		}
+		if reached := len(queue) - 1; total > 0 {
+			scores[s] = float64(reached) / float64(total) * float64(reached) / float64(n-1)
+		}
+	}
+	return toHashMap(g, vertices, scores)
+}
@@ -0,0 +1,144 @@
+package rank
+
+import (
+	"math"
+
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/collections/maps"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// PageRankConfig holds the configuration for PageRank
+type PageRankConfig struct {
+	damping       float64
+	tolerance     float64
+	maxIterations int
+}
+
+// PageRankOption configures PageRank
+type PageRankOption func(*PageRankConfig)
+
+func defaultPageRankConfig() PageRankConfig {
+	return PageRankConfig{
+		damping:       0.85,
+		tolerance:     1e-6,
+		maxIterations: 100,
+	}
+}
+
+// WithDamping sets the probability, between 0 and 1, that the random surfer
+// follows an edge rather than jumping to a random vertex. The default is
+// 0.85.
+func WithDamping(damping float64) PageRankOption {
+	return func(c *PageRankConfig) {
+		c.damping = damping
+	}
+}
+
+// WithTolerance sets the convergence threshold: iteration stops once the
+// ranks change by less than tolerance in total. The default is 1e-6.
+func WithTolerance(tolerance float64) PageRankOption {
+	return func(c *PageRankConfig) {
+		c.tolerance = tolerance
+	}
+}
+
+// WithMaxIterations sets the maximum number of iterations. The default is
+// 100.
+func WithMaxIterations(n int) PageRankOption {
+	return func(c *PageRankConfig) {
+		c.maxIterations = n
+	}
+}
+
+// PageRank computes the PageRank of each vertex of the given graph by power
+// iteration: the probability that a random surfer, who follows a random
+// edge out of the current vertex with the damping probability and otherwise
+// jumps to a random vertex, is at the vertex. The ranks sum to 1. A vertex
+// without outgoing edges passes its rank to all vertices evenly.
+//
+// Edge weights are ignored. Iteration stops when the ranks converge or after
+// the maximum number of iterations, whichever comes first.
+//
+// Example:
+//
+//	ranks := rank.PageRank[string, int](links, rank.WithDamping(0.9)).Unwrap()
+//	score, _ := ranks.Get("home")
+func PageRank[V comparable, E any](g graph.Graph[V, E], opts ...PageRankOption) res.Result[*maps.HashMap[V, float64]] {
+	config := defaultPageRankConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.damping < 0 || config.damping > 1 || math.IsNaN(config.damping) {
+		return res.Err[*maps.HashMap[V, float64]](errors.New(errors.ErrInvalidArgument, "damping must be between 0 and 1"))
+	}
+	if config.tolerance < 0 || config.maxIterations < 0 {
+		return res.Err[*maps.HashMap[V, float64]](errors.New(errors.ErrInvalidArgument, "tolerance and max iterations must not be negative"))
+	}
+
+	vertices, out := indexGraph(g)
+	n := len(vertices)
+	ranks := make([]float64, n)
+	for i := range ranks {
+		ranks[i] = 1 / float64(n)
+	}
+
+	next := make([]float64, n)
+	for iter := 0; iter < config.maxIterations; iter++ {
+		// Rank of vertices without outgoing edges is spread over all vertices
+		dangling := 0.0
+		for i, neighbors := range out {
+			if len(neighbors) == 0 {
+				dangling += ranks[i]
+			}
+		}
+		base := (1-config.damping)/float64(n) + config.damping*dangling/float64(n)
+		for i := range next {
+			next[i] = base
+		}
+		for i, neighbors := range out {
+			share := config.damping * ranks[i] / float64(len(neighbors))
+			for _, j := range neighbors {
+				next[j] += share
+			}
+		}
+
+		delta := 0.0
+		for i := range ranks {
+			delta += math.Abs(next[i] - ranks[i])
+		}
+		ranks, next = next, ranks
+		if delta < config.tolerance {
+			break
+		}
+	}
+
+	return res.Ok(toHashMap(g, vertices, ranks))
+}
+
+// indexGraph numbers the vertices of g and returns them with the outgoing
+// neighbors of each vertex, by number.
+func indexGraph[V comparable, E any](g graph.Graph[V, E]) ([]V, [][]int) {
+	vertices := g.GetVertices()
+	index := make(map[V]int, len(vertices))
+	for i, v := range vertices {
+		index[v] = i
+	}
+	out := make([][]int, len(vertices))
+	for i, v := range vertices {
+		for _, w := range g.GetNeighbors(v) {
+			out[i] = append(out[i], index[w])
+		}
+	}
+	return vertices, out
+}
+
+// toHashMap maps each vertex to its score.
+func toHashMap[V comparable, E any](g graph.Graph[V, E], vertices []V, scores []float64) *maps.HashMap[V, float64] {
+	result := maps.NewHashMap[V, float64](g.Comparator()).Unwrap()
+	for i, v := range vertices {
+		result.Put(v, scores[i])
+	}
+	return result
+}
@@ -14,6 +14,22 @@ type Item[K any] struct {
 	value      interface{}
 	frequency  int
 	lastAccess time.Time
+	writtenAt  time.Time
+	expiresAt  time.Time // Zero if the item never expires
+	ttl        time.Duration
+	cost       int64
+	negative   bool // Records that the key was not found, rather than a value
+	refreshing bool // A background reload of the item is in flight
+}
+
+// expired reports whether the item has expired at the given time.
+func (item *Item[K]) expired(now time.Time) bool {
+	return !item.expiresAt.IsZero() && !now.Before(item.expiresAt)
+}
+
+// live reports whether the item holds a value that can be served as fresh.
+func (item *Item[K]) live(now time.Time) bool {
+	return !item.negative && !item.expired(now)
 }
 
 // OrderPolicy defines the interface for cache ordering policies
@@ -31,69 +47,310 @@ type Cache[K any] struct {
 	policy     OrderPolicy[K]
 	mutex      sync.RWMutex
 	comparator comp.Comparator[K]
+	ttl        time.Duration
+	janitor    *janitor
+	maxCost    int64
+	totalCost  int64
+	staleGrace time.Duration
+	negTTL     time.Duration
+	refreshes  sync.WaitGroup
+	closed     bool
+
+	refreshAfter  time.Duration
+	refreshLoader Loader[K]
+	scheduler     *scheduler
+}
+
+// CacheConfig holds configuration options for a Cache
+type CacheConfig struct {
+	ttl             time.Duration
+	janitorInterval time.Duration
+	maxCost         int64
+	staleGrace      time.Duration
+	negativeTTL     time.Duration
+}
+
+// CacheOption is a function type for setting Cache options
+type CacheOption func(*CacheConfig)
+
+// defaultCacheConfig returns the default configuration for a Cache
+func defaultCacheConfig() *CacheConfig {
+	return &CacheConfig{}
+}
+
+// WithTTL sets the default time-to-live of items added with Set.
+// Items expire once their TTL has passed since they were last set; a TTL of
+// zero, the default, means items never expire.
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(c *CacheConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithJanitor starts a background goroutine that removes expired items about
+// once per interval. Without it, expired items are only removed when they are
+// looked up or by RemoveExpired, and keep their slots until then.
+// Each wait is jittered by up to 10% so that many caches created together do
+// not sweep in lockstep. Call Close to stop the goroutine.
+func WithJanitor(interval time.Duration) CacheOption {
+	return func(c *CacheConfig) {
+		c.janitorInterval = interval
+	}
+}
+
+// WithMaxCost limits the total cost of the items in the cache. Items added
+// with SetWeighted declare their own cost, such as their size in bytes;
+// all other items cost 1. When an insertion would exceed the budget, items
+// are evicted by the order policy until it fits. Zero, the default, means
+// no limit.
+func WithMaxCost(maxCost int64) CacheOption {
+	return func(c *CacheConfig) {
+		c.maxCost = maxCost
+	}
 }
 
 // NewCache creates a new cache with the given capacity and order policy
+// The capacity limits the number of items; zero or less means no limit,
+// for caches bounded by WithMaxCost alone.
 // The comparator is used to compare keys in the cache, it's used by the underlying map
 // to find the item in O(1) time
-func NewCache[K any](capacity int, policy OrderPolicy[K], comparator comp.Comparator[K]) *Cache[K] {
-	return &Cache[K]{
+//
+// Example:
+//
+//	c := cache.NewCache[string](1000, cache.NewLRUPolicy[string](), comp.GenericComparator[string](),
+//		cache.WithTTL(5*time.Minute), cache.WithJanitor(time.Minute))
+//	defer c.Close()
+func NewCache[K any](capacity int, policy OrderPolicy[K], comparator comp.Comparator[K], opts ...CacheOption) *Cache[K] {
+	config := defaultCacheConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	c := &Cache[K]{
 		capacity:   capacity,
 		items:      maps.NewHashMap[K, *Item[K]](comparator).Unwrap(),
 		policy:     policy,
 		comparator: comparator,
+		ttl:        config.ttl,
+		maxCost:    config.maxCost,
+		staleGrace: config.staleGrace,
+		negTTL:     config.negativeTTL,
+	}
+	if config.janitorInterval > 0 {
+		c.janitor = startJanitor(config.janitorInterval, func() { c.RemoveExpired() })
 	}
+	return c
 }
 
-// Set adds or updates an item in the cache
+// Set adds or updates an item in the cache.
+// The item expires after the cache's default TTL, if one was set with WithTTL.
+// A new item costs 1; an existing one keeps the cost it was given.
 func (c *Cache[K]) Set(key K, value interface{}) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL adds or updates an item in the cache that expires after ttl,
+// overriding the cache's default TTL. A ttl of zero or less means the item
+// never expires.
+//
+// Example:
+//
+//	c.SetWithTTL("session", token, 30*time.Minute)
+func (c *Cache[K]) SetWithTTL(key K, value interface{}, ttl time.Duration) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	c.set(key, value, ttl, keepCost)
+}
 
-	if item, ok := c.items.Get(key); ok {
+// SetWeighted adds or updates an item in the cache with the given cost,
+// counted against the budget set by WithMaxCost. Other items are evicted by
+// the order policy until the new one fits. The item expires after the
+// cache's default TTL, if one was set.
+//
+// It returns false if the item was not stored: if its cost is negative, in
+// which case the cache is left unchanged, if its cost alone exceeds the
+// budget, in which case any previous value for key is removed, or if the
+// order policy chose to evict the item itself to make room.
+//
+// Example:
+//
+//	c := cache.NewCache[string](0, cache.NewLRUPolicy[string](), comp.GenericComparator[string](),
+//		cache.WithMaxCost(64<<20))
+//	c.SetWeighted("thumbnail", img, int64(len(img)))
+func (c *Cache[K]) SetWeighted(key K, value interface{}, cost int64) bool {
+	if cost < 0 {
+		// A negative cost would lower the total and let the cache outgrow its budget
+		return false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.set(key, value, c.ttl, cost)
+}
+
+// keepCost is passed to set by writes that declare no cost, so that an
+// existing item keeps the cost given to SetWeighted and a new one costs 1.
+const keepCost int64 = -1
+
+// set adds or updates an item; the caller must hold the write lock.
+func (c *Cache[K]) set(key K, value interface{}, ttl time.Duration, cost int64) bool {
+	item, exists := c.items.Get(key)
+	if cost == keepCost {
+		cost = 1
+		if exists {
+			cost = item.cost
+		}
+	}
+	if c.maxCost > 0 && cost > c.maxCost {
+		if exists {
+			c.removeItem(item)
+		}
+		return false
+	}
+
+	now := time.Now()
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	}
+
+	if exists {
 		item.value = value
-		item.lastAccess = time.Now()
-		item.frequency++
+		item.lastAccess = now
+		item.writtenAt = now
+		item.expiresAt = expiresAt
+		item.ttl = ttl
+		item.negative = false
+		c.totalCost += cost - item.cost
+		item.cost = cost
 		c.policy.Update(item)
-	} else {
-		if c.items.Size() >= c.capacity {
-			c.evict()
+		for c.overBudget(0) {
+			victim := c.evict()
+			if victim == nil || victim == item {
+				return victim == nil
+			}
 		}
-		item := &Item[K]{
-			key:        key,
-			value:      value,
-			frequency:  1,
-			lastAccess: time.Now(),
+		c.scheduleRefresh(item)
+		return true
+	}
+
+	if c.capacity > 0 && c.items.Size() >= c.capacity {
+		c.evict()
+	}
+	for c.overBudget(cost) {
+		if c.evict() == nil {
+			break
 		}
-		c.policy.Add(item)
-		c.items.Put(key, item)
 	}
+	item = &Item[K]{
+		key:        key,
+		value:      value,
+		frequency:  1,
+		lastAccess: now,
+		writtenAt:  now,
+		expiresAt:  expiresAt,
+		ttl:        ttl,
+		cost:       cost,
+	}
+	c.policy.Add(item)
+	c.items.Put(key, item)
+	c.totalCost += cost
+	c.scheduleRefresh(item)
+	return true
+}
+
+// overBudget reports whether adding an item of the given cost would exceed
+// the cache's maximum cost.
+func (c *Cache[K]) overBudget(cost int64) bool {
+	return c.maxCost > 0 && c.totalCost+cost > c.maxCost
 }
 
-// Get retrieves an item from the cache
+// Get retrieves an item from the cache.
+// An expired item is reported as missing, and removed unless it is within
+// the grace period set by WithStaleWhileRevalidate, during which only
+// GetOrLoad serves it. A cached "not found" result is reported as missing.
 func (c *Cache[K]) Get(key K) (interface{}, bool) {
-	c.mutex.RLock()
-	item, ok := c.items.Get(key)
-	c.mutex.RUnlock()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
+	item, ok := c.items.Get(key)
 	if !ok {
 		return nil, false
 	}
 
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	now := time.Now()
+	if c.dead(item, now) {
+		c.removeItem(item)
+		return nil, false
+	}
+	if !item.live(now) {
+		return nil, false
+	}
 
-	item.lastAccess = time.Now()
-	item.frequency++
+	item.lastAccess = now
 	c.policy.Update(item)
 	return item.value, true
 }
 
+// RemoveExpired removes every expired item from the cache, other than those
+// still within their stale grace period, and returns how many were removed.
+// The janitor started by WithJanitor calls it periodically.
+func (c *Cache[K]) RemoveExpired() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	var expired []*Item[K]
+	c.items.ForEach(func(_ K, item *Item[K]) {
+		if c.dead(item, now) {
+			expired = append(expired, item)
+		}
+	})
+	for _, item := range expired {
+		c.removeItem(item)
+	}
+	return len(expired)
+}
+
+// dead reports whether an item has expired and is past its stale grace
+// period, so that it can no longer be served and should be removed.
+func (c *Cache[K]) dead(item *Item[K], now time.Time) bool {
+	return item.expired(now.Add(-c.staleGrace))
+}
+
+// Close stops the background janitor, if any, and waits for it and for any
+// background refreshes, including those scheduled by RefreshAfterWrite, to
+// exit. The cache remains usable afterwards, with expired items removed
+// lazily and no more background refreshes: stale items are served until
+// their grace period ends, then loaded again by GetOrLoad. Close is safe to
+// call more than once.
+func (c *Cache[K]) Close() {
+	if c.janitor != nil {
+		c.janitor.stop()
+	}
+	c.mutex.Lock()
+	s := c.scheduler
+	// Later writes must not queue refreshes on the stopped scheduler, which
+	// would hold on to their items forever, and later reads must not start
+	// refreshes that the wait below could miss.
+	c.scheduler = nil
+	c.refreshAfter = 0
+	c.closed = true
+	c.mutex.Unlock()
+	if s != nil {
+		s.stop()
+	}
+	c.refreshes.Wait()
+}
+
 // evict removes the item selected by the order policy
-func (c *Cache[K]) evict() {
-	if item := c.policy.Evict(); item != nil {
+// and returns it, or nil if the policy had nothing to evict
+func (c *Cache[K]) evict() *Item[K] {
+	item := c.policy.Evict()
+	if item != nil {
 		c.items.Remove(item.key)
+		c.totalCost -= item.cost
 	}
+	return item
 }
 
 // Remove removes an item from the cache
@@ -102,11 +359,17 @@ func (c *Cache[K]) Remove(key K) {
 	defer c.mutex.Unlock()
 
 	if item, ok := c.items.Get(key); ok {
-		c.policy.Remove(item)
-		c.items.Remove(key)
+		c.removeItem(item)
 	}
 }
 
+// removeItem removes an item from both the order policy and the map
+func (c *Cache[K]) removeItem(item *Item[K]) {
+	c.policy.Remove(item)
+	c.items.Remove(item.key)
+	c.totalCost -= item.cost
+}
+
 // Clear removes all items from the cache
 func (c *Cache[K]) Clear() {
 	c.mutex.Lock()
@@ -114,24 +377,42 @@ func (c *Cache[K]) Clear() {
 
 	c.items = maps.NewHashMap[K, *Item[K]](c.comparator).Unwrap()
 	c.policy = c.createNewPolicy()
+	c.totalCost = 0
 }
 
-// Size returns the number of items in the cache
+// Size returns the number of items in the cache, including expired items
+// that have not been removed yet
 func (c *Cache[K]) Size() int {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 	return c.items.Size()
 }
 
+// Cost returns the total cost of the items in the cache
+func (c *Cache[K]) Cost() int64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.totalCost
+}
+
+// MaxCost returns the cost budget set by WithMaxCost, or zero if there is none
+func (c *Cache[K]) MaxCost() int64 {
+	return c.maxCost
+}
+
 // Update the createNewPolicy method to include the new policies
 func (c *Cache[K]) createNewPolicy() OrderPolicy[K] {
-	switch c.policy.(type) {
+	switch p := c.policy.(type) {
 	case *LRUPolicy[K]:
 		return NewLRUPolicy[K]()
 	case *LFUPolicy[K]:
 		return NewLFUPolicy[K]()
 	case *LFRUPolicy[K]:
 		return NewLFRUPolicy[K]()
+	case *SievePolicy[K]:
+		return NewSievePolicy[K]()
+	case *TwoQPolicy[K]:
+		return New2QPolicy[K](p.capacity, p.comparator)
 	default:
 		panic("Unknown policy type")
 	}
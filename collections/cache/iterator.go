@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/res"
+)
+
+// Keys returns the keys of the unexpired items in the cache, in no
+// particular order. Like the other iteration methods, it does not count as
+// an access: frequencies and recency are left untouched.
+func (c *Cache[K]) Keys() []K {
+	entries := c.snapshot()
+	keys := make([]K, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+	return keys
+}
+
+// Len returns the number of unexpired items in the cache. Unlike Size, it
+// does not count expired items that have not been removed yet, nor cached
+// "not found" results.
+func (c *Cache[K]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	now := time.Now()
+	n := 0
+	c.items.ForEach(func(_ K, item *Item[K]) {
+		if item.live(now) {
+			n++
+		}
+	})
+	return n
+}
+
+// ForEach calls f for each unexpired item in the cache, in no particular
+// order, until f returns false. It iterates over a snapshot taken when it is
+// called, so f may safely call other methods of the cache, and does not
+// count as an access to the items.
+//
+// Example:
+//
+//	c.ForEach(func(key string, value interface{}) bool {
+//		fmt.Println(key, value)
+//		return true
+//	})
+func (c *Cache[K]) ForEach(f func(key K, value interface{}) bool) {
+	for _, entry := range c.snapshot() {
+		if !f(entry.Key, entry.Value) {
+			return
+		}
+	}
+}
+
+// Iterator returns an iterator over the unexpired items in the cache, in no
+// particular order. Like ForEach, it iterates over a snapshot and does not
+// count as an access, so a background job can walk the cache, for example
+// to revalidate entries, without distorting the eviction order.
+//
+// Example:
+//
+//	it := c.Iterator()
+//	for it.HasNext() {
+//		entry := it.Next().Unwrap()
+//		revalidate(entry.Key, entry.Value)
+//	}
+func (c *Cache[K]) Iterator() collections.Iterator[collections.Pair[K, interface{}]] {
+	return &cacheIterator[K]{entries: c.snapshot()}
+}
+
+// snapshot returns the key and value of each unexpired item.
+func (c *Cache[K]) snapshot() []collections.Pair[K, interface{}] {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	now := time.Now()
+	entries := make([]collections.Pair[K, interface{}], 0, c.items.Size())
+	c.items.ForEach(func(key K, item *Item[K]) {
+		if item.live(now) {
+			entries = append(entries, collections.Pair[K, interface{}]{Key: key, Value: item.value})
+		}
+	})
+	return entries
+}
+
+// cacheIterator implements the Iterator interface over a snapshot of a Cache.
+type cacheIterator[K any] struct {
+	entries []collections.Pair[K, interface{}]
+	index   int
+}
+
+func (it *cacheIterator[K]) HasNext() bool {
+	return it.index < len(it.entries)
+}
+
+func (it *cacheIterator[K]) Next() res.Option[collections.Pair[K, interface{}]] {
+	if !it.HasNext() {
+		return res.None[collections.Pair[K, interface{}]]()
+	}
+	entry := it.entries[it.index]
+	it.index++
+	return res.Some(entry)
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// janitor runs a sweep function in the background at jittered intervals
+// until it is stopped.
+type janitor struct {
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startJanitor starts a goroutine that calls sweep about once per interval.
+func startJanitor(interval time.Duration, sweep func()) *janitor {
+	j := &janitor{
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go j.run(interval, sweep)
+	return j
+}
+
+func (j *janitor) run(interval time.Duration, sweep func()) {
+	defer close(j.done)
+	timer := time.NewTimer(jitter(interval))
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			sweep()
+			timer.Reset(jitter(interval))
+		case <-j.quit:
+			return
+		}
+	}
+}
+
+// stop signals the goroutine to exit and waits for it. It is idempotent.
+func (j *janitor) stop() {
+	j.stopOnce.Do(func() { close(j.quit) })
+	<-j.done
+}
+
+// jitter returns interval adjusted by a random amount of up to 10% either way.
+func jitter(interval time.Duration) time.Duration {
+	spread := int64(interval / 10)
+	if spread <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(2*spread+1)-spread)
+}
@@ -29,18 +29,18 @@ func (p *LFRUPolicy[K]) Update(item *Item[K]) {
 }
 
 func (p *LFRUPolicy[K]) Evict() *Item[K] {
-	lfuItem := p.lfu.Evict()
-	lruItem := p.lru.Evict()
-
-	if lfuItem == lruItem {
-		return lfuItem
+	lfuItem := p.lfu.peek()
+	lruItem := p.lru.peek()
+	if lruItem == nil {
+		return nil
 	}
 
+	// Evict the less frequently used of the two candidates, preferring the
+	// least recently used one on ties.
+	victim := lruItem
 	if lfuItem.frequency < lruItem.frequency {
-		p.lru.Add(lruItem)
-		return lfuItem
+		victim = lfuItem
 	}
-
-	p.lfu.Add(lfuItem)
-	return lruItem
+	p.Remove(victim)
+	return victim
 }
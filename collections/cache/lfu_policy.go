@@ -7,12 +7,12 @@ type LFUPolicy[K any] struct {
 	frequencies *list.LinkedList[*FrequencyNode[K]]
 	items       map[*Item[K]]*list.Node[*Item[K]]
 	freqMap     map[int]*FrequencyNode[K]
-	minFreq     int
 }
 
 type FrequencyNode[K any] struct {
 	frequency int
 	items     *list.LinkedList[*Item[K]]
+	node      *list.Node[*FrequencyNode[K]] // Position in the frequencies list
 }
 
 func NewLFUPolicy[K any]() *LFUPolicy[K] {
@@ -20,15 +20,14 @@ func NewLFUPolicy[K any]() *LFUPolicy[K] {
 		frequencies: list.NewLinkedList[*FrequencyNode[K]](),
 		items:       make(map[*Item[K]]*list.Node[*Item[K]]),
 		freqMap:     make(map[int]*FrequencyNode[K]),
-		minFreq:     0,
 	}
 }
 
 func (p *LFUPolicy[K]) Add(item *Item[K]) {
+	item.frequency = 1
 	freqNode := p.getOrCreateFrequencyNode(1)
 	itemNode := freqNode.items.AddLast(item)
 	p.items[item] = itemNode
-	p.minFreq = 1
 }
 
 func (p *LFUPolicy[K]) Remove(item *Item[K]) {
@@ -49,21 +48,22 @@ func (p *LFUPolicy[K]) Update(item *Item[K]) {
 	freqNode := p.getOrCreateFrequencyNode(item.frequency)
 	itemNode := freqNode.items.AddLast(item)
 	p.items[item] = itemNode
+}
 
-	if item.frequency-1 == p.minFreq && p.freqMap[p.minFreq] == nil {
-		p.minFreq = item.frequency
+func (p *LFUPolicy[K]) Evict() *Item[K] {
+	item := p.peek()
+	if item != nil {
+		p.Remove(item)
 	}
+	return item
 }
 
-func (p *LFUPolicy[K]) Evict() *Item[K] {
-	if p.minFreq > 0 && p.freqMap[p.minFreq] != nil {
-		freqNode := p.freqMap[p.minFreq]
-		itemNode := freqNode.items.First()
-		if itemNode != nil {
-			item := itemNode.Value()
-			p.Remove(item)
-			return item
-		}
+// peek returns the item Evict would remove, without removing it: the least
+// recently added item with the lowest frequency.
+func (p *LFUPolicy[K]) peek() *Item[K] {
+	// The frequencies list is sorted, and empty frequency nodes are removed.
+	if node := p.frequencies.First(); node != nil {
+		return node.Value().items.First().Value()
 	}
 	return nil
 }
@@ -88,15 +88,15 @@ func (p *LFUPolicy[K]) getOrCreateFrequencyNode(freq int) *FrequencyNode[K] {
 	}
 
 	if insertAfter == nil {
-		p.frequencies.AddFirst(freqNode)
+		freqNode.node = p.frequencies.AddFirst(freqNode)
 	} else {
-		p.frequencies.AddAfter(insertAfter, freqNode)
+		freqNode.node = p.frequencies.AddAfter(insertAfter, freqNode)
 	}
 
 	return freqNode
 }
 
 func (p *LFUPolicy[K]) removeFrequencyNode(freqNode *FrequencyNode[K]) {
-	p.frequencies.Remove(freqNode)
+	p.frequencies.RemoveNode(freqNode.node)
 	delete(p.freqMap, freqNode.frequency)
 }
@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/ielm/neostd/errors"
+)
+
+// Loader loads the value for a key from the underlying data source.
+// A loader reports that the key does not exist by returning an error with
+// the code errors.ErrNotFound, which GetOrLoad can cache; see
+// WithNegativeCaching.
+type Loader[K any] func(key K) (interface{}, error)
+
+// WithStaleWhileRevalidate lets GetOrLoad keep serving an item for up to
+// grace after it expires, while it reloads the item in the background, so
+// that callers never wait for the data source when a hot item expires.
+// If the reload fails, the stale value is served until the grace period
+// ends, after which GetOrLoad loads the item synchronously.
+func WithStaleWhileRevalidate(grace time.Duration) CacheOption {
+	return func(c *CacheConfig) {
+		c.staleGrace = grace
+	}
+}
+
+// WithNegativeCaching makes GetOrLoad remember, for ttl, that a loader
+// reported a key as not found, and answer later lookups of the key with a
+// not-found error without calling the loader again. This shields the data
+// source from repeated lookups of keys that do not exist.
+func WithNegativeCaching(ttl time.Duration) CacheOption {
+	return func(c *CacheConfig) {
+		c.negativeTTL = ttl
+	}
+}
+
+// GetOrLoad retrieves an item from the cache, calling loader to load and
+// cache it if it is missing or expired. It returns the loader's error, if
+// any, without caching anything, except for not-found errors when negative
+// caching is enabled.
+//
+// An item within its stale grace period is returned at once, and reloaded
+// in the background with loader; see WithStaleWhileRevalidate.
+//
+// Concurrent misses for the same key each call the loader. The loader is
+// called without holding the cache's lock, so it may use the cache.
+//
+// Example:
+//
+//	user, err := c.GetOrLoad("alice", func(key string) (interface{}, error) {
+//		return db.FindUser(key)
+//	})
+func (c *Cache[K]) GetOrLoad(key K, loader Loader[K]) (interface{}, error) {
+	c.mutex.Lock()
+	if item, ok := c.items.Get(key); ok {
+		now := time.Now()
+		switch {
+		case c.dead(item, now):
+			c.removeItem(item)
+		case item.live(now):
+			item.lastAccess = now
+			c.policy.Update(item)
+			value := item.value
+			c.mutex.Unlock()
+			return value, nil
+		case item.negative && !item.expired(now):
+			c.mutex.Unlock()
+			return nil, errors.New(errors.ErrNotFound, "key not found")
+		case !item.negative:
+			// Stale but within the grace period
+			item.lastAccess = now
+			c.policy.Update(item)
+			if !item.refreshing {
+				c.refresh(item, loader)
+			}
+			value := item.value
+			c.mutex.Unlock()
+			return value, nil
+		}
+	}
+	c.mutex.Unlock()
+
+	value, err := loader(key)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err != nil {
+		if errors.Is(err, errors.NotFound) && c.negTTL > 0 {
+			c.setNegative(key)
+		}
+		return nil, err
+	}
+	c.set(key, value, c.ttl, keepCost)
+	return value, nil
+}
+
+// refresh reloads item in the background, unless the cache was closed; the
+// caller must hold the write lock. The result replaces the item only if it is
+// still in the cache.
+func (c *Cache[K]) refresh(item *Item[K], loader Loader[K]) {
+	if c.closed {
+		return
+	}
+	item.refreshing = true
+	c.refreshes.Add(1)
+	go func() {
+		defer c.refreshes.Done()
+		value, err := loader(item.key)
+
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		item.refreshing = false
+		if current, ok := c.items.Get(item.key); !ok || current != item {
+			return
+		}
+		switch {
+		case err == nil:
+			c.set(item.key, value, item.ttl, item.cost)
+		case errors.Is(err, errors.NotFound) && c.negTTL > 0:
+			c.setNegative(item.key)
+		case errors.Is(err, errors.NotFound):
+			c.removeItem(item)
+		}
+		// On other errors, keep serving the stale value until its grace ends
+	}()
+}
+
+// setNegative caches a not-found result for key; the caller must hold the
+// write lock.
+func (c *Cache[K]) setNegative(key K) {
+	if c.set(key, nil, c.negTTL, 1) {
+		if item, ok := c.items.Get(key); ok {
+			item.negative = true
+		}
+	}
+}
+
+// RefreshAfterWrite makes the cache reload items in the background, with
+// loader, once d has passed since they were written. Only items that were
+// read since they were last written are reloaded; others are left to expire.
+// With d shorter than the TTL, hot items are replaced before they expire,
+// so readers never wait for the data source and tail latencies stay flat.
+//
+// It applies to items written after it is called, and starts a scheduler
+// goroutine that Close stops. It has no effect on a closed cache.
+//
+// Example:
+//
+//	c := cache.NewCache[string](1000, cache.NewLRUPolicy[string](), comp.GenericComparator[string](),
+//		cache.WithTTL(10*time.Minute))
+//	c.RefreshAfterWrite(8*time.Minute, loadUser)
+//	defer c.Close()
+func (c *Cache[K]) RefreshAfterWrite(d time.Duration, loader Loader[K]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return
+	}
+	c.refreshAfter = d
+	c.refreshLoader = loader
+	if c.scheduler == nil && d > 0 {
+		c.scheduler = startScheduler()
+	}
+}
+
+// scheduleRefresh schedules a refresh-ahead check for item, if enabled; the
+// caller must hold the write lock.
+func (c *Cache[K]) scheduleRefresh(item *Item[K]) {
+	if c.refreshAfter <= 0 || c.scheduler == nil || item.negative {
+		return
+	}
+	c.scheduler.schedule(item.writtenAt.Add(c.refreshAfter), func() {
+		c.refreshIfDue(item)
+	})
+}
+
+// refreshIfDue starts a background reload of item if it is still cached,
+// was not rewritten since the check was scheduled, and was read since it
+// was written.
+func (c *Cache[K]) refreshIfDue(item *Item[K]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if current, ok := c.items.Get(item.key); !ok || current != item {
+		return
+	}
+	if item.negative || item.refreshing || c.refreshLoader == nil ||
+		time.Since(item.writtenAt) < c.refreshAfter || !item.lastAccess.After(item.writtenAt) {
+		return
+	}
+	c.refresh(item, c.refreshLoader)
+}
@@ -4,29 +4,46 @@ import "github.com/ielm/neostd/collections/list"
 
 // LRUPolicy implements the Least Recently Used order policy
 type LRUPolicy[K any] struct {
-	list *list.LinkedList[*Item[K]]
+	list  *list.LinkedList[*Item[K]]
+	nodes map[*Item[K]]*list.Node[*Item[K]]
 }
 
 func NewLRUPolicy[K any]() *LRUPolicy[K] {
-	return &LRUPolicy[K]{list: list.NewLinkedList[*Item[K]]()}
+	return &LRUPolicy[K]{
+		list:  list.NewLinkedList[*Item[K]](),
+		nodes: make(map[*Item[K]]*list.Node[*Item[K]]),
+	}
 }
 
 func (p *LRUPolicy[K]) Add(item *Item[K]) {
-	p.list.AddFirst(item)
+	p.nodes[item] = p.list.AddFirst(item)
 }
 
 func (p *LRUPolicy[K]) Remove(item *Item[K]) {
-	p.list.Remove(item)
+	if node, ok := p.nodes[item]; ok {
+		p.list.RemoveNode(node)
+		delete(p.nodes, item)
+	}
 }
 
 func (p *LRUPolicy[K]) Update(item *Item[K]) {
-	p.list.MoveToFront(item)
+	if node, ok := p.nodes[item]; ok {
+		p.list.MoveNodeToFront(node)
+	}
 }
 
 func (p *LRUPolicy[K]) Evict() *Item[K] {
-	if p.list.IsEmpty() {
-		return nil
+	item := p.peek()
+	if item != nil {
+		p.Remove(item)
 	}
-	item, _ := p.list.RemoveLast()
 	return item
 }
+
+// peek returns the item Evict would remove, without removing it.
+func (p *LRUPolicy[K]) peek() *Item[K] {
+	if node := p.list.Last(); node != nil {
+		return node.Value()
+	}
+	return nil
+}
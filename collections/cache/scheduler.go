@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ielm/neostd/collections/heap"
+)
+
+// task is a function scheduled to run at a given time.
+type task struct {
+	at  time.Time
+	run func()
+}
+
+// scheduler runs tasks at their scheduled times on a single background
+// goroutine, in order of time. Tasks run one at a time, so a task should not
+// block for long; anything slow should start its own goroutine.
+type scheduler struct {
+	mu       sync.Mutex
+	tasks    *heap.BinaryHeap[task]
+	wake     chan struct{}
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// startScheduler starts a scheduler with no tasks.
+func startScheduler() *scheduler {
+	s := &scheduler{
+		tasks: heap.NewMinBinaryHeap(func(a, b task) int {
+			return a.at.Compare(b.at)
+		}),
+		wake: make(chan struct{}, 1),
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// schedule arranges for fn to run at the given time, or as soon as possible
+// if that time has passed.
+func (s *scheduler) schedule(at time.Time, fn func()) {
+	s.mu.Lock()
+	s.tasks.Push(task{at: at, run: fn})
+	s.mu.Unlock()
+
+	// Let the loop recompute its wait in case the new task is the earliest.
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *scheduler) loop() {
+	defer close(s.done)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		for _, t := range s.due(time.Now()) {
+			t.run()
+		}
+
+		s.mu.Lock()
+		wait := time.Hour
+		if next := s.tasks.Peek(); next.IsSome() {
+			wait = time.Until(next.Unwrap().at)
+		}
+		s.mu.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-timer.C:
+		case <-s.wake:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// due removes and returns the tasks scheduled at or before now.
+func (s *scheduler) due(now time.Time) []task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tasks []task
+	for {
+		next := s.tasks.Peek()
+		if next.IsNone() || next.Unwrap().at.After(now) {
+			return tasks
+		}
+		tasks = append(tasks, s.tasks.Pop().Unwrap())
+	}
+}
+
+// stop discards pending tasks, stops the goroutine and waits for it to exit.
+// It is idempotent.
+func (s *scheduler) stop() {
+	s.stopOnce.Do(func() { close(s.quit) })
+	<-s.done
+}
@@ -0,0 +1,79 @@
+package cache
+
+import "github.com/ielm/neostd/collections/list"
+
+// SievePolicy implements the SIEVE order policy.
+//
+// Items are kept in insertion order, newest first, each with a visited bit
+// that a hit sets. To evict, a hand sweeps from the oldest item towards the
+// newest, clearing visited bits, and evicts the first item it finds
+// unvisited; the hand stays where it stopped for the next eviction and wraps
+// around at the newest item.
+//
+// Unlike LRU, a hit only sets a bit and never reorders the list, so reads
+// are cheap, and items that are not revisited soon after insertion are
+// evicted quickly, which suits read-heavy workloads with many one-hit items.
+//
+// Example:
+//
+//	c := cache.NewCache[string](1000, cache.NewSievePolicy[string](), comp.GenericComparator[string]())
+type SievePolicy[K any] struct {
+	list    *list.LinkedList[*Item[K]]
+	nodes   map[*Item[K]]*list.Node[*Item[K]]
+	visited map[*Item[K]]bool
+	hand    *list.Node[*Item[K]]
+}
+
+func NewSievePolicy[K any]() *SievePolicy[K] {
+	return &SievePolicy[K]{
+		list:    list.NewLinkedList[*Item[K]](),
+		nodes:   make(map[*Item[K]]*list.Node[*Item[K]]),
+		visited: make(map[*Item[K]]bool),
+	}
+}
+
+func (p *SievePolicy[K]) Add(item *Item[K]) {
+	p.nodes[item] = p.list.AddFirst(item)
+	p.visited[item] = false
+}
+
+func (p *SievePolicy[K]) Remove(item *Item[K]) {
+	node, ok := p.nodes[item]
+	if !ok {
+		return
+	}
+	if p.hand == node {
+		p.hand = node.Prev()
+	}
+	p.list.RemoveNode(node)
+	delete(p.nodes, item)
+	delete(p.visited, item)
+}
+
+func (p *SievePolicy[K]) Update(item *Item[K]) {
+	if _, ok := p.nodes[item]; ok {
+		p.visited[item] = true
+	}
+}
+
+func (p *SievePolicy[K]) Evict() *Item[K] {
+	if p.list.IsEmpty() {
+		return nil
+	}
+	node := p.hand
+	for {
+		if node == nil {
+			node = p.list.Last()
+		}
+		item := node.Value()
+		if !p.visited[item] {
+			break
+		}
+		p.visited[item] = false
+		node = node.Prev()
+	}
+	item := node.Value()
+	p.hand = node
+	p.Remove(item)
+	return item
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/collections/list"
+	"github.com/ielm/neostd/collections/maps"
+)
+
+// TwoQPolicy implements the 2Q order policy of Johnson and Shasha.
+//
+// New items enter A1in, a FIFO queue holding about a quarter of the cache.
+// Items evicted from A1in leave their key behind in A1out, a ghost queue
+// that remembers recently evicted keys without their values. A key that is
+// added again while it is still in A1out has proven it is reused, and goes
+// to Am, an LRU queue holding the rest of the cache.
+//
+// Items seen only once, such as those read by a one-shot scan, pass through
+// A1in and are evicted without disturbing the hot items in Am.
+//
+// Example:
+//
+//	policy := cache.New2QPolicy[string](1000, comp.GenericComparator[string]())
+//	c := cache.NewCache[string](1000, policy, comp.GenericComparator[string]())
+type TwoQPolicy[K any] struct {
+	capacity   int
+	comparator comp.Comparator[K]
+	kin        int // Target size of A1in
+	kout       int // Maximum size of A1out
+	a1in       *list.LinkedList[*Item[K]]
+	am         *list.LinkedList[*Item[K]]
+	a1out      *list.LinkedList[K]
+	ghosts     *maps.HashMap[K, *list.Node[K]]
+	nodes      map[*Item[K]]*list.Node[*Item[K]]
+	hot        map[*Item[K]]bool // Whether each item is in Am rather than A1in
+}
+
+// New2QPolicy creates a new 2Q policy for a cache of the given capacity.
+// The comparator is used to look up keys in the A1out ghost queue, and
+// should be the one the cache uses.
+func New2QPolicy[K any](capacity int, comparator comp.Comparator[K]) *TwoQPolicy[K] {
+	return &TwoQPolicy[K]{
+		capacity:   capacity,
+		comparator: comparator,
+		kin:        max(capacity/4, 1),
+		kout:       max(capacity/2, 1),
+		a1in:       list.NewLinkedList[*Item[K]](),
+		am:         list.NewLinkedList[*Item[K]](),
+		a1out:      list.NewLinkedList[K](),
+		ghosts:     maps.NewHashMap[K, *list.Node[K]](comparator).Unwrap(),
+		nodes:      make(map[*Item[K]]*list.Node[*Item[K]]),
+		hot:        make(map[*Item[K]]bool),
+	}
+}
+
+func (p *TwoQPolicy[K]) Add(item *Item[K]) {
+	if ghost, ok := p.ghosts.Remove(item.key); ok {
+		p.a1out.RemoveNode(ghost)
+		p.nodes[item] = p.am.AddFirst(item)
+		p.hot[item] = true
+		return
+	}
+	p.nodes[item] = p.a1in.AddFirst(item)
+	p.hot[item] = false
+}
+
+func (p *TwoQPolicy[K]) Remove(item *Item[K]) {
+	node, ok := p.nodes[item]
+	if !ok {
+		return
+	}
+	if p.hot[item] {
+		p.am.RemoveNode(node)
+	} else {
+		p.a1in.RemoveNode(node)
+	}
+	delete(p.nodes, item)
+	delete(p.hot, item)
+}
+
+// Update records a hit. Items in Am move to its front; items in A1in stay
+// where they are, since repeated hits shortly after insertion are usually
+// part of the same burst of accesses.
+func (p *TwoQPolicy[K]) Update(item *Item[K]) {
+	if node, ok := p.nodes[item]; ok && p.hot[item] {
+		p.am.MoveNodeToFront(node)
+	}
+}
+
+// Evict removes the oldest item of A1in while A1in is over its target size,
+// remembering its key in A1out, and otherwise the least recently used item
+// of Am.
+func (p *TwoQPolicy[K]) Evict() *Item[K] {
+	if p.a1in.Size() > p.kin || (p.am.IsEmpty() && !p.a1in.IsEmpty()) {
+		item := p.a1in.Last().Value()
+		p.Remove(item)
+		p.remember(item.key)
+		return item
+	}
+	if p.am.IsEmpty() {
+		return nil
+	}
+	item := p.am.Last().Value()
+	p.Remove(item)
+	return item
+}
+
+// remember adds key to A1out, forgetting the oldest key if A1out is full.
+func (p *TwoQPolicy[K]) remember(key K) {
+	if p.a1out.Size() >= p.kout {
+		oldest, _ := p.a1out.RemoveLast()
+		p.ghosts.Remove(oldest)
+	}
+	p.ghosts.Put(key, p.a1out.AddFirst(key))
+}
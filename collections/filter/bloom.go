@@ -1,7 +1,7 @@
 package filter
 
 import (
-	"encoding/binary"
+	"io"
 	"math"
 	"math/bits"
 
@@ -119,8 +119,17 @@ func (bf *BloomFilter) Clear() {
 //	count := bf.EstimateElementCount()
 //	fmt.Printf("Estimated number of elements: %d\n", count)
 func (bf *BloomFilter) EstimateElementCount() uint64 {
-	setBits := bf.countSetBits()
-	return uint64(-(float64(bf.size) / float64(bf.hashCount)) * math.Log(1-float64(setBits)/float64(bf.size)))
+	return uint64(bf.estimateCount(bf.countSetBits()))
+}
+
+// estimateCount estimates how many elements were added to a filter with this
+// filter's parameters, given how many of its bits are set. A saturated
+// filter, whose count cannot be estimated, reports its full bit size.
+func (bf *BloomFilter) estimateCount(setBits uint64) float64 {
+	if setBits >= bf.size {
+		return float64(bf.size)
+	}
+	return -(float64(bf.size) / float64(bf.hashCount)) * math.Log(1-float64(setBits)/float64(bf.size))
 }
 
 // FalsePositiveRate calculates the current false positive rate of the Bloom filter.
@@ -135,10 +144,18 @@ func (bf *BloomFilter) FalsePositiveRate() float64 {
 }
 
 func (bf *BloomFilter) hashValues(data []byte) (uint64, uint64) {
-	bf.hasher.Reset()
-	bf.hasher.Write(data)
-	h := bf.hasher.Sum(nil)
-	return binary.LittleEndian.Uint64(h[:8]), binary.LittleEndian.Uint64(h[8:16])
+	return bloomHashes(bf.hasher, data)
+}
+
+// bloomHashes hashes data and returns the two base hashes used for double
+// hashing. Hashers with 128 bits of output supply both as independent
+// halves; for shorter hashes the second is derived from the first.
+func bloomHashes(hasher hash.Hasher, data []byte) (uint64, uint64) {
+	h1, h2, ok := hash.Sum128(hasher, data)
+	if !ok {
+		h2 = mix64(h1)
+	}
+	return h1, h2
 }
 
 // index calculates the bit index for the i-th hash function.
@@ -176,7 +193,7 @@ func optimalHashCount(size uint64, n int) uint64 {
 }
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
-// It serializes the Bloom filter into a binary format.
+// It serializes the Bloom filter into the format written by WriteTo.
 //
 // Example:
 //
@@ -185,17 +202,11 @@ func optimalHashCount(size uint64, n int) uint64 {
 //		log.Fatal(err)
 //	}
 func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
-	data := make([]byte, 16+len(bf.bitset)*8)
-	binary.LittleEndian.PutUint64(data[0:8], bf.size)
-	binary.LittleEndian.PutUint64(data[8:16], bf.hashCount)
-	for i, v := range bf.bitset {
-		binary.LittleEndian.PutUint64(data[16+i*8:], v)
-	}
-	return data, nil
+	return marshal(bf)
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
-// It deserializes the Bloom filter from a binary format.
+// It deserializes a Bloom filter written by MarshalBinary or WriteTo.
 //
 // Example:
 //
@@ -204,18 +215,66 @@ func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
 //		log.Fatal(err)
 //	}
 func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
-	if len(data) < 16 {
-		return errors.New(errors.ErrInvalidArgument, "invalid data length")
+	return unmarshal(bf, data)
+}
+
+// WriteTo implements the io.WriterTo interface.
+// It streams the Bloom filter to w without copying its bit array, so large
+// filters can be saved directly to a file. The encoding starts with a magic
+// number and format version, and records the hasher's key so that a decoded
+// filter hashes elements exactly as the original did.
+//
+// Example:
+//
+//	f, _ := os.Create("users.bloom")
+//	defer f.Close()
+//	if _, err := bf.WriteTo(bufio.NewWriter(f)); err != nil {
+//		log.Fatal(err)
+//	}
+func (bf *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	e := &encoder{w: w}
+	e.header(bloomMagic, bf.hasher)
+	e.u64(bf.size)
+	e.u64(bf.hashCount)
+	e.words(bf.bitset)
+	return e.n, e.err
+}
+
+// ReadFrom implements the io.ReaderFrom interface.
+// It reads a Bloom filter written by WriteTo or MarshalBinary, replacing the
+// contents of bf. It restores SipHasher and TigerHasher from the encoding;
+// a filter that used any other hasher must be decoded into a filter
+// constructed with that same hasher. On error, bf is left unchanged.
+//
+// Example:
+//
+//	var bf BloomFilter
+//	if _, err := bf.ReadFrom(bufio.NewReader(f)); err != nil {
+//		log.Fatal(err)
+//	}
+func (bf *BloomFilter) ReadFrom(r io.Reader) (int64, error) {
+	d := &decoder{r: r}
+	hasher, err := d.header(bloomMagic, bf.hasher)
+	if err != nil {
+		return d.n, err
 	}
-	bf.size = binary.LittleEndian.Uint64(data[0:8])
-	bf.hashCount = binary.LittleEndian.Uint64(data[8:16])
-	bf.bitset = make([]uint64, (bf.size+63)/64)
-	for i := range bf.bitset {
-		bf.bitset[i] = binary.LittleEndian.Uint64(data[16+i*8:])
+	size := d.u64()
+	hashCount := d.u64()
+	if d.err != nil {
+		return d.n, d.err
 	}
-	var err error
-	bf.hasher, err = hash.NewSipHasher()
-	return err
+	if size == 0 || size > math.MaxUint64-63 || hashCount == 0 {
+		return d.n, errors.New(errors.ErrInvalidArgument, "invalid bloom filter parameters")
+	}
+	bitset := d.words((size + 63) / 64)
+	if d.err != nil {
+		return d.n, d.err
+	}
+	bf.bitset = bitset
+	bf.size = size
+	bf.hashCount = hashCount
+	bf.hasher = hasher
+	return d.n, nil
 }
 
 // Size returns the current number of elements in the Bloom filter.
@@ -250,7 +309,8 @@ func (bf *BloomFilter) Capacity() int {
 	return int(float64(bf.size) * math.Log(2) / float64(bf.hashCount))
 }
 
-// Merge combines this Bloom filter with another one of the same size and hash count.
+// Merge combines this Bloom filter with another one of the same size and hash
+// count, built with the same hasher and key.
 //
 // Example:
 //
@@ -262,12 +322,68 @@ func (bf *BloomFilter) Merge(other *BloomFilter) error {
 	if bf.size != other.size || bf.hashCount != other.hashCount {
 		return errors.New(errors.ErrInvalidArgument, "bloom filters must have the same size and hash count to merge")
 	}
+	if !sameHasher(bf.hasher, other.hasher) {
+		return errors.New(errors.ErrInvalidArgument, "bloom filters must use the same hasher and key to merge")
+	}
 	for i := range bf.bitset {
 		bf.bitset[i] |= other.bitset[i]
 	}
 	return nil
 }
 
+// Intersect keeps only the bits set in both this Bloom filter and another one
+// of the same size, hash count and hasher. Every element added to both filters is
+// still reported as present afterwards, but the false positive rate is higher
+// than that of a filter built from the intersection directly, since bits set
+// by different elements in each filter can coincide.
+//
+// Example:
+//
+//	err := bf1.Intersect(bf2)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (bf *BloomFilter) Intersect(other *BloomFilter) error {
+	if bf.size != other.size || bf.hashCount != other.hashCount {
+		return errors.New(errors.ErrInvalidArgument, "bloom filters must have the same size and hash count to intersect")
+	}
+	if !sameHasher(bf.hasher, other.hasher) {
+		return errors.New(errors.ErrInvalidArgument, "bloom filters must use the same hasher and key to intersect")
+	}
+	for i := range bf.bitset {
+		bf.bitset[i] &= other.bitset[i]
+	}
+	return nil
+}
+
+// EstimateOverlap estimates the number of elements added to both this Bloom
+// filter and another one of the same size, hash count and hasher, without
+// modifying either. It estimates the sizes of each set and of their union from
+// the number of set bits, and applies inclusion-exclusion:
+// |A ∩ B| = |A| + |B| - |A ∪ B|.
+//
+// Example:
+//
+//	overlap, err := bf1.EstimateOverlap(bf2)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("About %d shared elements\n", overlap)
+func (bf *BloomFilter) EstimateOverlap(other *BloomFilter) (uint64, error) {
+	if bf.size != other.size || bf.hashCount != other.hashCount {
+		return 0, errors.New(errors.ErrInvalidArgument, "bloom filters must have the same size and hash count to estimate overlap")
+	}
+	if !sameHasher(bf.hasher, other.hasher) {
+		return 0, errors.New(errors.ErrInvalidArgument, "bloom filters must use the same hasher and key to estimate overlap")
+	}
+	var unionBits uint64
+	for i, x := range bf.bitset {
+		unionBits += uint64(bits.OnesCount64(x | other.bitset[i]))
+	}
+	overlap := bf.estimateCount(bf.countSetBits()) + bf.estimateCount(other.countSetBits()) - bf.estimateCount(unionBits)
+	return uint64(math.Round(max(overlap, 0))), nil
+}
+
 // Copy creates a deep copy of the Bloom filter.
 //
 // Example:
@@ -2,7 +2,7 @@ package filter
 
 import (
 	"math/bits"
-	"unsafe"
+	"math/rand"
 )
 
 // nextPowerOfTwo calculates the next power of two for a given number.
@@ -12,5 +12,15 @@ func nextPowerOfTwo(x uint64) uint64 {
 
 // fastrand is a fast, thread-safe random number generator.
 func fastrand() uint32 {
-	return uint32(uintptr(unsafe.Pointer(new(byte))))*1664525 + 1013904223
+	return rand.Uint32()
+}
+
+// mix64 is the SplitMix64 finalizer, a bijection on uint64 with good avalanche.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x
 }
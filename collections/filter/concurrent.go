@@ -0,0 +1,396 @@
+package filter
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+)
+
+// cuckooStripes is the number of locks guarding the buckets of a ConcurrentCuckooFilter.
+const cuckooStripes = 64
+
+// cuckooStripeSpan is the number of consecutive buckets guarded by each lock.
+// It is even so that stripe boundaries fall on byte boundaries for every
+// supported fingerprint length, and no byte is shared between two stripes.
+const cuckooStripeSpan = 8
+
+// hasherPool hands out hashers to concurrent callers, since a hash.Hasher
+// holds per-call state and cannot be shared.
+type hasherPool struct {
+	pool sync.Pool
+}
+
+func newHasherPool(newHasher func() hash.Hasher) *hasherPool {
+	return &hasherPool{pool: sync.Pool{New: func() any { return newHasher() }}}
+}
+
+func (p *hasherPool) get() hash.Hasher {
+	return p.pool.Get().(hash.Hasher)
+}
+
+func (p *hasherPool) put(h hash.Hasher) {
+	p.pool.Put(h)
+}
+
+// sipHasherFactory returns a function creating SipHashers that share one
+// random key, so that every hasher it creates agrees on every input.
+func sipHasherFactory() (func() hash.Hasher, error) {
+	base, err := hash.NewSipHasher()
+	if err != nil {
+		return nil, errors.New(errors.ErrConstructionFailed, "failed to create default hasher")
+	}
+	return func() hash.Hasher { return base.Clone() }, nil
+}
+
+// ConcurrentBloomFilter is a Bloom filter that is safe for concurrent use.
+// Bits are set and tested with atomic operations on the filter's words, so
+// Add and Contains never block each other.
+//
+// Example:
+//
+//	bf, _ := NewConcurrentBloomFilter(1000, 0.01)
+//	var wg sync.WaitGroup
+//	for _, item := range items {
+//		wg.Add(1)
+//		go func(item []byte) {
+//			defer wg.Done()
+//			bf.Add(item)
+//		}(item)
+//	}
+//	wg.Wait()
+type ConcurrentBloomFilter struct {
+	filter  *BloomFilter
+	hashers *hasherPool
+}
+
+// NewConcurrentBloomFilter creates a new concurrent Bloom filter with the given
+// expected number of elements and desired false positive rate.
+//
+// Example:
+//
+//	bf, err := NewConcurrentBloomFilter(1000, 0.01)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewConcurrentBloomFilter(expectedElements int, falsePositiveRate float64) (*ConcurrentBloomFilter, error) {
+	newHasher, err := sipHasherFactory()
+	if err != nil {
+		return nil, err
+	}
+	return NewConcurrentBloomFilterWithHasher(expectedElements, falsePositiveRate, newHasher)
+}
+
+// NewConcurrentBloomFilterWithHasher creates a new concurrent Bloom filter with
+// the given expected number of elements and desired false positive rate.
+// newHasher is called to create a hasher for each concurrent caller; every
+// hasher it returns must produce the same hash for the same input.
+//
+// Example:
+//
+//	bf, err := NewConcurrentBloomFilterWithHasher(1000, 0.01, func() hash.Hasher {
+//		return hash.NewTigerHasher()
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewConcurrentBloomFilterWithHasher(expectedElements int, falsePositiveRate float64, newHasher func() hash.Hasher) (*ConcurrentBloomFilter, error) {
+	filter, err := NewBloomFilterWithHasher(expectedElements, falsePositiveRate, newHasher())
+	if err != nil {
+		return nil, err
+	}
+	return &ConcurrentBloomFilter{filter: filter, hashers: newHasherPool(newHasher)}, nil
+}
+
+// Add inserts an element into the Bloom filter.
+// It returns true if the element was not present before, false otherwise.
+// When the same new element is added concurrently, more than one call may return true.
+func (c *ConcurrentBloomFilter) Add(data []byte) bool {
+	h1, h2 := c.hashValues(data)
+	bf := c.filter
+	added := false
+	for i := uint64(0); i < bf.hashCount; i++ {
+		index := bf.index(h1, h2, i)
+		word, mask := &bf.bitset[index/64], uint64(1)<<(index%64)
+		for {
+			old := atomic.LoadUint64(word)
+			if old&mask != 0 {
+				break
+			}
+			if atomic.CompareAndSwapUint64(word, old, old|mask) {
+				added = true
+				break
+			}
+		}
+	}
+	return added
+}
+
+// Contains checks if an element might be in the Bloom filter.
+func (c *ConcurrentBloomFilter) Contains(data []byte) bool {
+	h1, h2 := c.hashValues(data)
+	bf := c.filter
+	for i := uint64(0); i < bf.hashCount; i++ {
+		index := bf.index(h1, h2, i)
+		if atomic.LoadUint64(&bf.bitset[index/64])&(1<<(index%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear removes all elements from the Bloom filter.
+// Elements added concurrently with Clear may or may not be kept.
+func (c *ConcurrentBloomFilter) Clear() {
+	for i := range c.filter.bitset {
+		atomic.StoreUint64(&c.filter.bitset[i], 0)
+	}
+}
+
+// EstimateElementCount estimates the number of elements in the Bloom filter.
+func (c *ConcurrentBloomFilter) EstimateElementCount() uint64 {
+	return c.Snapshot().EstimateElementCount()
+}
+
+// Size returns the estimated number of elements in the Bloom filter.
+func (c *ConcurrentBloomFilter) Size() int {
+	return int(c.EstimateElementCount())
+}
+
+// IsEmpty returns true if the Bloom filter contains no elements.
+func (c *ConcurrentBloomFilter) IsEmpty() bool {
+	for i := range c.filter.bitset {
+		if atomic.LoadUint64(&c.filter.bitset[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Snapshot returns a copy of the filter as a plain BloomFilter, for example
+// to serialize or merge it. Each word is read atomically, but elements added
+// concurrently may be only partially reflected.
+func (c *ConcurrentBloomFilter) Snapshot() *BloomFilter {
+	bf := c.filter
+	snapshot := &BloomFilter{
+		bitset:    make([]uint64, len(bf.bitset)),
+		size:      bf.size,
+		hashCount: bf.hashCount,
+		hasher:    bf.hasher,
+	}
+	for i := range bf.bitset {
+		snapshot.bitset[i] = atomic.LoadUint64(&bf.bitset[i])
+	}
+	return snapshot
+}
+
+func (c *ConcurrentBloomFilter) hashValues(data []byte) (uint64, uint64) {
+	hasher := c.hashers.get()
+	defer c.hashers.put(hasher)
+	return bloomHashes(hasher, data)
+}
+
+// ConcurrentCuckooFilter is a Cuckoo filter that is safe for concurrent use.
+//
+// Buckets are guarded by a fixed set of striped locks, so operations that
+// touch different buckets proceed in parallel. Most insertions only need the
+// two candidate buckets of the new element. Insertions that must relocate
+// existing fingerprints, or that use the stash or grow the filter, briefly
+// take exclusive access to the whole filter, so that a fingerprint in transit
+// is never missed by a concurrent lookup.
+//
+// Example:
+//
+//	cf, _ := NewConcurrentCuckooFilter(1000, 0.01, WithAutoResize(true))
+//	go cf.Add([]byte("a"))
+//	go cf.Add([]byte("b"))
+type ConcurrentCuckooFilter struct {
+	mu      sync.RWMutex // Held exclusively to relocate, stash, grow or clear
+	stripes [cuckooStripes]sync.Mutex
+	filter  *CuckooFilter
+	hashers *hasherPool
+}
+
+// NewConcurrentCuckooFilter creates a new concurrent Cuckoo filter with the
+// given expected number of elements, desired false positive rate and options.
+//
+// Example:
+//
+//	cf, err := NewConcurrentCuckooFilter(1000, 0.01)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewConcurrentCuckooFilter(expectedElements int, falsePositiveRate float64, opts ...CuckooOption) (*ConcurrentCuckooFilter, error) {
+	newHasher, err := sipHasherFactory()
+	if err != nil {
+		return nil, err
+	}
+	return NewConcurrentCuckooFilterWithHasher(expectedElements, falsePositiveRate, newHasher, opts...)
+}
+
+// NewConcurrentCuckooFilterWithHasher creates a new concurrent Cuckoo filter
+// with the given expected number of elements, desired false positive rate and
+// options. newHasher is called to create a hasher for each concurrent caller;
+// every hasher it returns must produce the same hash for the same input.
+func NewConcurrentCuckooFilterWithHasher(expectedElements int, falsePositiveRate float64, newHasher func() hash.Hasher, opts ...CuckooOption) (*ConcurrentCuckooFilter, error) {
+	filter, err := NewCuckooFilterWithHasher(expectedElements, falsePositiveRate, newHasher(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ConcurrentCuckooFilter{filter: filter, hashers: newHasherPool(newHasher)}, nil
+}
+
+// Add inserts an element into the Cuckoo filter.
+// Returns true if the element was successfully inserted, false otherwise.
+func (c *ConcurrentCuckooFilter) Add(data []byte) bool {
+	h := c.hash(data)
+
+	c.mu.RLock()
+	tail := c.filter
+	for tail.next != nil {
+		tail = tail.next
+	}
+	i1, fp := tail.locate(h)
+	i2 := tail.altIndex(i1, fp)
+	unlock := c.lockBuckets(i1, i2)
+	inserted := tail.insertIntoBucket(i1, fp) || tail.insertIntoBucket(i2, fp)
+	unlock()
+	if inserted {
+		atomic.AddUint64(&tail.count, 1)
+	}
+	c.mu.RUnlock()
+	if inserted {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.filter.addHash(h)
+}
+
+// Contains checks if an element might be in the Cuckoo filter.
+func (c *ConcurrentCuckooFilter) Contains(data []byte) bool {
+	h := c.hash(data)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for f := c.filter; f != nil; f = f.next {
+		i1, fp := f.locate(h)
+		i2 := f.altIndex(i1, fp)
+		unlock := c.lockBuckets(i1, i2)
+		found := f.containsInBucket(i1, fp) || f.containsInBucket(i2, fp)
+		unlock()
+		if found || f.stashIndex(i1, i2, fp) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Remove removes an element from the Cuckoo filter.
+// Returns true if the element was successfully removed, false if it was not found.
+//
+// Like CuckooFilter.Remove, it searches the newest filter in the chain first,
+// so that a fingerprint shared with an older element is taken from the filter
+// the newer one was added to.
+func (c *ConcurrentCuckooFilter) Remove(data []byte) bool {
+	h := c.hash(data)
+
+	c.mu.RLock()
+	var chain []*CuckooFilter
+	for f := c.filter; f != nil; f = f.next {
+		chain = append(chain, f)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		f := chain[i]
+		i1, fp := f.locate(h)
+		i2 := f.altIndex(i1, fp)
+		unlock := c.lockBuckets(i1, i2)
+		removed := f.removeFromBucket(i1, fp) || f.removeFromBucket(i2, fp)
+		unlock()
+		if removed {
+			atomic.AddUint64(&f.count, ^uint64(0))
+			c.mu.RUnlock()
+			return true
+		}
+		if f.stashIndex(i1, i2, fp) >= 0 {
+			break
+		}
+	}
+	c.mu.RUnlock()
+
+	// The fingerprint may be in a stash, which is only changed exclusively.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.filter.removeHash(h)
+}
+
+// Clear removes all elements from the Cuckoo filter.
+func (c *ConcurrentCuckooFilter) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.filter.Clear()
+}
+
+// Size returns the number of items in the filter.
+func (c *ConcurrentCuckooFilter) Size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	count := 0
+	for f := c.filter; f != nil; f = f.next {
+		count += int(atomic.LoadUint64(&f.count))
+	}
+	return count
+}
+
+// IsEmpty returns true if the filter contains no elements.
+func (c *ConcurrentCuckooFilter) IsEmpty() bool {
+	return c.Size() == 0
+}
+
+// LoadFactor returns the current load factor of the filter.
+func (c *ConcurrentCuckooFilter) LoadFactor() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.filter.LoadFactor()
+}
+
+// FalsePositiveRate calculates the current false positive rate of the Cuckoo filter.
+func (c *ConcurrentCuckooFilter) FalsePositiveRate() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.filter.FalsePositiveRate()
+}
+
+func (c *ConcurrentCuckooFilter) hash(data []byte) cuckooHash {
+	hasher := c.hashers.get()
+	defer c.hashers.put(hasher)
+	return hashCuckoo(hasher, data)
+}
+
+// lockBuckets locks the stripes guarding buckets i1 and i2, in a fixed order
+// to avoid deadlock, and returns a function that unlocks them.
+func (c *ConcurrentCuckooFilter) lockBuckets(i1, i2 uint64) func() {
+	s1 := (i1 / cuckooStripeSpan) % cuckooStripes
+	s2 := (i2 / cuckooStripeSpan) % cuckooStripes
+	if s1 > s2 {
+		s1, s2 = s2, s1
+	}
+	c.stripes[s1].Lock()
+	if s1 == s2 {
+		return c.stripes[s1].Unlock
+	}
+	c.stripes[s2].Lock()
+	return func() {
+		c.stripes[s2].Unlock()
+		c.stripes[s1].Unlock()
+	}
+}
+
+// Ensure the concurrent filters implement the ProbabilisticSet interface
+var (
+	_ collections.ProbabilisticSet[[]byte] = (*ConcurrentBloomFilter)(nil)
+	_ collections.ProbabilisticSet[[]byte] = (*ConcurrentCuckooFilter)(nil)
+)
@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// TestConcurrentCuckooRemoveMatchesCuckoo checks that removing from a resized
+// concurrent filter loses no other elements, as with the plain filter.
+func TestConcurrentCuckooRemoveMatchesCuckoo(t *testing.T) {
+	const n = 40000
+	opts := []CuckooOption{WithAutoResize(true), WithFingerprintLen(8)}
+	plain, err := NewCuckooFilter(64, 0.5, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	concurrent, err := NewConcurrentCuckooFilter(64, 0.5, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := func(i int) []byte { return binary.LittleEndian.AppendUint64(nil, uint64(i)) }
+	for i := 0; i < n; i++ {
+		if !plain.Add(key(i)) || !concurrent.Add(key(i)) {
+			t.Fatalf("Add(%d) failed", i)
+		}
+	}
+	for i := 0; i < n; i += 2 {
+		plain.Remove(key(i))
+		concurrent.Remove(key(i))
+	}
+
+	var plainMissing, concurrentMissing int
+	for i := 1; i < n; i += 2 {
+		if !plain.Contains(key(i)) {
+			plainMissing++
+		}
+		if !concurrent.Contains(key(i)) {
+			concurrentMissing++
+		}
+	}
+	if plainMissing != 0 || concurrentMissing != 0 {
+		t.Errorf("false negatives after removal: plain %d, concurrent %d", plainMissing, concurrentMissing)
+	}
+}
@@ -1,9 +1,8 @@
 package filter
 
 import (
-	"encoding/binary"
+	"io"
 	"math"
-	"math/bits"
 
 	"github.com/ielm/neostd/collections"
 	"github.com/ielm/neostd/errors"
@@ -11,25 +10,95 @@ import (
 )
 
 const (
-	bucketSize     = 4   // Number of entries per bucket
-	maxKicks       = 500 // Maximum number of kicks during insertion
-	fingerprintLen = 8   // Length of fingerprint in bits
+	maxKicks  = 500 // Maximum number of kicks during insertion
+	stashSize = 4   // Maximum number of fingerprints held in the overflow stash
+
+	defaultBucketSize     = 4  // Default number of entries per bucket
+	defaultFingerprintLen = 8  // Default length of fingerprint in bits
+	maxBucketSize         = 16 // Largest supported number of entries per bucket
 )
 
 // CuckooFilter is a space-efficient probabilistic data structure for set membership testing.
 // It provides fast add, remove, and lookup operations with a controllable false positive rate.
 //
+// Each bucket holds bucketSize fingerprints of fingerprintLen bits, packed
+// back to back in a byte slice. Longer fingerprints lower the false positive
+// rate, roughly 2*bucketSize/2^fingerprintLen at full load, at the cost of
+// more memory per element; larger buckets allow a higher load factor but
+// raise the false positive rate. Both are set at construction with
+// WithFingerprintLen and WithBucketSize.
+//
 // Example:
 //
 //	cf, _ := NewCuckooFilter(1000, 0.01)
 //	cf.Add([]byte("example"))
 //	exists := cf.Contains([]byte("example")) // true
 type CuckooFilter struct {
-	buckets    []uint32 // Array of buckets, each containing 4 fingerprints
-	size       uint64   // Number of buckets
-	count      uint64   // Number of items in the filter
-	loadFactor float64  // Maximum load factor before resizing
-	hasher     hash.Hasher
+	buckets        []byte  // Packed fingerprints, bucketSize per bucket
+	size           uint64  // Number of buckets
+	count          uint64  // Number of items in the filter
+	loadFactor     float64 // Maximum load factor before resizing
+	bucketSize     uint64  // Number of entries per bucket
+	fingerprintLen uint    // Length of fingerprint in bits
+	hasher         hash.Hasher
+	stash          []stashEntry  // Fingerprints that could not be placed in a bucket
+	next           *CuckooFilter // Larger filter that receives new items once this one is full
+	autoResize     bool
+}
+
+// stashEntry is a fingerprint evicted from its buckets with nowhere to go.
+// index is one of its two candidate buckets.
+type stashEntry struct {
+	index uint64
+	fp    uint16
+}
+
+// CuckooConfig holds the configuration for a CuckooFilter.
+type CuckooConfig struct {
+	autoResize     bool
+	bucketSize     int
+	fingerprintLen int
+}
+
+// CuckooOption is a function type for configuring a CuckooFilter.
+type CuckooOption func(*CuckooConfig)
+
+// defaultCuckooConfig returns the default configuration for a CuckooFilter.
+func defaultCuckooConfig() *CuckooConfig {
+	return &CuckooConfig{
+		autoResize:     false,
+		bucketSize:     defaultBucketSize,
+		fingerprintLen: defaultFingerprintLen,
+	}
+}
+
+// WithFingerprintLen sets the length of each fingerprint in bits, which must
+// be 8, 12 or 16. Each extra bit halves the false positive rate.
+func WithFingerprintLen(bits int) CuckooOption {
+	return func(c *CuckooConfig) {
+		c.fingerprintLen = bits
+	}
+}
+
+// WithBucketSize sets the number of fingerprints per bucket, between 1 and 16.
+// The default of 4 allows a load factor of about 95%.
+func WithBucketSize(size int) CuckooOption {
+	return func(c *CuckooConfig) {
+		c.bucketSize = size
+	}
+}
+
+// WithAutoResize makes the filter grow instead of rejecting new items once
+// both its buckets and its stash are full. Because the original items are not
+// stored, fingerprints cannot be rehashed into a larger table; instead the
+// filter chains a new filter with twice as many buckets, which receives all
+// later items. Lookups and removals consult every filter in the chain, so the
+// false positive rate grows slowly with each resize. Removals start from the
+// newest filter; see Remove.
+func WithAutoResize(enabled bool) CuckooOption {
+	return func(c *CuckooConfig) {
+		c.autoResize = enabled
+	}
 }
 
 // NewCuckooFilter creates a new Cuckoo filter with the given expected number of elements
@@ -41,13 +110,13 @@ type CuckooFilter struct {
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
-func NewCuckooFilter(expectedElements int, falsePositiveRate float64) (*CuckooFilter, error) {
+func NewCuckooFilter(expectedElements int, falsePositiveRate float64, opts ...CuckooOption) (*CuckooFilter, error) {
 	hasher, err := hash.NewSipHasher()
 	if err != nil {
 		// return nil, fmt.Errorf("failed to create default hasher: %w", err)
 		return nil, errors.New(errors.ErrConstructionFailed, "failed to create default hasher")
 	}
-	return NewCuckooFilterWithHasher(expectedElements, falsePositiveRate, hasher)
+	return NewCuckooFilterWithHasher(expectedElements, falsePositiveRate, hasher, opts...)
 }
 
 // NewCuckooFilterWithHasher creates a new Cuckoo filter with the given expected number of elements,
@@ -60,7 +129,7 @@ func NewCuckooFilter(expectedElements int, falsePositiveRate float64) (*CuckooFi
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
-func NewCuckooFilterWithHasher(expectedElements int, falsePositiveRate float64, hasher hash.Hasher) (*CuckooFilter, error) {
+func NewCuckooFilterWithHasher(expectedElements int, falsePositiveRate float64, hasher hash.Hasher, opts ...CuckooOption) (*CuckooFilter, error) {
 	if expectedElements <= 0 {
 		return nil, errors.New(errors.ErrInvalidArgument, "expected elements must be positive")
 	}
@@ -68,21 +137,43 @@ func NewCuckooFilterWithHasher(expectedElements int, falsePositiveRate float64,
 		return nil, errors.New(errors.ErrInvalidArgument, "false positive rate must be between 0 and 1")
 	}
 
+	config := defaultCuckooConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	switch config.fingerprintLen {
+	case 8, 12, 16:
+	default:
+		return nil, errors.New(errors.ErrInvalidArgument, "fingerprint length must be 8, 12 or 16 bits")
+	}
+	if config.bucketSize < 1 || config.bucketSize > maxBucketSize {
+		return nil, errors.New(errors.ErrInvalidArgument, "bucket size must be between 1 and 16")
+	}
+
 	size := nextPowerOfTwo(uint64(float64(expectedElements) / falsePositiveRate))
-	buckets := make([]uint32, size)
 
-	return &CuckooFilter{
-		buckets:    buckets,
-		size:       size,
-		count:      0,
-		loadFactor: falsePositiveRate,
-		hasher:     hasher,
-	}, nil
+	cf := &CuckooFilter{
+		size:           size,
+		count:          0,
+		loadFactor:     falsePositiveRate,
+		bucketSize:     uint64(config.bucketSize),
+		fingerprintLen: uint(config.fingerprintLen),
+		hasher:         hasher,
+		autoResize:     config.autoResize,
+	}
+	cf.buckets = make([]byte, cf.tableLen())
+	return cf, nil
 }
 
 // Add inserts an element into the Cuckoo filter.
 // Returns true if the element was successfully inserted, false otherwise.
 //
+// When both candidate buckets are full, existing fingerprints are relocated
+// for up to maxKicks steps. A fingerprint still left without a bucket is kept
+// in a small overflow stash. Once the stash is full, Add either grows the
+// filter (see WithAutoResize) or returns false without modifying the filter,
+// so an insertion never evicts an element that was already present.
+//
 // Example:
 //
 //	success := cf.Add([]byte("example"))
@@ -90,8 +181,16 @@ func NewCuckooFilterWithHasher(expectedElements int, falsePositiveRate float64,
 //	    fmt.Println("Failed to insert element")
 //	}
 func (cf *CuckooFilter) Add(data []byte) bool {
-	fp := cf.fingerprint(data)
-	i1 := cf.index(data)
+	return cf.addHash(cf.hashData(data))
+}
+
+// addHash inserts the element with hash h.
+func (cf *CuckooFilter) addHash(h cuckooHash) bool {
+	if cf.next != nil {
+		return cf.next.addHash(h)
+	}
+
+	i1, fp := cf.locate(h)
 	i2 := cf.altIndex(i1, fp)
 
 	if cf.insertIntoBucket(i1, fp) || cf.insertIntoBucket(i2, fp) {
@@ -99,11 +198,21 @@ func (cf *CuckooFilter) Add(data []byte) bool {
 		return true
 	}
 
+	if len(cf.stash) >= stashSize {
+		if !cf.autoResize {
+			return false
+		}
+		cf.next = cf.grow()
+		return cf.next.addHash(h)
+	}
+
 	// Perform cuckoo hashing
 	i := i1
 	for k := 0; k < maxKicks; k++ {
-		j := uint32(fastrand() % bucketSize)
-		fp, cf.buckets[i] = extractFingerprint(cf.buckets[i], j), insertFingerprint(cf.buckets[i], j, fp)
+		slot := i*cf.bucketSize + uint64(fastrand())%cf.bucketSize
+		victim := cf.getSlot(slot)
+		cf.setSlot(slot, fp)
+		fp = victim
 		i = cf.altIndex(i, fp)
 		if cf.insertIntoBucket(i, fp) {
 			cf.count++
@@ -111,11 +220,29 @@ func (cf *CuckooFilter) Add(data []byte) bool {
 		}
 	}
 
-	return false
+	// The last evicted fingerprint has no bucket left; keep it in the stash.
+	cf.stash = append(cf.stash, stashEntry{index: i, fp: fp})
+	cf.count++
+	return true
+}
+
+// grow returns an empty filter with twice as many buckets to chain after this one.
+func (cf *CuckooFilter) grow() *CuckooFilter {
+	next := &CuckooFilter{
+		size:           cf.size * 2,
+		loadFactor:     cf.loadFactor,
+		bucketSize:     cf.bucketSize,
+		fingerprintLen: cf.fingerprintLen,
+		hasher:         cf.hasher,
+		autoResize:     cf.autoResize,
+	}
+	next.buckets = make([]byte, next.tableLen())
+	return next
 }
 
 // Contains checks if an element might be in the Cuckoo filter.
-// Note that false positives are possible, but false negatives are not.
+// Note that false positives are possible. False negatives are not, as long
+// as Remove is only called on elements that were added.
 //
 // Example:
 //
@@ -123,15 +250,34 @@ func (cf *CuckooFilter) Add(data []byte) bool {
 //	    fmt.Println("Element might be in the filter")
 //	}
 func (cf *CuckooFilter) Contains(data []byte) bool {
-	fp := cf.fingerprint(data)
-	i1 := cf.index(data)
+	return cf.containsHash(cf.hashData(data))
+}
+
+// containsHash checks for the element with hash h.
+func (cf *CuckooFilter) containsHash(h cuckooHash) bool {
+	i1, fp := cf.locate(h)
 	i2 := cf.altIndex(i1, fp)
-	return cf.containsInBucket(i1, fp) || cf.containsInBucket(i2, fp)
+	if cf.containsInBucket(i1, fp) || cf.containsInBucket(i2, fp) || cf.stashIndex(i1, i2, fp) >= 0 {
+		return true
+	}
+	return cf.next != nil && cf.next.containsHash(h)
 }
 
 // Remove removes an element from the Cuckoo filter.
 // Returns true if the element was successfully removed, false if it was not found.
 //
+// Only remove elements that were added: removing any other element that
+// matches a stored fingerprint deletes that fingerprint, making the element it
+// belongs to a false negative.
+//
+// With WithAutoResize, Remove tries the newest filter of the chain first. Each
+// filter has twice the buckets of the one before, so a fingerprint of another
+// element that matches in a newer filter also matches the removed element's
+// own fingerprint in its older filter. Removing the newer one therefore leaves
+// the other element found, and the removed element at worst a false positive.
+// Searching the oldest filter first instead would lose about 3% of the
+// remaining elements with 8-bit fingerprints after removing half of them.
+//
 // Example:
 //
 //	removed := cf.Remove([]byte("example"))
@@ -139,15 +285,28 @@ func (cf *CuckooFilter) Contains(data []byte) bool {
 //	    fmt.Println("Element removed from the filter")
 //	}
 func (cf *CuckooFilter) Remove(data []byte) bool {
-	fp := cf.fingerprint(data)
-	i1 := cf.index(data)
+	return cf.removeHash(cf.hashData(data))
+}
+
+// removeHash removes the element with hash h.
+// Newer filters are searched first; see Remove.
+func (cf *CuckooFilter) removeHash(h cuckooHash) bool {
+	if cf.next != nil && cf.next.removeHash(h) {
+		return true
+	}
+
+	i1, fp := cf.locate(h)
 	i2 := cf.altIndex(i1, fp)
 
 	if cf.removeFromBucket(i1, fp) || cf.removeFromBucket(i2, fp) {
 		cf.count--
 		return true
 	}
-
+	if s := cf.stashIndex(i1, i2, fp); s >= 0 {
+		cf.stash = append(cf.stash[:s], cf.stash[s+1:]...)
+		cf.count--
+		return true
+	}
 	return false
 }
 
@@ -157,10 +316,10 @@ func (cf *CuckooFilter) Remove(data []byte) bool {
 //
 //	cf.Clear()
 func (cf *CuckooFilter) Clear() {
-	for i := range cf.buckets {
-		cf.buckets[i] = 0
-	}
+	clear(cf.buckets)
 	cf.count = 0
+	cf.stash = nil
+	cf.next = nil
 }
 
 // Size returns the number of items in the filter.
@@ -170,7 +329,11 @@ func (cf *CuckooFilter) Clear() {
 //	count := cf.Size()
 //	fmt.Printf("Filter contains %d elements\n", count)
 func (cf *CuckooFilter) Size() int {
-	return int(cf.count)
+	count := 0
+	for f := cf; f != nil; f = f.next {
+		count += int(f.count)
+	}
+	return count
 }
 
 // IsEmpty returns true if the filter contains no elements.
@@ -181,32 +344,45 @@ func (cf *CuckooFilter) Size() int {
 //	    fmt.Println("Filter is empty")
 //	}
 func (cf *CuckooFilter) IsEmpty() bool {
-	return cf.count == 0
+	return cf.Size() == 0
 }
 
 // LoadFactor returns the current load factor of the filter.
+// If the filter has grown, the load factor covers every filter in the chain.
 //
 // Example:
 //
 //	lf := cf.LoadFactor()
 //	fmt.Printf("Current load factor: %.2f\n", lf)
 func (cf *CuckooFilter) LoadFactor() float64 {
-	return float64(cf.count) / (float64(cf.size) * bucketSize)
+	var slots float64
+	for f := cf; f != nil; f = f.next {
+		slots += float64(f.size * f.bucketSize)
+	}
+	return float64(cf.Size()) / slots
 }
 
 // FalsePositiveRate calculates the current false positive rate of the Cuckoo filter.
+// A lookup compares against the fingerprints in two buckets, each of which
+// matches a random non-zero fingerprint with probability 1/(2^fingerprintLen-1).
 //
 // Example:
 //
 //	fpr := cf.FalsePositiveRate()
 //	fmt.Printf("Current false positive rate: %.4f\n", fpr)
 func (cf *CuckooFilter) FalsePositiveRate() float64 {
-	e := 2 * bucketSize * float64(cf.size)
-	return 1 - math.Pow(1-1/e, float64(cf.count))
+	// A lookup is a false positive if any filter in the chain reports one.
+	pass := 1.0
+	for f := cf; f != nil; f = f.next {
+		// Expected number of fingerprints in the two buckets a lookup checks.
+		occupied := 2 * float64(f.count) / float64(f.size)
+		pass *= math.Pow(1-1/float64(f.fpMask()), occupied)
+	}
+	return 1 - pass
 }
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
-// It allows the CuckooFilter to be serialized into a binary format.
+// It serializes the CuckooFilter into the format written by WriteTo.
 //
 // Example:
 //
@@ -216,18 +392,11 @@ func (cf *CuckooFilter) FalsePositiveRate() float64 {
 //	}
 //	// Save 'data' to a file or send over network
 func (cf *CuckooFilter) MarshalBinary() ([]byte, error) {
-	data := make([]byte, 24+len(cf.buckets)*4)
-	binary.LittleEndian.PutUint64(data[0:8], cf.size)
-	binary.LittleEndian.PutUint64(data[8:16], cf.count)
-	binary.LittleEndian.PutUint64(data[16:24], math.Float64bits(cf.loadFactor))
-	for i, v := range cf.buckets {
-		binary.LittleEndian.PutUint32(data[24+i*4:], v)
-	}
-	return data, nil
+	return marshal(cf)
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
-// It allows a CuckooFilter to be deserialized from a binary format.
+// It deserializes a CuckooFilter written by MarshalBinary or WriteTo.
 //
 // Example:
 //
@@ -237,81 +406,249 @@ func (cf *CuckooFilter) MarshalBinary() ([]byte, error) {
 //	    log.Fatal(err)
 //	}
 func (cf *CuckooFilter) UnmarshalBinary(data []byte) error {
-	if len(data) < 24 {
-		return errors.New(errors.ErrInvalidArgument, "invalid data length")
+	return unmarshal(cf, data)
+}
+
+// WriteTo implements the io.WriterTo interface.
+// It streams the CuckooFilter to w without copying its buckets. The encoding
+// starts with a magic number and format version, records the hasher's key so
+// that a decoded filter hashes elements exactly as the original did, and
+// holds the configuration followed by every filter in the resized chain with
+// its stash and packed buckets.
+//
+// Example:
+//
+//	if _, err := cf.WriteTo(w); err != nil {
+//	    log.Fatal(err)
+//	}
+func (cf *CuckooFilter) WriteTo(w io.Writer) (int64, error) {
+	e := &encoder{w: w}
+	e.header(cuckooMagic, cf.hasher)
+	e.u64(math.Float64bits(cf.loadFactor))
+	e.u8(uint8(cf.bucketSize))
+	e.u8(uint8(cf.fingerprintLen))
+	var autoResize uint8
+	if cf.autoResize {
+		autoResize = 1
 	}
-	cf.size = binary.LittleEndian.Uint64(data[0:8])
-	cf.count = binary.LittleEndian.Uint64(data[8:16])
-	cf.loadFactor = math.Float64frombits(binary.LittleEndian.Uint64(data[16:24]))
-	cf.buckets = make([]uint32, (len(data)-24)/4)
-	for i := range cf.buckets {
-		cf.buckets[i] = binary.LittleEndian.Uint32(data[24+i*4:])
+	e.u8(autoResize)
+	var generations uint32
+	for f := cf; f != nil; f = f.next {
+		generations++
 	}
-	var err error
-	cf.hasher, err = hash.NewSipHasher()
-	return err
+	e.u32(generations)
+	for f := cf; f != nil; f = f.next {
+		e.u64(f.size)
+		e.u64(f.count)
+		e.u8(uint8(len(f.stash)))
+		for _, entry := range f.stash {
+			e.u64(entry.index)
+			e.u16(entry.fp)
+		}
+		e.bytes(f.buckets)
+	}
+	return e.n, e.err
+}
+
+// ReadFrom implements the io.ReaderFrom interface.
+// It reads a CuckooFilter written by WriteTo or MarshalBinary, replacing the
+// contents of cf. It restores SipHasher and TigerHasher from the encoding;
+// a filter that used any other hasher must be decoded into a filter
+// constructed with that same hasher. On error, cf is left unchanged.
+//
+// Example:
+//
+//	var cf CuckooFilter
+//	if _, err := cf.ReadFrom(r); err != nil {
+//	    log.Fatal(err)
+//	}
+func (cf *CuckooFilter) ReadFrom(r io.Reader) (int64, error) {
+	d := &decoder{r: r}
+	hasher, err := d.header(cuckooMagic, cf.hasher)
+	if err != nil {
+		return d.n, err
+	}
+	loadFactor := math.Float64frombits(d.u64())
+	bucketSize := uint64(d.u8())
+	fingerprintLen := uint(d.u8())
+	autoResize := d.u8() != 0
+	generations := d.u32()
+	if d.err != nil {
+		return d.n, d.err
+	}
+	if bucketSize < 1 || bucketSize > maxBucketSize ||
+		(fingerprintLen != 8 && fingerprintLen != 12 && fingerprintLen != 16) ||
+		generations < 1 || generations > 64 {
+		return d.n, errors.New(errors.ErrInvalidArgument, "invalid cuckoo filter parameters")
+	}
+
+	var head, tail *CuckooFilter
+	for g := uint32(0); g < generations; g++ {
+		f := &CuckooFilter{
+			size:           d.u64(),
+			count:          d.u64(),
+			loadFactor:     loadFactor,
+			bucketSize:     bucketSize,
+			fingerprintLen: fingerprintLen,
+			hasher:         hasher,
+			autoResize:     autoResize,
+		}
+		stashLen := int(d.u8())
+		if d.err != nil {
+			return d.n, d.err
+		}
+		if f.size == 0 || f.size&(f.size-1) != 0 || f.size > math.MaxUint64/(bucketSize*16) || stashLen > stashSize {
+			return d.n, errors.New(errors.ErrInvalidArgument, "invalid cuckoo filter parameters")
+		}
+		for k := 0; k < stashLen; k++ {
+			entry := stashEntry{index: d.u64(), fp: d.u16()}
+			if d.err == nil && (entry.index >= f.size || entry.fp == 0 || entry.fp > f.fpMask()) {
+				return d.n, errors.New(errors.ErrInvalidArgument, "invalid cuckoo filter stash")
+			}
+			f.stash = append(f.stash, entry)
+		}
+		f.buckets = d.bytes(f.tableLen())
+		if d.err != nil {
+			return d.n, d.err
+		}
+		if head == nil {
+			head = f
+		} else {
+			tail.next = f
+		}
+		tail = f
+	}
+	*cf = *head
+	return d.n, nil
 }
 
 // Helper functions
 
-func (cf *CuckooFilter) fingerprint(data []byte) uint8 {
-	cf.hasher.Reset()
-	cf.hasher.Write(data)
-	h := hash.HashBytesToUint64(cf.hasher.Sum(nil))
-	return uint8(h&0xFF) | 1 // Ensure fingerprint is non-zero
+// tableLen returns the number of bytes needed for the packed buckets.
+func (cf *CuckooFilter) tableLen() uint64 {
+	return (cf.size*cf.bucketSize*uint64(cf.fingerprintLen) + 7) / 8
+}
+
+// fpMask returns a mask covering fingerprintLen bits.
+func (cf *CuckooFilter) fpMask() uint16 {
+	return uint16(1<<cf.fingerprintLen - 1)
+}
+
+// cuckooHash is the hash of an element: its bucket index is derived from
+// index and its fingerprint from fp.
+type cuckooHash struct {
+	index uint64
+	fp    uint64
+}
+
+// hashData hashes data with the filter's hasher.
+func (cf *CuckooFilter) hashData(data []byte) cuckooHash {
+	return hashCuckoo(cf.hasher, data)
 }
 
-func (cf *CuckooFilter) index(data []byte) uint64 {
-	cf.hasher.Reset()
-	cf.hasher.Write(data)
-	h := hash.HashBytesToUint64(cf.hasher.Sum(nil))
-	return h % cf.size
+// hashCuckoo hashes data with hasher. Hashers with 128 bits of output give
+// the index and the fingerprint independent halves; for shorter hashes both
+// come from the same 64 bits, which locate splits.
+func hashCuckoo(hasher hash.Hasher, data []byte) cuckooHash {
+	h1, h2, ok := hash.Sum128(hasher, data)
+	if !ok {
+		h2 = h1
+	}
+	return cuckooHash{index: h1, fp: h2}
+}
+
+// locate derives the primary bucket of the element with hash h from the low
+// bits of its index hash and its fingerprint from the high bits of its
+// fingerprint hash, so that the two are independent even when both hashes
+// are the same 64 bits.
+func (cf *CuckooFilter) locate(h cuckooHash) (uint64, uint16) {
+	fp := uint16(h.fp>>48) & cf.fpMask()
+	if fp == 0 {
+		fp = 1 // Zero marks an empty slot
+	}
+	return h.index % cf.size, fp
 }
 
-func (cf *CuckooFilter) altIndex(i uint64, fp uint8) uint64 {
+func (cf *CuckooFilter) altIndex(i uint64, fp uint16) uint64 {
 	h := uint64(fp) * 0x5bd1e995 // MurmurHash2 constant
 	return (i ^ h) % cf.size
 }
 
-func (cf *CuckooFilter) insertIntoBucket(i uint64, fp uint8) bool {
-	if emptySlot := bits.TrailingZeros32(^cf.buckets[i]) / fingerprintLen; emptySlot < bucketSize {
-		cf.buckets[i] = insertFingerprint(cf.buckets[i], uint32(emptySlot), fp)
-		return true
-	}
-	return false
+// getSlot returns the fingerprint stored in the given slot, counting slots
+// across all buckets.
+func (cf *CuckooFilter) getSlot(slot uint64) uint16 {
+	word, shift := cf.loadSlotWord(slot)
+	return uint16(word>>shift) & cf.fpMask()
 }
 
-func (cf *CuckooFilter) containsInBucket(i uint64, fp uint8) bool {
-	return (cf.buckets[i]&uint32(fp)) != 0 && containsFingerprint(cf.buckets[i], fp)
+// setSlot stores fp in the given slot, counting slots across all buckets.
+func (cf *CuckooFilter) setSlot(slot uint64, fp uint16) {
+	word, shift := cf.loadSlotWord(slot)
+	word = word&^(uint32(cf.fpMask())<<shift) | uint32(fp)<<shift
+	bit := slot * uint64(cf.fingerprintLen)
+	b := cf.buckets[bit/8:]
+	b[0] = byte(word)
+	if shift+uint64(cf.fingerprintLen) > 8 {
+		b[1] = byte(word >> 8)
+	}
 }
 
-func (cf *CuckooFilter) removeFromBucket(i uint64, fp uint8) bool {
-	if slot := findFingerprint(cf.buckets[i], fp); slot < bucketSize {
-		cf.buckets[i] = removeFingerprint(cf.buckets[i], uint32(slot))
-		return true
+// loadSlotWord returns the bytes holding the given slot, little endian, and
+// the bit offset of the slot within them. Fingerprints of 8, 12 or 16 bits
+// start at bit 0 or 4 of a byte, so a slot never spans more than two bytes,
+// and only the bytes the slot occupies are read.
+func (cf *CuckooFilter) loadSlotWord(slot uint64) (uint32, uint64) {
+	bit := slot * uint64(cf.fingerprintLen)
+	b := cf.buckets[bit/8:]
+	shift := bit % 8
+	word := uint32(b[0])
+	if shift+uint64(cf.fingerprintLen) > 8 {
+		word |= uint32(b[1]) << 8
 	}
-	return false
+	return word, shift
 }
 
-func insertFingerprint(bucket uint32, slot uint32, fp uint8) uint32 {
-	return bucket | (uint32(fp) << (slot * fingerprintLen))
+// findSlot returns the first slot of bucket i holding fp, or false if there is none.
+// Passing a zero fingerprint finds the first empty slot.
+func (cf *CuckooFilter) findSlot(i uint64, fp uint16) (uint64, bool) {
+	first := i * cf.bucketSize
+	for slot := first; slot < first+cf.bucketSize; slot++ {
+		if cf.getSlot(slot) == fp {
+			return slot, true
+		}
+	}
+	return 0, false
 }
 
-func extractFingerprint(bucket uint32, slot uint32) uint8 {
-	return uint8((bucket >> (slot * fingerprintLen)) & 0xFF)
+func (cf *CuckooFilter) insertIntoBucket(i uint64, fp uint16) bool {
+	if slot, ok := cf.findSlot(i, 0); ok {
+		cf.setSlot(slot, fp)
+		return true
+	}
+	return false
 }
 
-func removeFingerprint(bucket uint32, slot uint32) uint32 {
-	return bucket & ^(0xFF << (slot * fingerprintLen))
+// stashIndex returns the position in the stash of fp stored for bucket i1 or i2, or -1.
+func (cf *CuckooFilter) stashIndex(i1, i2 uint64, fp uint16) int {
+	for s, e := range cf.stash {
+		if e.fp == fp && (e.index == i1 || e.index == i2) {
+			return s
+		}
+	}
+	return -1
 }
 
-func containsFingerprint(bucket uint32, fp uint8) bool {
-	return (bucket&uint32(fp)) != 0 && findFingerprint(bucket, fp) < bucketSize
+func (cf *CuckooFilter) containsInBucket(i uint64, fp uint16) bool {
+	_, ok := cf.findSlot(i, fp)
+	return ok
 }
 
-func findFingerprint(bucket uint32, fp uint8) uint32 {
-	x := bucket ^ (uint32(fp) * 0x01010101)
-	return uint32(bits.OnesCount32((x - 0x01010101) & ^x & 0x80808080))
+func (cf *CuckooFilter) removeFromBucket(i uint64, fp uint16) bool {
+	if slot, ok := cf.findSlot(i, fp); ok {
+		cf.setSlot(slot, 0)
+		return true
+	}
+	return false
 }
 
 // Ensure CuckooFilter implements the ProbabilisticSet interface
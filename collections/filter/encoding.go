@@ -0,0 +1,358 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"slices"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+)
+
+// Serialized filters share a common header:
+//
+//	magic    [4]byte  identifies the filter type
+//	version  uint8    format version, currently 1
+//	hasher   uint8    kind of hasher, followed by its key for keyed hashers
+//
+// The filter's own fields and tables follow, little-endian.
+const formatVersion = 1
+
+var (
+	bloomMagic        = [4]byte{'N', 'S', 'B', 'F'}
+	blockedBloomMagic = [4]byte{'N', 'S', 'B', 'B'}
+	cuckooMagic       = [4]byte{'N', 'S', 'C', 'F'}
+	xorMagic          = [4]byte{'N', 'S', 'X', 'F'}
+)
+
+// Hasher kinds recorded in the header.
+const (
+	// hasherCustom marks a hasher this package cannot recreate. Decoding keeps
+	// the hasher already set on the receiving filter.
+	hasherCustom uint8 = iota
+	// hasherSip marks a SipHasher, followed by its 16-byte key.
+	hasherSip
+	// hasherTiger marks a TigerHasher, which is unkeyed.
+	hasherTiger
+	// hasherFNV32 and hasherFNV64 mark the FNV-1a hashers, which are unkeyed.
+	hasherFNV32
+	hasherFNV64
+	// hasherWy marks a WyHasher, followed by its 8-byte seed.
+	hasherWy
+	// hasherSipRounds marks a SipHasher with other rounds than 1-3,
+	// followed by its 16-byte key and its two round counts.
+	hasherSipRounds
+	// hasherCRC32C marks a CRC32CHasher, which is unkeyed.
+	hasherCRC32C
+	// hasherAES marks an AESHasher, followed by its 16-byte key.
+	hasherAES
+	// hasherTiger2 marks a TigerHasher for Tiger2, and hasherTigerTree a
+	// TigerTreeHasher; both are unkeyed.
+	hasherTiger2
+	hasherTigerTree
+)
+
+// chunkSize is the number of bytes encoded or decoded at a time when
+// streaming tables, so that large filters are never copied in full.
+const chunkSize = 64 << 10
+
+// encoder writes little-endian values to a writer, remembering the first
+// error and the number of bytes written.
+type encoder struct {
+	w   io.Writer
+	n   int64
+	err error
+	buf [8]byte
+}
+
+func (e *encoder) write(p []byte) {
+	if e.err != nil {
+		return
+	}
+	n, err := e.w.Write(p)
+	e.n += int64(n)
+	e.err = err
+}
+
+func (e *encoder) u8(v uint8) {
+	e.buf[0] = v
+	e.write(e.buf[:1])
+}
+
+func (e *encoder) u16(v uint16) {
+	binary.LittleEndian.PutUint16(e.buf[:], v)
+	e.write(e.buf[:2])
+}
+
+func (e *encoder) u32(v uint32) {
+	binary.LittleEndian.PutUint32(e.buf[:], v)
+	e.write(e.buf[:4])
+}
+
+func (e *encoder) u64(v uint64) {
+	binary.LittleEndian.PutUint64(e.buf[:], v)
+	e.write(e.buf[:8])
+}
+
+// bytes writes p in chunks, so that a failing writer stops the copy early.
+func (e *encoder) bytes(p []byte) {
+	for len(p) > 0 && e.err == nil {
+		n := min(len(p), chunkSize)
+		e.write(p[:n])
+		p = p[n:]
+	}
+}
+
+// words writes ws through a fixed-size buffer.
+func (e *encoder) words(ws []uint64) {
+	buf := make([]byte, 0, chunkSize)
+	for len(ws) > 0 && e.err == nil {
+		n := min(len(ws), chunkSize/8)
+		buf = buf[:0]
+		for _, w := range ws[:n] {
+			buf = binary.LittleEndian.AppendUint64(buf, w)
+		}
+		e.write(buf)
+		ws = ws[n:]
+	}
+}
+
+// header writes the common header for a filter with the given magic and hasher.
+func (e *encoder) header(magic [4]byte, hasher hash.Hasher) {
+	e.write(magic[:])
+	e.u8(formatVersion)
+	switch h := hasher.(type) {
+	case *hash.SipHasher:
+		key, err := h.MarshalBinary()
+		if err != nil && e.err == nil {
+			e.err = err
+		}
+		if len(key) > 16 {
+			e.u8(hasherSipRounds)
+		} else {
+			e.u8(hasherSip)
+		}
+		e.write(key)
+	case *hash.TigerHasher:
+		if h.IsTiger2() {
+			e.u8(hasherTiger2)
+		} else {
+			e.u8(hasherTiger)
+		}
+	case *hash.TigerTreeHasher:
+		e.u8(hasherTigerTree)
+	case *hash.FNVHasher:
+		if h.Size() == 4 {
+			e.u8(hasherFNV32)
+		} else {
+			e.u8(hasherFNV64)
+		}
+	case *hash.WyHasher:
+		seed, err := h.MarshalBinary()
+		if err != nil && e.err == nil {
+			e.err = err
+		}
+		e.u8(hasherWy)
+		e.write(seed)
+	case *hash.CRC32CHasher:
+		e.u8(hasherCRC32C)
+	case *hash.AESHasher:
+		key, err := h.MarshalBinary()
+		if err != nil && e.err == nil {
+			e.err = err
+		}
+		e.u8(hasherAES)
+		e.write(key)
+	default:
+		e.u8(hasherCustom)
+	}
+}
+
+// sameHasher reports whether a and b hash identically: the same hasher kind
+// with the same key, as recorded in the header. Custom hashers, whose header
+// carries no key, must be the same hasher value.
+func sameHasher(a, b hash.Hasher) bool {
+	var ea, eb encoder
+	var ba, bb bytes.Buffer
+	ea.w, eb.w = &ba, &bb
+	ea.header([4]byte{}, a)
+	eb.header([4]byte{}, b)
+	if ea.err != nil || eb.err != nil || !bytes.Equal(ba.Bytes(), bb.Bytes()) {
+		return false
+	}
+	// The kind byte follows the 4-byte magic and the version.
+	if ba.Bytes()[5] != hasherCustom {
+		return true
+	}
+	ta := reflect.TypeOf(a)
+	return ta == reflect.TypeOf(b) && ta != nil && ta.Comparable() && a == b
+}
+
+// decoder reads little-endian values from a reader, remembering the first
+// error and the number of bytes read. A stream that ends early yields
+// io.ErrUnexpectedEOF.
+type decoder struct {
+	r   io.Reader
+	n   int64
+	err error
+	buf [8]byte
+}
+
+func (d *decoder) read(p []byte) {
+	if d.err != nil {
+		return
+	}
+	n, err := io.ReadFull(d.r, p)
+	d.n += int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	d.err = err
+}
+
+func (d *decoder) u8() uint8 {
+	d.read(d.buf[:1])
+	return d.buf[0]
+}
+
+func (d *decoder) u16() uint16 {
+	d.read(d.buf[:2])
+	return binary.LittleEndian.Uint16(d.buf[:])
+}
+
+func (d *decoder) u32() uint32 {
+	d.read(d.buf[:4])
+	return binary.LittleEndian.Uint32(d.buf[:])
+}
+
+func (d *decoder) u64() uint64 {
+	d.read(d.buf[:8])
+	return binary.LittleEndian.Uint64(d.buf[:])
+}
+
+// bytes reads n bytes. The result grows chunk by chunk as data arrives, so a
+// corrupt length in a truncated stream fails before allocating all of it.
+func (d *decoder) bytes(n uint64) []byte {
+	var out []byte
+	for uint64(len(out)) < n && d.err == nil {
+		start := len(out)
+		k := int(min(n-uint64(start), chunkSize))
+		out = slices.Grow(out, k)[:start+k]
+		d.read(out[start:])
+	}
+	return out
+}
+
+// words reads n little-endian uint64 values, growing the result like bytes.
+func (d *decoder) words(n uint64) []uint64 {
+	var out []uint64
+	buf := make([]byte, chunkSize)
+	for uint64(len(out)) < n && d.err == nil {
+		k := int(min(n-uint64(len(out)), chunkSize/8))
+		d.read(buf[:k*8])
+		out = slices.Grow(out, k)
+		for i := 0; i < k; i++ {
+			out = append(out, binary.LittleEndian.Uint64(buf[i*8:]))
+		}
+	}
+	return out
+}
+
+// header reads and checks the common header for a filter with the given
+// magic. It returns the hasher the filter was serialized with, or current if
+// that hasher was a custom one, which cannot be recreated.
+func (d *decoder) header(magic [4]byte, current hash.Hasher) (hash.Hasher, error) {
+	var got [4]byte
+	d.read(got[:])
+	version := d.u8()
+	kind := d.u8()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if got != magic {
+		return nil, errors.New(errors.ErrInvalidArgument, "unrecognized filter encoding")
+	}
+	if version != formatVersion {
+		return nil, errors.New(errors.ErrNotImplemented, "unsupported filter encoding version")
+	}
+	switch kind {
+	case hasherSip, hasherSipRounds:
+		n := uint64(16)
+		if kind == hasherSipRounds {
+			n = 18
+		}
+		key := d.bytes(n)
+		if d.err != nil {
+			return nil, d.err
+		}
+		h := new(hash.SipHasher)
+		if err := h.UnmarshalBinary(key); err != nil {
+			return nil, errors.Wrap(err, "invalid hasher key")
+		}
+		return h, nil
+	case hasherTiger:
+		return hash.NewTigerHasher(), nil
+	case hasherTiger2:
+		return hash.NewTiger2Hasher(), nil
+	case hasherTigerTree:
+		return hash.NewTigerTreeHasher(), nil
+	case hasherFNV32:
+		return hash.NewFNV32Hasher(), nil
+	case hasherFNV64:
+		return hash.NewFNV64Hasher(), nil
+	case hasherWy:
+		seed := d.bytes(8)
+		if d.err != nil {
+			return nil, d.err
+		}
+		h := new(hash.WyHasher)
+		if err := h.UnmarshalBinary(seed); err != nil {
+			return nil, errors.Wrap(err, "invalid hasher seed")
+		}
+		return h, nil
+	case hasherCRC32C:
+		return hash.NewCRC32CHasher(), nil
+	case hasherAES:
+		key := d.bytes(16)
+		if d.err != nil {
+			return nil, d.err
+		}
+		h := new(hash.AESHasher)
+		if err := h.UnmarshalBinary(key); err != nil {
+			return nil, errors.Wrap(err, "invalid hasher key")
+		}
+		return h, nil
+	case hasherCustom:
+		if current == nil {
+			return nil, errors.New(errors.ErrInvalidArgument,
+				"filter was serialized with a custom hasher; decode into a filter constructed with that hasher")
+		}
+		return current, nil
+	default:
+		return nil, errors.New(errors.ErrInvalidArgument, "unrecognized hasher kind")
+	}
+}
+
+// marshal encodes a filter into memory through its WriteTo method.
+func marshal(w io.WriterTo) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshal decodes a filter from memory through its ReadFrom method and
+// checks that all of data was consumed.
+func unmarshal(r io.ReaderFrom, data []byte) error {
+	n, err := r.ReadFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if n != int64(len(data)) {
+		return errors.New(errors.ErrInvalidArgument, "invalid data length")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"encoding/binary"
+
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+)
+
+// Typed adapts a filter over byte slices to values of type T.
+// Each value is hashed to 64 bits with a hash.TypedHasher, and the 8-byte
+// hash is what the underlying filter stores, so any filter in this package
+// can hold strings, integers or custom types without the caller serializing
+// them at every call site.
+//
+// Example:
+//
+//	bf, _ := NewBloomFilter(1000, 0.01)
+//	users := NewTyped[string](bf, hash.NewStringHasher(hash.NewTigerHasher()))
+//	users.Add("alice")
+//	users.Contains("alice") // true
+type Typed[T any] struct {
+	filter collections.ProbabilisticSet[[]byte]
+	hasher hash.TypedHasher[T]
+}
+
+// NewTyped creates a new Typed adapter that stores values in filter, hashing them with hasher.
+func NewTyped[T any](filter collections.ProbabilisticSet[[]byte], hasher hash.TypedHasher[T]) *Typed[T] {
+	return &Typed[T]{filter: filter, hasher: hasher}
+}
+
+// Add inserts a value into the filter.
+// It returns the result of the underlying filter's Add.
+func (t *Typed[T]) Add(value T) bool {
+	return t.filter.Add(t.key(value))
+}
+
+// Contains checks if a value might be in the filter.
+func (t *Typed[T]) Contains(value T) bool {
+	return t.filter.Contains(t.key(value))
+}
+
+// Remove removes a value from the filter, if the underlying filter supports
+// removal, as CuckooFilter does. It returns true if the value was removed,
+// or an error if the underlying filter does not support removal.
+//
+// Example:
+//
+//	removed, err := users.Remove("alice")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (t *Typed[T]) Remove(value T) (bool, error) {
+	remover, ok := t.filter.(interface{ Remove(data []byte) bool })
+	if !ok {
+		return false, errors.New(errors.ErrNotImplemented, "underlying filter does not support removal")
+	}
+	return remover.Remove(t.key(value)), nil
+}
+
+// Clear removes all values from the filter.
+func (t *Typed[T]) Clear() {
+	t.filter.Clear()
+}
+
+// Size returns the number of values in the filter, as reported by the underlying filter.
+func (t *Typed[T]) Size() int {
+	return t.filter.Size()
+}
+
+// IsEmpty returns true if the filter contains no values.
+func (t *Typed[T]) IsEmpty() bool {
+	return t.filter.IsEmpty()
+}
+
+// Filter returns the underlying filter, for example to serialize it.
+func (t *Typed[T]) Filter() collections.ProbabilisticSet[[]byte] {
+	return t.filter
+}
+
+// key returns the bytes stored in the underlying filter for value.
+func (t *Typed[T]) key(value T) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], t.hasher.Hash(value))
+	return buf[:]
+}
+
+// Ensure Typed implements the ProbabilisticSet interface
+var _ collections.ProbabilisticSet[string] = (*Typed[string])(nil)
@@ -1,7 +1,7 @@
 package filter
 
 import (
-	"encoding/binary"
+	"io"
 	"math"
 
 	"github.com/ielm/neostd/collections"
@@ -98,8 +98,7 @@ func (xf *XorFilter) Add(data []byte) bool {
 //		fmt.Println("Element might be in the set")
 //	}
 func (xf *XorFilter) Contains(data []byte) bool {
-	h1, h2, h3 := xf.hashValues(data)
-	f := xf.fingerprint(h1)
+	h1, h2, h3, f := xf.hashValues(data)
 	return xf.fingerprints[h1]^xf.fingerprints[h2]^xf.fingerprints[h3] == f
 }
 
@@ -150,7 +149,7 @@ func (xf *XorFilter) FalsePositiveRate() float64 {
 }
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
-// It serializes the XorFilter into a binary format.
+// It serializes the XorFilter into the format written by WriteTo.
 //
 // Example:
 //
@@ -160,19 +159,11 @@ func (xf *XorFilter) FalsePositiveRate() float64 {
 //	}
 //	// Use 'data' for storage or transmission
 func (xf *XorFilter) MarshalBinary() ([]byte, error) {
-	data := make([]byte, 28+len(xf.fingerprints))
-	binary.LittleEndian.PutUint32(data[0:4], xf.blockLength)
-	binary.LittleEndian.PutUint32(data[4:8], xf.segmentLength)
-	binary.LittleEndian.PutUint32(data[8:12], xf.segmentLengthMask)
-	binary.LittleEndian.PutUint32(data[12:16], xf.segmentCount)
-	binary.LittleEndian.PutUint32(data[16:20], xf.segmentCountLength)
-	binary.LittleEndian.PutUint64(data[20:28], xf.seed)
-	copy(data[28:], xf.fingerprints)
-	return data, nil
+	return marshal(xf)
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
-// It deserializes the XorFilter from a binary format.
+// It deserializes an XorFilter written by MarshalBinary or WriteTo.
 //
 // Example:
 //
@@ -182,34 +173,90 @@ func (xf *XorFilter) MarshalBinary() ([]byte, error) {
 //		log.Fatal(err)
 //	}
 func (xf *XorFilter) UnmarshalBinary(data []byte) error {
-	if len(data) < 28 {
-		return errors.New(errors.ErrInvalidArgument, "invalid data length")
-	}
-	xf.blockLength = binary.LittleEndian.Uint32(data[0:4])
-	xf.segmentLength = binary.LittleEndian.Uint32(data[4:8])
-	xf.segmentLengthMask = binary.LittleEndian.Uint32(data[8:12])
-	xf.segmentCount = binary.LittleEndian.Uint32(data[12:16])
-	xf.segmentCountLength = binary.LittleEndian.Uint32(data[16:20])
-	xf.seed = binary.LittleEndian.Uint64(data[20:28])
-	xf.fingerprints = make([]uint8, len(data)-28)
-	copy(xf.fingerprints, data[28:])
+	return unmarshal(xf, data)
+}
 
-	// Use the default SipHasher for deserialized XorFilters
-	var err error
-	xf.hasher, err = hash.NewSipHasher()
-	return err
+// WriteTo implements the io.WriterTo interface.
+// It streams the XorFilter to w without copying its fingerprints. The
+// encoding starts with a magic number and format version, and records the
+// hasher's key so that a decoded filter hashes elements exactly as the
+// original did.
+//
+// Example:
+//
+//	if _, err := xf.WriteTo(w); err != nil {
+//		log.Fatal(err)
+//	}
+func (xf *XorFilter) WriteTo(w io.Writer) (int64, error) {
+	e := &encoder{w: w}
+	e.header(xorMagic, xf.hasher)
+	e.u32(xf.blockLength)
+	e.u32(xf.segmentLength)
+	e.u32(xf.segmentLengthMask)
+	e.u32(xf.segmentCount)
+	e.u32(xf.segmentCountLength)
+	e.u64(xf.seed)
+	e.u64(uint64(len(xf.fingerprints)))
+	e.bytes(xf.fingerprints)
+	return e.n, e.err
+}
+
+// ReadFrom implements the io.ReaderFrom interface.
+// It reads an XorFilter written by WriteTo or MarshalBinary, replacing the
+// contents of xf. It restores SipHasher and TigerHasher from the encoding;
+// a filter that used any other hasher must be decoded into a filter
+// constructed with that same hasher. On error, xf is left unchanged.
+//
+// Example:
+//
+//	var xf XorFilter
+//	if _, err := xf.ReadFrom(r); err != nil {
+//		log.Fatal(err)
+//	}
+func (xf *XorFilter) ReadFrom(r io.Reader) (int64, error) {
+	d := &decoder{r: r}
+	hasher, err := d.header(xorMagic, xf.hasher)
+	if err != nil {
+		return d.n, err
+	}
+	decoded := XorFilter{
+		blockLength:        d.u32(),
+		segmentLength:      d.u32(),
+		segmentLengthMask:  d.u32(),
+		segmentCount:       d.u32(),
+		segmentCountLength: d.u32(),
+		seed:               d.u64(),
+		hasher:             hasher,
+	}
+	length := d.u64()
+	if d.err != nil {
+		return d.n, d.err
+	}
+	if length < uint64(decoded.segmentCountLength) {
+		return d.n, errors.New(errors.ErrInvalidArgument, "invalid xor filter parameters")
+	}
+	decoded.fingerprints = d.bytes(length)
+	if d.err != nil {
+		return d.n, d.err
+	}
+	*xf = decoded
+	return d.n, nil
 }
 
 // Helper functions
 
-func (xf *XorFilter) hashValues(data []byte) (uint32, uint32, uint32) {
-	xf.hasher.Reset()
-	xf.hasher.Write(data)
-	h := hash.HashBytesToUint64(xf.hasher.Sum(nil))
+// hashValues returns the three slots of an element and its fingerprint.
+// Hashers with 128 bits of output give the fingerprint its own half;
+// otherwise it is derived from the first slot.
+func (xf *XorFilter) hashValues(data []byte) (uint32, uint32, uint32, uint8) {
+	h, fh, ok := hash.Sum128(xf.hasher, data)
 	h1 := uint32(h) & (xf.segmentCountLength - 1)
 	h2 := uint32(h>>32) & (xf.segmentCountLength - 1)
 	h3 := xf.hash(uint64(h1) ^ uint64(h2))
-	return h1, h2, h3
+	if !ok {
+		return h1, h2, h3, xf.fingerprint(h1)
+	}
+	return h1, h2, h3, xf.fingerprint(uint32(fh))
 }
 
 func (xf *XorFilter) hash(x uint64) uint32 {
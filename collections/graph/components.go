@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"github.com/ielm/neostd/collections/maps"
+	"github.com/ielm/neostd/collections/set"
+)
+
+// ConnectedComponents labels each vertex of the graph with the number of its
+// connected component, from 0 to count-1, and returns the labels and the
+// number of components. Two vertices are in the same component if a path
+// joins them, ignoring edge directions, so in a directed graph these are
+// the weakly connected components. It merges the endpoints of each edge
+// with a DisjointSet, in nearly O(V+E) time.
+//
+// Example:
+//
+//	labels, count := graph.ConnectedComponents[string, int](g)
+//	a, _ := labels.Get("a")
+//	b, _ := labels.Get("b")
+//	fmt.Println(count, a == b)
+func ConnectedComponents[V comparable, E any](g Graph[V, E]) (*maps.HashMap[V, int], int) {
+	vertices := g.GetVertices()
+	forest := set.NewDisjointSet[V]()
+	for _, v := range vertices {
+		forest.MakeSet(v)
+	}
+	for _, edge := range g.GetAllEdges() {
+		forest.Union(edge.Source, edge.Destination)
+	}
+
+	labels := maps.NewHashMap[V, int](g.Comparator()).Unwrap()
+	roots := make(map[V]int)
+	for _, v := range vertices {
+		root, _ := forest.Find(v)
+		label, ok := roots[root]
+		if !ok {
+			label = len(roots)
+			roots[root] = label
+		}
+		labels.Put(v, label)
+	}
+	return labels, len(roots)
+}
+
+// StronglyConnectedComponents labels each vertex of the graph with the
+// number of its strongly connected component, from 0 to count-1, and
+// returns the labels and the number of components. Two vertices are in the
+// same strongly connected component if each has a path to the other.
+//
+// It uses Tarjan's algorithm, in O(V+E) time, which finds the components in
+// reverse topological order: if an edge joins two components, the label of
+// its source is greater than the label of its destination. In an undirected
+// graph, the strongly connected components are the connected components.
+//
+// Example:
+//
+//	labels, count := graph.StronglyConnectedComponents[string, int](g)
+//	if count == g.Size() {
+//		fmt.Println("no cycles")
+//	}
+func StronglyConnectedComponents[V comparable, E any](g Graph[V, E]) (*maps.HashMap[V, int], int) {
+	labels := maps.NewHashMap[V, int](g.Comparator()).Unwrap()
+	index := make(map[V]int)   // Order of discovery
+	lowlink := make(map[V]int) // Lowest index reachable through the search tree
+	onStack := make(map[V]bool)
+	var stack []V
+	count := 0
+
+	// frame is a vertex of the search path and the neighbors it has left
+	// to explore.
+	type frame struct {
+		vertex    V
+		neighbors []V
+	}
+
+	for _, root := range g.GetVertices() {
+		if _, seen := index[root]; seen {
+			continue
+		}
+
+		path := []frame{{vertex: root, neighbors: g.GetNeighbors(root)}}
+		index[root], lowlink[root] = len(index), len(index)
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(path) > 0 {
+			top := &path[len(path)-1]
+			v := top.vertex
+			if len(top.neighbors) > 0 {
+				w := top.neighbors[0]
+				top.neighbors = top.neighbors[1:]
+				if _, seen := index[w]; !seen {
+					index[w], lowlink[w] = len(index), len(index)
+					stack = append(stack, w)
+					onStack[w] = true
+					path = append(path, frame{vertex: w, neighbors: g.GetNeighbors(w)})
+				} else if onStack[w] {
+					lowlink[v] = min(lowlink[v], index[w])
+				}
+				continue
+			}
+
+			// All neighbors are done; if v is the root of a component, the
+			// component is v and everything above it on the stack
+			if lowlink[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					labels.Put(w, count)
+					if w == v {
+						break
+					}
+				}
+				count++
+			}
+			path = path[:len(path)-1]
+			if len(path) > 0 {
+				parent := path[len(path)-1].vertex
+				lowlink[parent] = min(lowlink[parent], lowlink[v])
+			}
+		}
+	}
+	return labels, count
+}
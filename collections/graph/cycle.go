@@ -0,0 +1,94 @@
+package graph
+
+import "github.com/ielm/neostd/res"
+
+// HasCycle reports whether the graph contains a cycle. A self-loop counts as
+// a cycle. In an undirected graph, an edge is not a cycle by itself: a cycle
+// must return to its first vertex without reusing an edge.
+//
+// A directed graph without cycles is a DAG, so HasCycle can be used to check
+// that a graph can be topologically sorted.
+//
+// Example:
+//
+//	if graph.HasCycle[string, int](deps) {
+//		return errors.New(errors.ErrInvalidArgument, "circular dependency")
+//	}
+func HasCycle[V comparable, E any](g Graph[V, E]) bool {
+	return FindCycle(g).IsSome()
+}
+
+// FindCycle returns the vertices of a cycle in the graph, in order along the
+// cycle and without repeating the first vertex, or None if the graph has no
+// cycle. It runs a depth-first search in O(V+E) time.
+//
+// Example:
+//
+//	if cycle := graph.FindCycle[string, int](deps); cycle.IsSome() {
+//		fmt.Println("circular dependency:", cycle.Unwrap())
+//	}
+func FindCycle[V comparable, E any](g Graph[V, E]) res.Option[[]V] {
+	const (
+		white = iota // Not yet discovered
+		gray         // On the current search path
+		black        // Finished
+	)
+
+	undirected := IsUndirected(g)
+	color := make(map[V]int)
+	parent := make(map[V]V)
+
+	// frame is a vertex on the search path and the neighbors it has left
+	// to explore.
+	type frame struct {
+		vertex    V
+		neighbors []V
+	}
+
+	for _, root := range g.GetVertices() {
+		if color[root] != white {
+			continue
+		}
+		color[root] = gray
+		stack := []frame{{vertex: root, neighbors: g.GetNeighbors(root)}}
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if len(top.neighbors) == 0 {
+				color[top.vertex] = black
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			next := top.neighbors[0]
+			top.neighbors = top.neighbors[1:]
+
+			// In an undirected graph, the edge back to the parent is the
+			// edge we arrived by, not a cycle.
+			if undirected && next != top.vertex {
+				if p, ok := parent[top.vertex]; ok && p == next {
+					continue
+				}
+			}
+
+			switch color[next] {
+			case white:
+				color[next] = gray
+				parent[next] = top.vertex
+				stack = append(stack, frame{vertex: next, neighbors: g.GetNeighbors(next)})
+			case gray:
+				// next is on the search path, so the path from next to the
+				// current vertex closes a cycle.
+				cycle := []V{top.vertex}
+				for v := top.vertex; v != next; {
+					v = parent[v]
+					cycle = append(cycle, v)
+				}
+				for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return res.Some(cycle)
+			}
+		}
+	}
+	return res.None[[]V]()
+}
@@ -0,0 +1,263 @@
+package graph
+
+import (
+	"slices"
+
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/collections/maps"
+	"github.com/ielm/neostd/errors"
+)
+
+// DAG represents a directed acyclic graph. AddEdge rejects any edge that
+// would close a cycle, so the graph always has a topological order.
+//
+// The order is maintained incrementally with the algorithm of Pearce and
+// Kelly: an edge that agrees with the current order is added in constant
+// time, and otherwise only the vertices between its endpoints in the order
+// are searched and reordered.
+//
+// Example:
+//
+//	deps := graph.NewDAG[string, struct{}](comp.GenericComparator[string]())
+//	deps.Add("app")
+//	deps.Add("lib")
+//	deps.AddEdge("lib", "app", struct{}{})
+//	err := deps.AddEdge("app", "lib", struct{}{}) // Fails: lib -> app -> lib
+type DAG[V comparable, E any] struct {
+	*DiGraph[V, E]
+	order    map[V]int            // Position of each vertex in a topological order
+	incoming map[V]map[V]struct{} // Sources of the edges into each vertex
+	next     int                  // Position for the next vertex added
+}
+
+// NewDAG creates a new directed acyclic graph
+func NewDAG[V comparable, E any](comparator comp.Comparator[V]) *DAG[V, E] {
+	return &DAG[V, E]{
+		DiGraph:  NewDiGraph[V, E](comparator),
+		order:    make(map[V]int),
+		incoming: make(map[V]map[V]struct{}),
+	}
+}
+
+// Add adds a vertex to the graph, last in the topological order
+func (g *DAG[V, E]) Add(vertex V) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, exists := g.vertices.Get(vertex); exists {
+		return false
+	}
+	g.vertices.Put(vertex, maps.NewHashMap[V, E](g.comparator).Unwrap())
+	g.order[vertex] = g.next
+	g.incoming[vertex] = make(map[V]struct{})
+	g.next++
+	return true
+}
+
+// Remove removes a vertex and all its edges from the graph
+func (g *DAG[V, E]) Remove(vertex V) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	edges, exists := g.vertices.Get(vertex)
+	if !exists {
+		return false
+	}
+	edges.ForEach(func(dest V, _ E) {
+		delete(g.incoming[dest], vertex)
+	})
+	for source := range g.incoming[vertex] {
+		if sourceEdges, ok := g.vertices.Get(source); ok {
+			sourceEdges.Remove(vertex)
+		}
+	}
+	g.edgeCount -= edges.Size() + len(g.incoming[vertex])
+	g.vertices.Remove(vertex)
+	delete(g.order, vertex)
+	delete(g.incoming, vertex)
+	return true
+}
+
+// Clear removes all vertices and edges from the graph
+func (g *DAG[V, E]) Clear() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.vertices.Clear()
+	g.edgeCount = 0
+	clear(g.order)
+	clear(g.incoming)
+	g.next = 0
+}
+
+// AddEdge adds a directed edge to the graph, or updates its weight if it
+// exists. It returns an ErrInvalidArgument error, and leaves the graph
+// unchanged, if the edge would create a cycle.
+func (g *DAG[V, E]) AddEdge(source, destination V, weight E) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sourceEdges, exists := g.vertices.Get(source)
+	if !exists {
+		return errors.New(errors.ErrNotFound, "source vertex not found")
+	}
+	if _, exists := g.vertices.Get(destination); !exists {
+		return errors.New(errors.ErrNotFound, "destination vertex not found")
+	}
+	if source == destination {
+		return errors.New(errors.ErrInvalidArgument, "edge would create a cycle")
+	}
+
+	if _, existed := sourceEdges.Get(destination); !existed && g.order[destination] < g.order[source] {
+		if !g.reorder(source, destination) {
+			return errors.New(errors.ErrInvalidArgument, "edge would create a cycle")
+		}
+	}
+
+	if _, existed := sourceEdges.Put(destination, weight); !existed {
+		g.incoming[destination][source] = struct{}{}
+		g.edgeCount++
+	}
+	return nil
+}
+
+// reorder restores a topological order before adding the edge u -> v, with
+// v currently ordered before u. Only the vertices ordered between v and u
+// can be affected: those reachable from v, which must move after u, and
+// those that reach u, which must move before v. It reports false if v
+// reaches u, in which case the edge would close a cycle.
+func (g *DAG[V, E]) reorder(u, v V) bool {
+	lower, upper := g.order[v], g.order[u]
+
+	var forward []V
+	visited := map[V]bool{v: true}
+	stack := []V{v}
+	for len(stack) > 0 {
+		w := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		forward = append(forward, w)
+		edges, _ := g.vertices.Get(w)
+		for _, next := range edges.Keys() {
+			if next == u {
+				return false
+			}
+			if !visited[next] && g.order[next] < upper {
+				visited[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+
+	var backward []V
+	stack = append(stack, u)
+	visited[u] = true
+	for len(stack) > 0 {
+		w := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		backward = append(backward, w)
+		for prev := range g.incoming[w] {
+			if !visited[prev] && g.order[prev] > lower {
+				visited[prev] = true
+				stack = append(stack, prev)
+			}
+		}
+	}
+
+	// Give the vertices that reach u the lowest of the positions involved,
+	// then those reachable from v, each set keeping its relative order
+	byOrder := func(a, b V) int { return g.order[a] - g.order[b] }
+	slices.SortFunc(backward, byOrder)
+	slices.SortFunc(forward, byOrder)
+	affected := append(backward, forward...)
+	positions := make([]int, len(affected))
+	for i, w := range affected {
+		positions[i] = g.order[w]
+	}
+	slices.Sort(positions)
+	for i, w := range affected {
+		g.order[w] = positions[i]
+	}
+	return true
+}
+
+// RemoveEdge removes a directed edge from the graph
+func (g *DAG[V, E]) RemoveEdge(source, destination V) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sourceEdges, exists := g.vertices.Get(source)
+	if !exists {
+		return errors.New(errors.ErrNotFound, "source vertex not found")
+	}
+
+	if _, exists := sourceEdges.Remove(destination); exists {
+		delete(g.incoming[destination], source)
+		g.edgeCount--
+		return nil
+	}
+	return errors.New(errors.ErrNotFound, "edge not found")
+}
+
+// TopologicalOrder returns the vertices in an order in which every edge
+// goes from an earlier vertex to a later one.
+func (g *DAG[V, E]) TopologicalOrder() []V {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	vertices := g.vertices.Keys()
+	slices.SortFunc(vertices, func(a, b V) int { return g.order[a] - g.order[b] })
+	return vertices
+}
+
+// Ancestors returns the vertices from which there is a path to vertex, in
+// no particular order
+func (g *DAG[V, E]) Ancestors(vertex V) []V {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.reach(vertex, func(v V) []V {
+		sources := make([]V, 0, len(g.incoming[v]))
+		for source := range g.incoming[v] {
+			sources = append(sources, source)
+		}
+		return sources
+	})
+}
+
+// Descendants returns the vertices to which there is a path from vertex, in
+// no particular order
+func (g *DAG[V, E]) Descendants(vertex V) []V {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.reach(vertex, func(v V) []V {
+		edges, _ := g.vertices.Get(v)
+		return edges.Keys()
+	})
+}
+
+// reach returns the vertices reachable from start, excluding start, by
+// following next.
+func (g *DAG[V, E]) reach(start V, next func(V) []V) []V {
+	result := []V{}
+	if _, exists := g.order[start]; !exists {
+		return result
+	}
+	visited := map[V]bool{start: true}
+	stack := []V{start}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, w := range next(v) {
+			if !visited[w] {
+				visited[w] = true
+				result = append(result, w)
+				stack = append(stack, w)
+			}
+		}
+	}
+	return result
+}
+
+// Ensure DAG implements the Graph interface
+var _ Graph[string, int] = (*DAG[string, int])(nil)
@@ -2,6 +2,7 @@ package graph
 
 import (
 	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/collections/maps"
 	"github.com/ielm/neostd/errors"
 )
 
@@ -31,8 +32,9 @@ func (g *DiGraph[V, E]) AddEdge(source, destination V, weight E) error {
 		return errors.New(errors.ErrNotFound, "destination vertex not found")
 	}
 
-	sourceEdges.Put(destination, weight)
-	g.edgeCount++
+	if _, existed := sourceEdges.Put(destination, weight); !existed {
+		g.edgeCount++
+	}
 	return nil
 }
 
@@ -75,5 +77,32 @@ func (g *DiGraph[V, E]) GetEdges(vertex V) []Edge[V, E] {
 	return result
 }
 
+// Transpose returns a new graph with the same vertices and the direction of
+// every edge reversed, keeping its weight. The transpose has the same
+// strongly connected components as the graph, and edges into a vertex of
+// the graph are edges out of it in the transpose.
+//
+// Example:
+//
+//	callers := calls.Transpose()
+//	fmt.Println(callers.GetNeighbors("parse")) // Functions that call parse
+func (g *DiGraph[V, E]) Transpose() *DiGraph[V, E] {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	t := NewDiGraph[V, E](g.comparator)
+	g.vertices.ForEach(func(v V, _ *maps.HashMap[V, E]) {
+		t.vertices.Put(v, maps.NewHashMap[V, E](g.comparator).Unwrap())
+	})
+	g.vertices.ForEach(func(source V, edges *maps.HashMap[V, E]) {
+		edges.ForEach(func(dest V, weight E) {
+			incoming, _ := t.vertices.Get(dest)
+			incoming.Put(source, weight)
+		})
+	})
+	t.edgeCount = g.edgeCount
+	return t
+}
+
 // Ensure DirectedGraph implements the Graph interface
 var _ Graph[string, int] = (*DiGraph[string, int])(nil)
@@ -0,0 +1,524 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// DOTConfig holds the configuration for DOT export
+type DOTConfig[V comparable, E any] struct {
+	name        string
+	vertexID    func(V) string
+	vertexLabel func(V) string
+	edgeLabel   func(E) string
+}
+
+// DOTOption configures DOT export
+type DOTOption[V comparable, E any] func(*DOTConfig[V, E])
+
+func defaultDOTConfig[V comparable, E any]() DOTConfig[V, E] {
+	return DOTConfig[V, E]{
+		vertexID: func(v V) string { return fmt.Sprint(v) },
+	}
+}
+
+// WithDOTName sets the name of the exported graph.
+func WithDOTName[V comparable, E any](name string) DOTOption[V, E] {
+	return func(c *DOTConfig[V, E]) {
+		c.name = name
+	}
+}
+
+// WithVertexID sets the function that names vertices in the output. It must
+// give each vertex a distinct name. By default, vertices are formatted with
+// fmt.Sprint.
+func WithVertexID[V comparable, E any](id func(V) string) DOTOption[V, E] {
+	return func(c *DOTConfig[V, E]) {
+		c.vertexID = id
+	}
+}
+
+// WithVertexLabel sets the function that labels vertices in the output. By
+// default, vertices have no label attribute, so Graphviz shows their names.
+func WithVertexLabel[V comparable, E any](label func(V) string) DOTOption[V, E] {
+	return func(c *DOTConfig[V, E]) {
+		c.vertexLabel = label
+	}
+}
+
+// WithEdgeLabel sets the function that labels edges, from their weights, in
+// the output. By default, edges have no label.
+func WithEdgeLabel[V comparable, E any](label func(E) string) DOTOption[V, E] {
+	return func(c *DOTConfig[V, E]) {
+		c.edgeLabel = label
+	}
+}
+
+// ToDOT writes the graph to w in the DOT language of Graphviz, as a graph
+// for an undirected graph and as a digraph otherwise. Vertices and edges are
+// sorted by name, so the same graph always produces the same output.
+//
+// Example:
+//
+//	err := graph.ToDOT[string, int](g, os.Stdout,
+//		graph.WithEdgeLabel[string, int](strconv.Itoa))
+//
+// Pipe the output to `dot -Tsvg` to render it.
+func ToDOT[V comparable, E any](g Graph[V, E], w io.Writer, opts ...DOTOption[V, E]) error {
+	config := defaultDOTConfig[V, E]()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	keyword, arrow := "digraph", "->"
+	if IsUndirected(g) {
+		keyword, arrow = "graph", "--"
+	}
+
+	vertices := g.GetVertices()
+	ids := make(map[V]string, len(vertices))
+	for _, v := range vertices {
+		ids[v] = config.vertexID(v)
+	}
+	slices.SortFunc(vertices, func(a, b V) int { return strings.Compare(ids[a], ids[b]) })
+
+	bw := bufio.NewWriter(w)
+	if config.name != "" {
+		fmt.Fprintf(bw, "%s %s {\n", keyword, quoteDOT(config.name))
+	} else {
+		fmt.Fprintf(bw, "%s {\n", keyword)
+	}
+
+	for _, v := range vertices {
+		fmt.Fprintf(bw, "\t%s", quoteDOT(ids[v]))
+		if config.vertexLabel != nil {
+			fmt.Fprintf(bw, " [label=%s]", quoteDOT(config.vertexLabel(v)))
+		}
+		bw.WriteString(";\n")
+	}
+
+	written := make(map[[2]V]bool)
+	for _, v := range vertices {
+		edges := g.GetEdges(v)
+		slices.SortFunc(edges, func(a, b Edge[V, E]) int {
+			return strings.Compare(ids[a.Destination], ids[b.Destination])
+		})
+		for _, edge := range edges {
+			if arrow == "--" {
+				// Undirected edges are listed from both ends; write them once
+				if written[[2]V{edge.Destination, edge.Source}] {
+					continue
+				}
+				written[[2]V{edge.Source, edge.Destination}] = true
+			}
+			fmt.Fprintf(bw, "\t%s %s %s", quoteDOT(ids[edge.Source]), arrow, quoteDOT(ids[edge.Destination]))
+			if config.edgeLabel != nil {
+				fmt.Fprintf(bw, " [label=%s]", quoteDOT(config.edgeLabel(edge.Weight)))
+			}
+			bw.WriteString(";\n")
+		}
+	}
+
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// quoteDOT returns s as a DOT identifier, quoting it unless it is a plain
+// alphanumeric name or a number.
+func quoteDOT(s string) string {
+	if isPlainDOTID(s) {
+		return s
+	}
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+func isPlainDOTID(s string) bool {
+	if s == "" {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "graph", "digraph", "subgraph", "node", "edge", "strict":
+		return false
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "eEinfINFxX+") {
+		return true
+	}
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// FromDOT parses a graph in the DOT language of Graphviz. It returns a
+// DiGraph for a digraph and a UGraph for a graph, with vertices named by
+// their DOT identifiers and edges weighted by their label attribute, or
+// their weight attribute if they have no label, or "" if they have neither.
+//
+// It supports the subset of DOT that ToDOT writes, plus chains of edges
+// (a -> b -> c), comments, and attribute and default statements, which it
+// ignores. Subgraphs, ports and HTML labels are not supported.
+//
+// Example:
+//
+//	result := graph.FromDOT(strings.NewReader(`digraph { a -> b [label=3]; }`))
+//	g := result.Unwrap()
+func FromDOT(r io.Reader) res.Result[Graph[string, string]] {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return res.Err[Graph[string, string]](errors.NewWithCause(errors.ErrInvalidArgument, "failed to read DOT input", err))
+	}
+	p := &dotParser{lexer: dotLexer{input: []rune(string(data)), line: 1}}
+	g, err := p.parse()
+	if err != nil {
+		return res.Err[Graph[string, string]](err)
+	}
+	return res.Ok(g)
+}
+
+// dotToken kinds
+const (
+	dotEOF = iota
+	dotID
+	dotPunct // One of { } [ ] ; , = : or an edge operator
+)
+
+type dotToken struct {
+	kind int
+	text string
+	line int
+}
+
+// dotLexer splits DOT input into tokens.
+type dotLexer struct {
+	input []rune
+	pos   int
+	line  int
+}
+
+func (l *dotLexer) next() (dotToken, error) {
+	if err := l.skipSpace(); err != nil {
+		return dotToken{}, err
+	}
+	if l.pos >= len(l.input) {
+		return dotToken{kind: dotEOF, line: l.line}, nil
+	}
+
+	start, line := l.pos, l.line
+	c := l.input[l.pos]
+	switch {
+	case strings.ContainsRune("{}[];,=:", c):
+		l.pos++
+		return dotToken{kind: dotPunct, text: string(c), line: line}, nil
+	case c == '-' && l.pos+1 < len(l.input) && (l.input[l.pos+1] == '>' || l.input[l.pos+1] == '-'):
+		l.pos += 2
+		return dotToken{kind: dotPunct, text: string(l.input[start:l.pos]), line: line}, nil
+	case c == '"':
+		return l.quoted()
+	case c == '<':
+		return dotToken{}, dotError(line, "HTML labels are not supported")
+	case c == '_' || c == '-' || c == '.' || unicode.IsLetter(c) || unicode.IsDigit(c):
+		l.pos++
+		for l.pos < len(l.input) {
+			c := l.input[l.pos]
+			if c != '_' && c != '.' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+				break
+			}
+			l.pos++
+		}
+		return dotToken{kind: dotID, text: string(l.input[start:l.pos]), line: line}, nil
+	}
+	return dotToken{}, dotError(line, fmt.Sprintf("unexpected character %q", c))
+}
+
+// quoted reads a double-quoted string, handling escaped quotes and
+// backslashes and the + concatenation of quoted strings.
+func (l *dotLexer) quoted() (dotToken, error) {
+	line := l.line
+	var sb strings.Builder
+	for {
+		l.pos++ // Opening quote
+		for {
+			if l.pos >= len(l.input) {
+				return dotToken{}, dotError(line, "unterminated string")
+			}
+			c := l.input[l.pos]
+			l.pos++
+			if c == '"' {
+				break
+			}
+			if c == '\n' {
+				l.line++
+			}
+			if c == '\\' && l.pos < len(l.input) {
+				switch next := l.input[l.pos]; next {
+				case '"', '\\':
+					c = next
+					l.pos++
+				case '\n':
+					// Line continuation
+					l.line++
+					l.pos++
+					continue
+				}
+			}
+			sb.WriteRune(c)
+		}
+
+		// "a" + "b" concatenates
+		save, saveLine := l.pos, l.line
+		if err := l.skipSpace(); err != nil {
+			return dotToken{}, err
+		}
+		if l.pos < len(l.input) && l.input[l.pos] == '+' {
+			l.pos++
+			if err := l.skipSpace(); err != nil {
+				return dotToken{}, err
+			}
+			if l.pos < len(l.input) && l.input[l.pos] == '"' {
+				continue
+			}
+			return dotToken{}, dotError(l.line, "expected string after +")
+		}
+		l.pos, l.line = save, saveLine
+		return dotToken{kind: dotID, text: sb.String(), line: line}, nil
+	}
+}
+
+// skipSpace skips whitespace and comments.
+func (l *dotLexer) skipSpace() error {
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+		case unicode.IsSpace(c):
+			l.pos++
+		case c == '#' || (c == '/' && l.peek(1) == '/'):
+			for l.pos < len(l.input) && l.input[l.pos] != '\n' {
+				l.pos++
+			}
+		case c == '/' && l.peek(1) == '*':
+			line := l.line
+			l.pos += 2
+			for l.pos < len(l.input) && !(l.input[l.pos] == '*' && l.peek(1) == '/') {
+				if l.input[l.pos] == '\n' {
+					l.line++
+				}
+				l.pos++
+			}
+			if l.pos >= len(l.input) {
+				return dotError(line, "unterminated comment")
+			}
+			l.pos += 2
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *dotLexer) peek(offset int) rune {
+	if l.pos+offset < len(l.input) {
+		return l.input[l.pos+offset]
+	}
+	return 0
+}
+
+// dotParser builds a graph from DOT tokens.
+type dotParser struct {
+	lexer dotLexer
+	tok   dotToken
+	graph Graph[string, string]
+	arrow string
+}
+
+func (p *dotParser) advance() error {
+	tok, err := p.lexer.next()
+	p.tok = tok
+	return err
+}
+
+// keyword reports whether the current token is the given DOT keyword; DOT
+// keywords are case-insensitive.
+func (p *dotParser) keyword(k string) bool {
+	return p.tok.kind == dotID && strings.EqualFold(p.tok.text, k)
+}
+
+func (p *dotParser) expect(punct string) error {
+	if p.tok.kind != dotPunct || p.tok.text != punct {
+		return dotError(p.tok.line, fmt.Sprintf("expected %q", punct))
+	}
+	return p.advance()
+}
+
+func (p *dotParser) parse() (Graph[string, string], error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.keyword("strict") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case p.keyword("digraph"):
+		p.graph, p.arrow = NewDiGraph[string, string](comp.GenericComparator[string]()), "->"
+	case p.keyword("graph"):
+		p.graph, p.arrow = NewUGraph[string, string](comp.GenericComparator[string]()), "--"
+	default:
+		return nil, dotError(p.tok.line, "expected graph or digraph")
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == dotID {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for !(p.tok.kind == dotPunct && p.tok.text == "}") {
+		if p.tok.kind == dotEOF {
+			return nil, dotError(p.tok.line, "expected \"}\"")
+		}
+		if err := p.statement(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != dotEOF {
+		return nil, dotError(p.tok.line, "unexpected input after graph")
+	}
+	return p.graph, nil
+}
+
+// statement parses one node, edge or attribute statement.
+func (p *dotParser) statement() error {
+	switch {
+	case p.tok.kind == dotPunct && p.tok.text == ";":
+		return p.advance()
+	case p.keyword("subgraph") || (p.tok.kind == dotPunct && p.tok.text == "{"):
+		return dotError(p.tok.line, "subgraphs are not supported")
+	case p.keyword("graph") || p.keyword("node") || p.keyword("edge"):
+		// Default attributes do not affect the graph's structure
+		if err := p.advance(); err != nil {
+			return err
+		}
+		_, err := p.attributes()
+		return err
+	case p.tok.kind != dotID:
+		return dotError(p.tok.line, fmt.Sprintf("unexpected %q", p.tok.text))
+	}
+
+	first := p.tok.text
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.tok.kind == dotPunct && p.tok.text == "=" {
+		// A graph attribute, such as rankdir=LR
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.tok.kind != dotID {
+			return dotError(p.tok.line, "expected attribute value")
+		}
+		return p.advance()
+	}
+	if p.tok.kind == dotPunct && p.tok.text == ":" {
+		return dotError(p.tok.line, "ports are not supported")
+	}
+
+	chain := []string{first}
+	for p.tok.kind == dotPunct && (p.tok.text == "->" || p.tok.text == "--") {
+		if p.tok.text != p.arrow {
+			return dotError(p.tok.line, fmt.Sprintf("edge operator %s in a graph that uses %s", p.tok.text, p.arrow))
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.tok.kind != dotID {
+			return dotError(p.tok.line, "expected vertex after edge operator")
+		}
+		chain = append(chain, p.tok.text)
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+
+	attrs, err := p.attributes()
+	if err != nil {
+		return err
+	}
+	for _, v := range chain {
+		p.graph.Add(v)
+	}
+	weight, ok := attrs["label"]
+	if !ok {
+		weight = attrs["weight"]
+	}
+	for i := 1; i < len(chain); i++ {
+		if err := p.graph.AddEdge(chain[i-1], chain[i], weight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attributes parses optional attribute lists, such as [a=1, b=2][c=3].
+func (p *dotParser) attributes() (map[string]string, error) {
+	attrs := make(map[string]string)
+	for p.tok.kind == dotPunct && p.tok.text == "[" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for !(p.tok.kind == dotPunct && p.tok.text == "]") {
+			if p.tok.kind != dotID {
+				return nil, dotError(p.tok.line, "expected attribute name")
+			}
+			name := p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != dotID {
+				return nil, dotError(p.tok.line, "expected attribute value")
+			}
+			attrs[name] = p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind == dotPunct && (p.tok.text == "," || p.tok.text == ";") {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
+
+func dotError(line int, message string) error {
+	return errors.New(errors.ErrInvalidArgument, fmt.Sprintf("DOT line %d: %s", line, message))
+}
@@ -0,0 +1,247 @@
+// Package gen generates graphs from classic random and regular models, for
+// benchmarking and testing graph algorithms. Vertices are the integers from
+// 0 to n-1, and the random generators are deterministic for a given seed.
+package gen
+
+import (
+	"math/rand"
+
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/collections/graph"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// GenConfig holds the configuration for the generators
+type GenConfig[E any] struct {
+	weight   func(u, v int) E
+	directed bool
+}
+
+// GenOption configures a generator
+type GenOption[E any] func(*GenConfig[E])
+
+func defaultGenConfig[E any]() GenConfig[E] {
+	return GenConfig[E]{
+		weight: func(int, int) E {
+			var zero E
+			return zero
+		},
+	}
+}
+
+// WithWeight sets the function that gives the weight of the edge from u to
+// v. By default, edges have the zero value of E as their weight.
+//
+// Example:
+//
+//	r := rand.New(rand.NewSource(1))
+//	g := gen.Complete(10, gen.WithWeight(func(u, v int) float64 { return r.Float64() }))
+func WithWeight[E any](weight func(u, v int) E) GenOption[E] {
+	return func(c *GenConfig[E]) {
+		c.weight = weight
+	}
+}
+
+// WithDirected makes ErdosRenyi, Complete and Grid generate a directed
+// graph, with an edge in each direction where the undirected graph would
+// have one edge, or, for ErdosRenyi, with each direction drawn separately.
+// The other generators ignore it.
+func WithDirected[E any]() GenOption[E] {
+	return func(c *GenConfig[E]) {
+		c.directed = true
+	}
+}
+
+// builder adds vertices and edges to a new graph
+type builder[E any] struct {
+	graph  graph.Graph[int, E]
+	weight func(u, v int) E
+}
+
+func newBuilder[E any](n int, directed bool, config GenConfig[E]) *builder[E] {
+	b := &builder[E]{weight: config.weight}
+	if directed {
+		b.graph = graph.NewDiGraph[int, E](comp.GenericComparator[int]())
+	} else {
+		b.graph = graph.NewUGraph[int, E](comp.GenericComparator[int]())
+	}
+	for v := 0; v < n; v++ {
+		b.graph.Add(v)
+	}
+	return b
+}
+
+func (b *builder[E]) edge(u, v int) {
+	b.graph.AddEdge(u, v, b.weight(u, v))
+}
+
+func applyOptions[E any](opts []GenOption[E]) GenConfig[E] {
+	config := defaultGenConfig[E]()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// ErdosRenyi generates a G(n, p) random graph, in which each possible edge
+// between n vertices is present independently with probability p.
+//
+// Example:
+//
+//	g := gen.ErdosRenyi[int](1000, 0.01, 42).Unwrap()
+func ErdosRenyi[E any](n int, p float64, seed int64, opts ...GenOption[E]) res.Result[graph.Graph[int, E]] {
+	if n < 0 {
+		return res.Err[graph.Graph[int, E]](errors.New(errors.ErrInvalidArgument, "number of vertices must not be negative"))
+	}
+	if !(p >= 0 && p <= 1) {
+		return res.Err[graph.Graph[int, E]](errors.New(errors.ErrInvalidArgument, "edge probability must be between 0 and 1"))
+	}
+	config := applyOptions(opts)
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, config.directed, config)
+	for u := 0; u < n; u++ {
+		for v := u + 1; v < n; v++ {
+			if r.Float64() < p {
+				b.edge(u, v)
+			}
+			if config.directed && r.Float64() < p {
+				b.edge(v, u)
+			}
+		}
+	}
+	return res.Ok(b.graph)
+}
+
+// BarabasiAlbert generates an undirected scale-free graph by preferential
+// attachment: starting from a star of m+1 vertices, each new vertex is
+// joined to m distinct existing vertices, chosen with probability
+// proportional to their degree, until there are n vertices. Degrees follow
+// a power law, with a few highly connected hubs.
+//
+// Example:
+//
+//	g := gen.BarabasiAlbert[int](1000, 3, 42).Unwrap()
+func BarabasiAlbert[E any](n, m int, seed int64, opts ...GenOption[E]) res.Result[graph.Graph[int, E]] {
+	if m < 1 || m >= n {
+		return res.Err[graph.Graph[int, E]](errors.New(errors.ErrInvalidArgument, "edges per vertex must be at least 1 and less than the number of vertices"))
+	}
+	config := applyOptions(opts)
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, false, config)
+
+	// Each vertex appears in ends once per incident edge, so a uniform pick
+	// from ends picks a vertex with probability proportional to its degree
+	ends := make([]int, 0, 2*m*n)
+	for v := 1; v <= m; v++ {
+		b.edge(0, v)
+		ends = append(ends, 0, v)
+	}
+	targets := make(map[int]bool, m)
+	for v := m + 1; v < n; v++ {
+		clear(targets)
+		for len(targets) < m {
+			targets[ends[r.Intn(len(ends))]] = true
+		}
+		// Add in increasing order so that the result depends only on seed
+		for u := 0; u < v; u++ {
+			if targets[u] {
+				b.edge(v, u)
+				ends = append(ends, v, u)
+			}
+		}
+	}
+	return res.Ok(b.graph)
+}
+
+// WattsStrogatz generates an undirected small-world graph: a ring of n
+// vertices, each joined to its k nearest neighbors, k/2 on each side, in
+// which each edge is then rewired with probability beta to a random vertex.
+// A small beta keeps the ring's high clustering while adding shortcuts that
+// make paths short.
+//
+// Example:
+//
+//	g := gen.WattsStrogatz[int](1000, 6, 0.1, 42).Unwrap()
+func WattsStrogatz[E any](n, k int, beta float64, seed int64, opts ...GenOption[E]) res.Result[graph.Graph[int, E]] {
+	if k < 2 || k%2 != 0 || k >= n {
+		return res.Err[graph.Graph[int, E]](errors.New(errors.ErrInvalidArgument, "neighbors must be even, at least 2 and less than the number of vertices"))
+	}
+	if !(beta >= 0 && beta <= 1) {
+		return res.Err[graph.Graph[int, E]](errors.New(errors.ErrInvalidArgument, "rewiring probability must be between 0 and 1"))
+	}
+	config := applyOptions(opts)
+	r := rand.New(rand.NewSource(seed))
+	b := newBuilder(n, false, config)
+	for u := 0; u < n; u++ {
+		for j := 1; j <= k/2; j++ {
+			b.edge(u, (u+j)%n)
+		}
+	}
+
+	for j := 1; j <= k/2; j++ {
+		for u := 0; u < n; u++ {
+			v := (u + j) % n
+			if r.Float64() >= beta || len(b.graph.GetNeighbors(u)) >= n-1 {
+				continue
+			}
+			w := r.Intn(n)
+			for w == u || b.graph.HasEdge(u, w) {
+				w = r.Intn(n)
+			}
+			b.graph.RemoveEdge(u, v)
+			b.edge(u, w)
+		}
+	}
+	return res.Ok(b.graph)
+}
+
+// Complete generates the complete graph on n vertices, with an edge between
+// every pair of distinct vertices.
+//
+// Example:
+//
+//	g := gen.Complete[int](10)
+func Complete[E any](n int, opts ...GenOption[E]) graph.Graph[int, E] {
+	config := applyOptions(opts)
+	b := newBuilder(max(n, 0), config.directed, config)
+	for u := 0; u < n; u++ {
+		for v := u + 1; v < n; v++ {
+			b.edge(u, v)
+			if config.directed {
+				b.edge(v, u)
+			}
+		}
+	}
+	return b.graph
+}
+
+// Grid generates a width by height lattice, in which the vertex y*width+x
+// is joined to its horizontal and vertical neighbors.
+//
+// Example:
+//
+//	g := gen.Grid[int](10, 10)
+func Grid[E any](width, height int, opts ...GenOption[E]) graph.Graph[int, E] {
+	config := applyOptions(opts)
+	width, height = max(width, 0), max(height, 0)
+	b := newBuilder(width*height, config.directed, config)
+	link := func(u, v int) {
+		b.edge(u, v)
+		if config.directed {
+			b.edge(v, u)
+		}
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := y*width + x
+			if x+1 < width {
+				link(v, v+1)
+			}
+			if y+1 < height {
+				link(v, v+width)
+			}
+		}
+	}
+	return b.graph
+}
@@ -29,6 +29,21 @@ type Graph[V comparable, E any] interface {
 	HasEdge(source, destination V) bool
 	GetWeight(source, destination V) (E, bool)
 	SetWeight(source, destination V, weight E) error
+	GetAllEdges() []Edge[V, E]
+	EdgeCount() int
+	EdgeIterator() collections.Iterator[Edge[V, E]]
+}
+
+// IsUndirected reports whether g is an undirected graph, or a view of one,
+// whose edges can be followed both ways.
+func IsUndirected[V comparable, E any](g Graph[V, E]) bool {
+	switch g := g.(type) {
+	case *UGraph[V, E]:
+		return true
+	case *subgraph[V, E]:
+		return IsUndirected(g.parent)
+	}
+	return false
 }
 
 // baseGraph is the common implementation for both directed and undirected graphs
@@ -38,6 +53,7 @@ type Graph[V comparable, E any] interface {
 type baseGraph[V comparable, E any] struct {
 	vertices   *maps.HashMap[V, *maps.HashMap[V, E]]
 	edgeCount  int
+	undirected bool
 	comparator comp.Comparator[V]
 	mu         sync.RWMutex
 }
@@ -75,8 +91,10 @@ func (g *baseGraph[V, E]) Remove(vertex V) bool {
 	g.edgeCount -= edges.Size()
 	g.vertices.Remove(vertex)
 
+	// In an undirected graph, the edges into the vertex are the edges
+	// already counted above, seen from their other end
 	g.vertices.ForEach(func(v V, edges *maps.HashMap[V, E]) {
-		if _, ok := edges.Remove(vertex); ok {
+		if _, ok := edges.Remove(vertex); ok && !g.undirected {
 			g.edgeCount--
 		}
 	})
@@ -194,6 +212,45 @@ func (g *baseGraph[V, E]) SetWeight(source, destination V, weight E) error {
 	return nil
 }
 
+// EdgeCount returns the number of edges in the graph
+func (g *baseGraph[V, E]) EdgeCount() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.edgeCount
+}
+
+// GetAllEdges returns all edges of the graph. In an undirected graph, each
+// edge appears once, in one of its two directions.
+func (g *baseGraph[V, E]) GetAllEdges() []Edge[V, E] {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	result := make([]Edge[V, E], 0, g.edgeCount)
+	var seen map[[2]V]bool
+	if g.undirected {
+		seen = make(map[[2]V]bool, g.edgeCount)
+	}
+	g.vertices.ForEach(func(source V, edges *maps.HashMap[V, E]) {
+		edges.ForEach(func(dest V, weight E) {
+			if g.undirected {
+				if seen[[2]V{dest, source}] {
+					return
+				}
+				seen[[2]V{source, dest}] = true
+			}
+			result = append(result, Edge[V, E]{Source: source, Destination: dest, Weight: weight})
+		})
+	})
+	return result
+}
+
+// EdgeIterator returns an iterator over a snapshot of the edges of the
+// graph, as returned by GetAllEdges
+func (g *baseGraph[V, E]) EdgeIterator() collections.Iterator[Edge[V, E]] {
+	return &edgeIterator[V, E]{edges: g.GetAllEdges()}
+}
+
 // Iterator returns an iterator over the vertices of the graph
 func (g *baseGraph[V, E]) Iterator() collections.Iterator[V] {
 	return &graphIterator[V, E]{
@@ -241,5 +298,23 @@ func (it *graphIterator[V, E]) Next() res.Option[V] {
 	return res.Some(vertex)
 }
 
+type edgeIterator[V comparable, E any] struct {
+	edges []Edge[V, E]
+	index int
+}
+
+func (it *edgeIterator[V, E]) HasNext() bool {
+	return it.index < len(it.edges)
+}
+
+func (it *edgeIterator[V, E]) Next() res.Option[Edge[V, E]] {
+	if !it.HasNext() {
+		return res.None[Edge[V, E]]()
+	}
+	edge := it.edges[it.index]
+	it.index++
+	return res.Some(edge)
+}
+
 // Ensure baseGraph implements the Collection interface
 var _ collections.Collection[string] = (*baseGraph[string, int])(nil)
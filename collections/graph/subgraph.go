@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/errors"
+)
+
+// Subgraph returns a read-only view of the vertices of g that satisfy
+// vertexPred and the edges between them that satisfy edgePred. A nil
+// predicate accepts everything. The view copies nothing: it filters g on
+// each call, so it reflects later changes to g, and algorithms that accept
+// a Graph can run against a slice of a large graph without copying it.
+//
+// Size and EdgeCount walk the whole graph, and GetVertices and GetEdges
+// filter it, so an algorithm that calls them repeatedly pays the filtering
+// cost each time. In an undirected graph, edgePred should not depend on the
+// direction of the edge.
+//
+// The view's mutating methods fail: Add and Remove return false, AddEdge,
+// RemoveEdge and SetWeight return an error, and Clear and SetComparator do
+// nothing.
+//
+// Example:
+//
+//	local := graph.Subgraph[string, int](g,
+//		func(v string) bool { return region[v] == "eu" },
+//		func(e graph.Edge[string, int]) bool { return e.Weight < 100 })
+//	result := dijkstra.Dijkstra[string, int](local, "paris", less, 0, add)
+func Subgraph[V comparable, E any](g Graph[V, E], vertexPred func(V) bool, edgePred func(Edge[V, E]) bool) Graph[V, E] {
+	return &subgraph[V, E]{parent: g, vertexPred: vertexPred, edgePred: edgePred}
+}
+
+// errReadOnly is returned by the mutating methods of a subgraph view
+var errReadOnly = errors.New(errors.ErrNotImplemented, "subgraph view is read-only")
+
+type subgraph[V comparable, E any] struct {
+	parent     Graph[V, E]
+	vertexPred func(V) bool
+	edgePred   func(Edge[V, E]) bool
+}
+
+func (s *subgraph[V, E]) hasVertex(v V) bool {
+	return s.vertexPred == nil || s.vertexPred(v)
+}
+
+func (s *subgraph[V, E]) hasEdge(edge Edge[V, E]) bool {
+	return s.hasVertex(edge.Source) && s.hasVertex(edge.Destination) &&
+		(s.edgePred == nil || s.edgePred(edge))
+}
+
+// filterEdges keeps the edges of the view, reusing the slice
+func (s *subgraph[V, E]) filterEdges(edges []Edge[V, E]) []Edge[V, E] {
+	result := edges[:0]
+	for _, edge := range edges {
+		if s.hasEdge(edge) {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+func (s *subgraph[V, E]) Contains(vertex V) bool {
+	return s.hasVertex(vertex) && s.parent.Contains(vertex)
+}
+
+func (s *subgraph[V, E]) GetVertices() []V {
+	vertices := s.parent.GetVertices()
+	result := vertices[:0]
+	for _, v := range vertices {
+		if s.hasVertex(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func (s *subgraph[V, E]) Size() int {
+	return len(s.GetVertices())
+}
+
+func (s *subgraph[V, E]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+func (s *subgraph[V, E]) GetEdges(vertex V) []Edge[V, E] {
+	if !s.hasVertex(vertex) {
+		return []Edge[V, E]{}
+	}
+	return s.filterEdges(s.parent.GetEdges(vertex))
+}
+
+func (s *subgraph[V, E]) GetNeighbors(vertex V) []V {
+	edges := s.GetEdges(vertex)
+	result := make([]V, len(edges))
+	for i, edge := range edges {
+		result[i] = edge.Destination
+	}
+	return result
+}
+
+func (s *subgraph[V, E]) GetWeight(source, destination V) (E, bool) {
+	weight, ok := s.parent.GetWeight(source, destination)
+	if !ok || !s.hasEdge(Edge[V, E]{Source: source, Destination: destination, Weight: weight}) {
+		var zero E
+		return zero, false
+	}
+	return weight, true
+}
+
+func (s *subgraph[V, E]) GetEdge(source, destination V) (E, bool) {
+	return s.GetWeight(source, destination)
+}
+
+func (s *subgraph[V, E]) HasEdge(source, destination V) bool {
+	_, ok := s.GetWeight(source, destination)
+	return ok
+}
+
+func (s *subgraph[V, E]) GetAllEdges() []Edge[V, E] {
+	return s.filterEdges(s.parent.GetAllEdges())
+}
+
+func (s *subgraph[V, E]) EdgeCount() int {
+	return len(s.GetAllEdges())
+}
+
+func (s *subgraph[V, E]) EdgeIterator() collections.Iterator[Edge[V, E]] {
+	return &edgeIterator[V, E]{edges: s.GetAllEdges()}
+}
+
+func (s *subgraph[V, E]) Iterator() collections.Iterator[V] {
+	return &graphIterator[V, E]{keys: s.GetVertices()}
+}
+
+func (s *subgraph[V, E]) ReverseIterator() collections.Iterator[V] {
+	vertices := s.GetVertices()
+	return &graphIterator[V, E]{keys: vertices, index: len(vertices) - 1, reverse: true}
+}
+
+func (s *subgraph[V, E]) Comparator() comp.Comparator[V] {
+	return s.parent.Comparator()
+}
+
+func (s *subgraph[V, E]) Add(V) bool    { return false }
+func (s *subgraph[V, E]) Remove(V) bool { return false }
+func (s *subgraph[V, E]) Clear()        {}
+
+func (s *subgraph[V, E]) SetComparator(comp.Comparator[V]) {}
+
+func (s *subgraph[V, E]) AddEdge(V, V, E) error {
+	return errReadOnly
+}
+
+func (s *subgraph[V, E]) RemoveEdge(V, V) error {
+	return errReadOnly
+}
+
+func (s *subgraph[V, E]) SetWeight(V, V, E) error {
+	return errReadOnly
+}
+
+// Ensure subgraph implements the Graph interface
+var _ Graph[string, int] = (*subgraph[string, int])(nil)
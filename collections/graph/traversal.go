@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/res"
+)
+
+// TraversalConfig holds the configuration for BFS and DFS iterators
+type TraversalConfig struct {
+	maxDepth int
+}
+
+// TraversalOption configures a BFS or DFS iterator
+type TraversalOption func(*TraversalConfig)
+
+func defaultTraversalConfig() TraversalConfig {
+	return TraversalConfig{maxDepth: -1}
+}
+
+// WithMaxDepth limits a traversal to vertices at most depth edges away from
+// the start vertex; a depth of 0 visits only the start vertex. By default
+// the depth is unlimited.
+func WithMaxDepth(depth int) TraversalOption {
+	return func(c *TraversalConfig) {
+		c.maxDepth = depth
+	}
+}
+
+// visit is a vertex waiting to be visited and its depth
+type visit[V any] struct {
+	vertex V
+	depth  int
+}
+
+// BFSIterator returns an iterator over the vertices reachable from start, in
+// breadth-first order: start first, then its neighbors, then theirs, and so
+// on. Each vertex is visited once, and neighbors are only looked up when the
+// iterator reaches their vertex, so stopping early does not explore the rest
+// of the graph. If start is not in the graph, the iterator is empty.
+//
+// Example:
+//
+//	it := graph.BFSIterator[string, int](g, "a", graph.WithMaxDepth(2))
+//	for it.HasNext() {
+//		fmt.Println(it.Next().Unwrap())
+//	}
+func BFSIterator[V comparable, E any](g Graph[V, E], start V, opts ...TraversalOption) collections.Iterator[V] {
+	config := defaultTraversalConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	it := &bfsIterator[V, E]{
+		graph:    g,
+		maxDepth: config.maxDepth,
+		visited:  make(map[V]struct{}),
+	}
+	if g.Contains(start) {
+		it.queue = append(it.queue, visit[V]{vertex: start})
+		it.visited[start] = struct{}{}
+	}
+	return it
+}
+
+type bfsIterator[V comparable, E any] struct {
+	graph    Graph[V, E]
+	maxDepth int
+	queue    []visit[V]
+	visited  map[V]struct{}
+}
+
+func (it *bfsIterator[V, E]) HasNext() bool {
+	return len(it.queue) > 0
+}
+
+func (it *bfsIterator[V, E]) Next() res.Option[V] {
+	if !it.HasNext() {
+		return res.None[V]()
+	}
+	current := it.queue[0]
+	it.queue[0] = visit[V]{}
+	it.queue = it.queue[1:]
+
+	if it.maxDepth < 0 || current.depth < it.maxDepth {
+		for _, neighbor := range it.graph.GetNeighbors(current.vertex) {
+			if _, seen := it.visited[neighbor]; !seen {
+				it.visited[neighbor] = struct{}{}
+				it.queue = append(it.queue, visit[V]{vertex: neighbor, depth: current.depth + 1})
+			}
+		}
+	}
+	return res.Some(current.vertex)
+}
+
+// DFSIterator returns an iterator over the vertices reachable from start, in
+// depth-first preorder: each vertex comes before the vertices discovered
+// through it, and the iterator follows one branch as deep as it goes before
+// backtracking. Each vertex is visited once, and neighbors are only looked
+// up when the iterator reaches their vertex. If start is not in the graph,
+// the iterator is empty.
+//
+// With WithMaxDepth, depth is measured along the path the search took, which
+// may be longer than the shortest path to a vertex.
+//
+// Example:
+//
+//	it := graph.DFSIterator[string, int](g, "a")
+//	for it.HasNext() {
+//		fmt.Println(it.Next().Unwrap())
+//	}
+func DFSIterator[V comparable, E any](g Graph[V, E], start V, opts ...TraversalOption) collections.Iterator[V] {
+	config := defaultTraversalConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	it := &dfsIterator[V, E]{
+		graph:    g,
+		maxDepth: config.maxDepth,
+		visited:  make(map[V]struct{}),
+	}
+	if g.Contains(start) {
+		it.stack = append(it.stack, visit[V]{vertex: start})
+	}
+	return it
+}
+
+type dfsIterator[V comparable, E any] struct {
+	graph    Graph[V, E]
+	maxDepth int
+	stack    []visit[V]
+	visited  map[V]struct{}
+}
+
+// HasNext discards stack entries for vertices that were visited after they
+// were pushed.
+func (it *dfsIterator[V, E]) HasNext() bool {
+	for len(it.stack) > 0 {
+		if _, seen := it.visited[it.stack[len(it.stack)-1].vertex]; !seen {
+			return true
+		}
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+	return false
+}
+
+func (it *dfsIterator[V, E]) Next() res.Option[V] {
+	if !it.HasNext() {
+		return res.None[V]()
+	}
+	current := it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	it.visited[current.vertex] = struct{}{}
+
+	if it.maxDepth < 0 || current.depth < it.maxDepth {
+		// Push in reverse so that the first neighbor is visited first
+		neighbors := it.graph.GetNeighbors(current.vertex)
+		for i := len(neighbors) - 1; i >= 0; i-- {
+			if _, seen := it.visited[neighbors[i]]; !seen {
+				it.stack = append(it.stack, visit[V]{vertex: neighbors[i], depth: current.depth + 1})
+			}
+		}
+	}
+	return res.Some(current.vertex)
+}
+
+// Ensure the traversal iterators implement the Iterator interface
+var (
+	_ collections.Iterator[string] = (*bfsIterator[string, int])(nil)
+	_ collections.Iterator[string] = (*dfsIterator[string, int])(nil)
+)
@@ -12,9 +12,11 @@ type UGraph[V comparable, E any] struct {
 
 // NewUGraph creates a new undirected graph
 func NewUGraph[V comparable, E any](comparator comp.Comparator[V]) *UGraph[V, E] {
-	return &UGraph[V, E]{
+	g := &UGraph[V, E]{
 		baseGraph: newBaseGraph[V, E](comparator),
 	}
+	g.undirected = true
+	return g
 }
 
 // AddEdge adds an undirected edge to the graph
@@ -32,9 +34,11 @@ func (g *UGraph[V, E]) AddEdge(source, destination V, weight E) error {
 		return errors.New(errors.ErrNotFound, "destination vertex not found")
 	}
 
-	sourceEdges.Put(destination, weight)
+	_, existed := sourceEdges.Put(destination, weight)
 	destEdges.Put(source, weight)
-	g.edgeCount++
+	if !existed {
+		g.edgeCount++
+	}
 	return nil
 }
 
@@ -83,5 +87,26 @@ func (g *UGraph[V, E]) GetEdges(vertex V) []Edge[V, E] {
 	return result
 }
 
+// ToUndirected returns a new undirected graph with the vertices of g and an
+// edge between every pair of vertices joined by an edge of g, in either
+// direction, with the same weight. If g has edges in both directions
+// between two vertices, which have different weights, the undirected edge
+// takes the weight of either one of them.
+//
+// Example:
+//
+//	u := graph.ToUndirected[string, int](dg)
+//	fmt.Println(graph.HasCycle[string, int](u))
+func ToUndirected[V comparable, E any](g Graph[V, E]) *UGraph[V, E] {
+	u := NewUGraph[V, E](g.Comparator())
+	for _, v := range g.GetVertices() {
+		u.Add(v)
+	}
+	for _, edge := range g.GetAllEdges() {
+		u.AddEdge(edge.Source, edge.Destination, edge.Weight)
+	}
+	return u
+}
+
 // Ensure UndirectedGraph implements the Graph interface
 var _ Graph[string, int] = (*UGraph[string, int])(nil)
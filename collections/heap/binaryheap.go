@@ -1,8 +1,11 @@
 package heap
 
 import (
+	"fmt"
+
 	"github.com/ielm/neostd/collections"
 	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/errors"
 	"github.com/ielm/neostd/res"
 )
 
@@ -11,6 +14,7 @@ import (
 type BinaryHeap[T any] struct {
 	data       []T
 	comparator comp.Comparator[T]
+	debug      bool
 }
 
 // NewBinaryHeap creates a new BinaryHeap with the given comparator.
@@ -51,6 +55,7 @@ func NewMinBinaryHeap[T any](comparator comp.Comparator[T]) *BinaryHeap[T] {
 func (h *BinaryHeap[T]) Push(item T) {
 	h.data = append(h.data, item)
 	h.siftUp(len(h.data) - 1)
+	h.checkInvariant()
 }
 
 // Pop removes and returns the top element from the heap.
@@ -76,6 +81,7 @@ func (h *BinaryHeap[T]) Pop() res.Option[T] {
 	if !h.IsEmpty() {
 		h.siftDown(0)
 	}
+	h.checkInvariant()
 	return res.Some(max)
 }
 
@@ -201,7 +207,74 @@ func (it *heapIterator[T]) Next() res.Option[T] {
 	return res.Some(item)
 }
 
-// IntoSortedVec returns a sorted vector of the heap's elements.
+// PushPop pushes item onto the heap and then pops and returns the top element.
+// This is faster than calling Push followed by Pop, since it sifts at most once,
+// and if item would come out on top it is returned without touching the heap.
+//
+// Example:
+//
+//	// Keep the k smallest values seen so far in a max-heap of size k.
+//	largest := h.PushPop(x)
+func (h *BinaryHeap[T]) PushPop(item T) T {
+	if h.IsEmpty() || h.comparator(item, h.data[0]) >= 0 {
+		return item
+	}
+	top := h.data[0]
+	h.data[0] = item
+	h.siftDown(0)
+	h.checkInvariant()
+	return top
+}
+
+// Replace pops the top element and then pushes item, returning the popped element.
+// This is faster than calling Pop followed by Push, since it sifts only once.
+// Unlike PushPop, the returned element may be less than item.
+// If the heap is empty, item is pushed and None is returned.
+//
+// Example:
+//
+//	old := h.Replace(42)
+func (h *BinaryHeap[T]) Replace(item T) res.Option[T] {
+	if h.IsEmpty() {
+		h.Push(item)
+		return res.None[T]()
+	}
+	top := h.data[0]
+	h.data[0] = item
+	h.siftDown(0)
+	h.checkInvariant()
+	return res.Some(top)
+}
+
+// DrainSorted returns an iterator that removes elements from the heap in the
+// order they would be popped: descending for a max-heap and ascending for a
+// min-heap (created with NewMinBinaryHeap). Elements are popped lazily, so
+// stopping early leaves the remaining elements in the heap.
+//
+// Example:
+//
+//	it := heap.DrainSorted()
+//	for it.HasNext() {
+//		fmt.Println(it.Next().Unwrap())
+//	}
+func (h *BinaryHeap[T]) DrainSorted() collections.Iterator[T] {
+	return &drainSortedIterator[T]{heap: h}
+}
+
+type drainSortedIterator[T any] struct {
+	heap *BinaryHeap[T]
+}
+
+func (it *drainSortedIterator[T]) HasNext() bool {
+	return !it.heap.IsEmpty()
+}
+
+func (it *drainSortedIterator[T]) Next() res.Option[T] {
+	return it.heap.Pop()
+}
+
+// IntoSortedVec removes every element from the heap and returns them in the
+// order they would be popped.
 // For a max-heap, this returns the elements in descending order.
 // For a min-heap (created with NewMinBinaryHeap), this returns the elements in ascending order.
 //
@@ -211,17 +284,8 @@ func (it *heapIterator[T]) Next() res.Option[T] {
 //	fmt.Printf("Sorted elements: %v\n", sortedSlice)
 func (h *BinaryHeap[T]) IntoSortedVec() []T {
 	result := make([]T, 0, len(h.data))
-	for len(h.data) > 0 {
-		if popped := h.Pop(); popped.IsSome() {
-			result = append(result, popped.Unwrap())
-		} else {
-			// This shouldn't happen, but we handle it gracefully
-			break
-		}
-	}
-	// Reverse the slice since we popped in reverse order
-	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
-		result[i], result[j] = result[j], result[i]
+	for !h.IsEmpty() {
+		result = append(result, h.Pop().Unwrap())
 	}
 	return result
 }
@@ -239,4 +303,51 @@ func (h *BinaryHeap[T]) SetComparator(comparator comp.Comparator[T]) {
 	for i := len(h.data)/2 - 1; i >= 0; i-- {
 		h.siftDown(i)
 	}
+	h.checkInvariant()
 }
+
+// Validate checks that every element is ordered no greater than its parent
+// under the heap's comparator, and returns an error describing the first
+// violation found. A violation means the comparator is not a consistent
+// ordering, or that elements were mutated while in the heap.
+//
+// Example:
+//
+//	if err := heap.Validate(); err != nil {
+//		log.Fatal(err)
+//	}
+func (h *BinaryHeap[T]) Validate() error {
+	for i := 1; i < len(h.data); i++ {
+		parent := (i - 1) / 2
+		if h.comparator(h.data[i], h.data[parent]) > 0 {
+			return errors.New(errors.ErrInternal, fmt.Sprintf("heap invariant violated: element at index %d is ordered above its parent at index %d", i, parent))
+		}
+	}
+	return nil
+}
+
+// SetDebug enables or disables debug mode. In debug mode the heap calls
+// Validate after every mutating operation and panics if the invariant does
+// not hold, which pinpoints the operation at which a faulty comparator or an
+// external mutation first broke the heap. Validation is O(n), so debug mode
+// should not be left on in production.
+//
+// Example:
+//
+//	heap.SetDebug(true)
+func (h *BinaryHeap[T]) SetDebug(enabled bool) {
+	h.debug = enabled
+}
+
+// checkInvariant panics if debug mode is enabled and the heap is invalid.
+func (h *BinaryHeap[T]) checkInvariant() {
+	if !h.debug {
+		return
+	}
+	if err := h.Validate(); err != nil {
+		panic(err)
+	}
+}
+
+// Ensure BinaryHeap implements the Heap interface
+var _ Heap[int] = (*BinaryHeap[int])(nil)
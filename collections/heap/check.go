@@ -0,0 +1,115 @@
+package heap
+
+import (
+	"fmt"
+
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/errors"
+)
+
+// CheckWeakOrdering reports whether comparator is a strict weak ordering over
+// the given samples, which is what every heap in this package requires.
+// It checks that:
+//
+//   - every element compares equal to itself (reflexivity),
+//   - swapping the arguments flips the sign of the result (antisymmetry),
+//   - a < b and b < c imply a < c (transitivity), and
+//   - elements that compare equal to a common element compare equal to each
+//     other (transitivity of equivalence).
+//
+// It returns an error describing the first violation found. Every triple of
+// samples is examined, so the check runs in O(n^3) time and is intended for
+// tests with a few dozen hand-picked or randomly generated values.
+//
+// Example:
+//
+//	func TestByPriority(t *testing.T) {
+//		samples := []task{{"a", 1}, {"b", 2}, {"c", 2}}
+//		if err := heap.CheckWeakOrdering(byPriority, samples); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+func CheckWeakOrdering[T any](comparator comp.Comparator[T], samples []T) error {
+	for i, a := range samples {
+		if comparator(a, a) != 0 {
+			return orderingError(fmt.Sprintf("sample %d does not compare equal to itself", i))
+		}
+		for j, b := range samples {
+			if sign(comparator(a, b)) != -sign(comparator(b, a)) {
+				return orderingError(fmt.Sprintf("samples %d and %d are not antisymmetric", i, j))
+			}
+		}
+	}
+
+	for i, a := range samples {
+		for j, b := range samples {
+			ab := sign(comparator(a, b))
+			for k, c := range samples {
+				bc := sign(comparator(b, c))
+				ac := sign(comparator(a, c))
+				switch {
+				case ab < 0 && bc < 0 && ac >= 0:
+					return orderingError(fmt.Sprintf("samples %d < %d < %d but not %d < %d", i, j, k, i, k))
+				case ab == 0 && bc == 0 && ac != 0:
+					return orderingError(fmt.Sprintf("samples %d == %d == %d but not %d == %d", i, j, k, i, k))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckHeap pushes items onto a heap created by newHeap, pops them all, and
+// verifies that the heap returned every item, in non-increasing order under
+// comparator. It can be used to test a custom comparator together with any
+// Heap implementation, or a custom Heap implementation itself. For a
+// min-heap constructor, pass comp.ReverseComparator of the comparator.
+//
+// Example:
+//
+//	err := heap.CheckHeap(func(c comp.Comparator[int]) heap.Heap[int] {
+//		return heap.NewDaryHeap(c)
+//	}, comp.GenericComparator[int](), []int{5, 3, 8, 1})
+func CheckHeap[T any](newHeap func(comp.Comparator[T]) Heap[T], comparator comp.Comparator[T], items []T) error {
+	h := newHeap(comparator)
+	for _, item := range items {
+		h.Push(item)
+	}
+	if h.Len() != len(items) {
+		return orderingError(fmt.Sprintf("heap holds %d elements after %d pushes", h.Len(), len(items)))
+	}
+
+	for n := 0; n < len(items); n++ {
+		top := h.Peek()
+		popped := h.Pop()
+		if popped.IsNone() {
+			return orderingError(fmt.Sprintf("heap empty after %d of %d pops", n, len(items)))
+		}
+		item := popped.Unwrap()
+		if comparator(top.Unwrap(), item) != 0 {
+			return orderingError(fmt.Sprintf("pop %d returned a different element than Peek", n))
+		}
+		if next := h.Peek(); next.IsSome() && comparator(next.Unwrap(), item) > 0 {
+			return orderingError(fmt.Sprintf("pop %d returned an element ordered below the next one", n))
+		}
+	}
+	if !h.IsEmpty() {
+		return orderingError(fmt.Sprintf("heap holds %d elements after popping all", h.Len()))
+	}
+	return nil
+}
+
+func orderingError(message string) error {
+	return errors.New(errors.ErrInvalidArgument, message)
+}
+
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	default:
+		return 0
+	}
+}
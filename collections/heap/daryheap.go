@@ -0,0 +1,209 @@
+package heap
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// DefaultArity is the number of children per node used by NewDaryHeap.
+const DefaultArity = 4
+
+// DaryHeap is a priority queue implemented with a d-ary heap, in which every
+// node has up to d children instead of two.
+//
+// A wider heap is shallower, so pushes do fewer comparisons and swaps, while
+// pops compare more children per level. Because the children of a node are
+// adjacent in memory, a 4-ary heap makes better use of the cache once the heap
+// outgrows it: in BenchmarkPushPop it beats BinaryHeap at a million elements,
+// and is on par or slightly slower below that. Measure with the benchmarks in
+// heap_test.go before switching. DaryHeap implements the same Heap interface
+// as BinaryHeap and can be used as a drop-in replacement.
+// By default, this is a max-heap. To use it as a min-heap, use the NewMinDaryHeap function.
+type DaryHeap[T any] struct {
+	data       []T
+	arity      int
+	comparator comp.Comparator[T]
+}
+
+// NewDaryHeap creates a new 4-ary max-heap with the given comparator.
+//
+// Example:
+//
+//	maxHeap := heap.NewDaryHeap(comp.GenericComparator[int]())
+func NewDaryHeap[T any](comparator comp.Comparator[T]) *DaryHeap[T] {
+	return &DaryHeap[T]{
+		data:       make([]T, 0),
+		arity:      DefaultArity,
+		comparator: comparator,
+	}
+}
+
+// NewMinDaryHeap creates a new 4-ary heap that functions as a min-heap.
+// It uses the provided comparator but reverses the comparison.
+//
+// Example:
+//
+//	minHeap := heap.NewMinDaryHeap(comp.GenericComparator[int]())
+func NewMinDaryHeap[T any](comparator comp.Comparator[T]) *DaryHeap[T] {
+	return NewDaryHeap(func(a, b T) int {
+		return -comparator(a, b)
+	})
+}
+
+// NewDaryHeapWithArity creates a new max-heap with the given number of
+// children per node. It returns an error if arity is less than 2.
+//
+// Example:
+//
+//	h, err := heap.NewDaryHeapWithArity(8, comp.GenericComparator[int]())
+func NewDaryHeapWithArity[T any](arity int, comparator comp.Comparator[T]) (*DaryHeap[T], error) {
+	if arity < 2 {
+		return nil, errors.New(errors.ErrInvalidArgument, "heap arity must be at least 2")
+	}
+	h := NewDaryHeap(comparator)
+	h.arity = arity
+	return h, nil
+}
+
+// Arity returns the number of children per node.
+func (h *DaryHeap[T]) Arity() int {
+	return h.arity
+}
+
+// Push adds an element to the heap.
+func (h *DaryHeap[T]) Push(item T) {
+	h.data = append(h.data, item)
+	h.siftUp(len(h.data) - 1)
+}
+
+// Pop removes and returns the top element from the heap.
+// If the heap is empty, it returns None.
+func (h *DaryHeap[T]) Pop() res.Option[T] {
+	if h.IsEmpty() {
+		return res.None[T]()
+	}
+
+	top := h.data[0]
+	lastIdx := len(h.data) - 1
+	h.data[0] = h.data[lastIdx]
+	h.data[lastIdx] = *new(T)
+	h.data = h.data[:lastIdx]
+	if !h.IsEmpty() {
+		h.siftDown(0)
+	}
+	return res.Some(top)
+}
+
+// Peek returns the top element without removing it.
+// If the heap is empty, it returns None.
+func (h *DaryHeap[T]) Peek() res.Option[T] {
+	if h.IsEmpty() {
+		return res.None[T]()
+	}
+	return res.Some(h.data[0])
+}
+
+// Contains checks if the heap contains the given item.
+func (h *DaryHeap[T]) Contains(item T) bool {
+	for _, v := range h.data {
+		if h.comparator(v, item) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *DaryHeap[T]) IsEmpty() bool {
+	return len(h.data) == 0
+}
+
+// Len returns the number of elements in the heap.
+func (h *DaryHeap[T]) Len() int {
+	return len(h.data)
+}
+
+// Clear removes all elements from the heap.
+func (h *DaryHeap[T]) Clear() {
+	clear(h.data)
+	h.data = h.data[:0]
+}
+
+// SetComparator sets a new comparator for the heap and rebuilds it in O(n).
+func (h *DaryHeap[T]) SetComparator(comparator comp.Comparator[T]) {
+	h.comparator = comparator
+	if len(h.data) < 2 {
+		// (len-2)/arity truncates toward zero, so an empty heap would sift index 0
+		return
+	}
+	for i := (len(h.data) - 2) / h.arity; i >= 0; i-- {
+		h.siftDown(i)
+	}
+}
+
+// Iterator returns an iterator over the heap's elements in arbitrary order.
+func (h *DaryHeap[T]) Iterator() collections.Iterator[T] {
+	return &daryHeapIterator[T]{heap: h, index: 0}
+}
+
+type daryHeapIterator[T any] struct {
+	heap  *DaryHeap[T]
+	index int
+}
+
+func (it *daryHeapIterator[T]) HasNext() bool {
+	return it.index < len(it.heap.data)
+}
+
+func (it *daryHeapIterator[T]) Next() res.Option[T] {
+	if !it.HasNext() {
+		return res.None[T]()
+	}
+	item := it.heap.data[it.index]
+	it.index++
+	return res.Some(item)
+}
+
+// siftUp moves the element at index i up to its proper position.
+// The element is held aside and written once, instead of swapped at every level.
+func (h *DaryHeap[T]) siftUp(i int) {
+	item := h.data[i]
+	for i > 0 {
+		parent := (i - 1) / h.arity
+		if h.comparator(item, h.data[parent]) <= 0 {
+			break
+		}
+		h.data[i] = h.data[parent]
+		i = parent
+	}
+	h.data[i] = item
+}
+
+// siftDown moves the element at index i down to its proper position.
+func (h *DaryHeap[T]) siftDown(i int) {
+	item := h.data[i]
+	n := len(h.data)
+	for {
+		first := h.arity*i + 1
+		if first >= n {
+			break
+		}
+		best := first
+		for c := first + 1; c < first+h.arity && c < n; c++ {
+			if h.comparator(h.data[c], h.data[best]) > 0 {
+				best = c
+			}
+		}
+		if h.comparator(h.data[best], item) <= 0 {
+			break
+		}
+		h.data[i] = h.data[best]
+		i = best
+	}
+	h.data[i] = item
+}
+
+// Ensure DaryHeap implements the Heap interface
+var _ Heap[int] = (*DaryHeap[int])(nil)
@@ -0,0 +1,27 @@
+// Package heap provides priority queues backed by implicit heaps.
+package heap
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/res"
+)
+
+// Heap is the interface shared by the priority queues in this package, so
+// that implementations with different performance characteristics, such as
+// BinaryHeap and DaryHeap, can be swapped for one another.
+//
+// The element at the top of the heap is the one the comparator orders
+// greatest; constructors named NewMin... reverse the comparator to obtain a
+// min-heap.
+type Heap[T any] interface {
+	Push(item T)
+	Pop() res.Option[T]
+	Peek() res.Option[T]
+	Contains(item T) bool
+	Len() int
+	IsEmpty() bool
+	Clear()
+	Iterator() collections.Iterator[T]
+	SetComparator(comparator comp.Comparator[T])
+}
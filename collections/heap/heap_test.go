@@ -0,0 +1,82 @@
+package heap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ielm/neostd/collections/comp"
+)
+
+// benchmarkSizes are the heap sizes the benchmarks run at; the larger ones
+// outgrow the CPU caches, where DaryHeap is meant to pay off.
+var benchmarkSizes = []struct {
+	name string
+	n    int
+}{
+	{"1K", 1 << 10},
+	{"64K", 1 << 16},
+	{"1M", 1 << 20},
+}
+
+func benchmarkHeaps() []struct {
+	name string
+	new  func() Heap[int]
+} {
+	cmp := comp.GenericComparator[int]()
+	return []struct {
+		name string
+		new  func() Heap[int]
+	}{
+		{"Binary", func() Heap[int] { return NewBinaryHeap(cmp) }},
+		{"Dary4", func() Heap[int] { return NewDaryHeap(cmp) }},
+		{"Dary8", func() Heap[int] {
+			h, _ := NewDaryHeapWithArity(8, cmp)
+			return h
+		}},
+	}
+}
+
+// BenchmarkPushPop fills a heap to size and then measures a pop followed by
+// a push of a random value, the steady state of a priority queue.
+func BenchmarkPushPop(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		for _, heap := range benchmarkHeaps() {
+			b.Run(heap.name+"/"+size.name, func(b *testing.B) {
+				rng := rand.New(rand.NewSource(1))
+				h := heap.new()
+				for i := 0; i < size.n; i++ {
+					h.Push(rng.Int())
+				}
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					h.Pop()
+					h.Push(rng.Int())
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkDrain measures pushing size random values and popping them all.
+func BenchmarkDrain(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		values := make([]int, size.n)
+		rng := rand.New(rand.NewSource(1))
+		for i := range values {
+			values[i] = rng.Int()
+		}
+		for _, heap := range benchmarkHeaps() {
+			b.Run(heap.name+"/"+size.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					h := heap.new()
+					for _, v := range values {
+						h.Push(v)
+					}
+					for !h.IsEmpty() {
+						h.Pop()
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package heap
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// Handle is a stable reference to an element of an IndexedHeap.
+// It remains valid while the element is in the heap, no matter how the
+// element moves, and can be passed to Remove, Fix and Update.
+type Handle[T any] struct {
+	value T
+	index int
+	owner *IndexedHeap[T]
+}
+
+// Value returns the element referred to by the handle.
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// InHeap reports whether the element is still in its heap.
+// A handle is invalidated when its element is popped, removed or cleared.
+func (h *Handle[T]) InHeap() bool {
+	return h.owner != nil
+}
+
+// IndexedHeap is a binary heap whose Push returns a Handle to the new element.
+// Handles make it possible to remove an arbitrary element, or to restore the
+// heap order after an element's priority has changed, in O(log n) time,
+// like Remove and Fix in container/heap.
+// By default, this is a max-heap. To use it as a min-heap, use the NewMinIndexedHeap function.
+//
+// Example:
+//
+//	type task struct{ name string; priority int }
+//	h := heap.NewIndexedHeap(func(a, b *task) int { return a.priority - b.priority })
+//	t := &task{"build", 1}
+//	handle := h.Push(t)
+//	t.priority = 10
+//	h.Fix(handle)
+type IndexedHeap[T any] struct {
+	data       []*Handle[T]
+	comparator comp.Comparator[T]
+}
+
+// NewIndexedHeap creates a new IndexedHeap with the given comparator.
+// This creates a max-heap by default.
+func NewIndexedHeap[T any](comparator comp.Comparator[T]) *IndexedHeap[T] {
+	return &IndexedHeap[T]{
+		data:       make([]*Handle[T], 0),
+		comparator: comparator,
+	}
+}
+
+// NewMinIndexedHeap creates a new IndexedHeap that functions as a min-heap.
+// It uses the provided comparator but reverses the comparison.
+func NewMinIndexedHeap[T any](comparator comp.Comparator[T]) *IndexedHeap[T] {
+	return NewIndexedHeap(func(a, b T) int {
+		return -comparator(a, b)
+	})
+}
+
+// Push adds an element to the heap and returns a handle to it.
+func (h *IndexedHeap[T]) Push(item T) *Handle[T] {
+	handle := &Handle[T]{value: item, index: len(h.data), owner: h}
+	h.data = append(h.data, handle)
+	h.siftUp(handle.index)
+	return handle
+}
+
+// Pop removes and returns the top element from the heap.
+// If the heap is empty, it returns None.
+func (h *IndexedHeap[T]) Pop() res.Option[T] {
+	if h.IsEmpty() {
+		return res.None[T]()
+	}
+	return res.Some(h.removeAt(0))
+}
+
+// Peek returns the top element without removing it.
+// If the heap is empty, it returns None.
+func (h *IndexedHeap[T]) Peek() res.Option[T] {
+	if h.IsEmpty() {
+		return res.None[T]()
+	}
+	return res.Some(h.data[0].value)
+}
+
+// PeekHandle returns a handle to the top element without removing it.
+// If the heap is empty, it returns None.
+func (h *IndexedHeap[T]) PeekHandle() res.Option[*Handle[T]] {
+	if h.IsEmpty() {
+		return res.None[*Handle[T]]()
+	}
+	return res.Some(h.data[0])
+}
+
+// Remove removes the element referred to by handle and returns it.
+// It returns an error if the handle does not belong to this heap or its
+// element has already been removed.
+func (h *IndexedHeap[T]) Remove(handle *Handle[T]) res.Result[T] {
+	if err := h.check(handle); err != nil {
+		return res.Err[T](err)
+	}
+	return res.Ok(h.removeAt(handle.index))
+}
+
+// Fix restores the heap order after the priority of the element referred to
+// by handle has changed, for example by mutating it through a pointer.
+// It returns an error if the handle does not belong to this heap or its
+// element has already been removed.
+func (h *IndexedHeap[T]) Fix(handle *Handle[T]) error {
+	if err := h.check(handle); err != nil {
+		return err
+	}
+	h.fix(handle.index)
+	return nil
+}
+
+// Update replaces the element referred to by handle with item and restores
+// the heap order. The handle keeps referring to the new element.
+func (h *IndexedHeap[T]) Update(handle *Handle[T], item T) error {
+	if err := h.check(handle); err != nil {
+		return err
+	}
+	handle.value = item
+	h.fix(handle.index)
+	return nil
+}
+
+// Contains checks if the heap contains the given item.
+func (h *IndexedHeap[T]) Contains(item T) bool {
+	for _, handle := range h.data {
+		if h.comparator(handle.value, item) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty returns true if the heap contains no elements.
+func (h *IndexedHeap[T]) IsEmpty() bool {
+	return len(h.data) == 0
+}
+
+// Len returns the number of elements in the heap.
+func (h *IndexedHeap[T]) Len() int {
+	return len(h.data)
+}
+
+// Clear removes all elements from the heap and invalidates their handles.
+func (h *IndexedHeap[T]) Clear() {
+	for _, handle := range h.data {
+		handle.owner = nil
+	}
+	clear(h.data)
+	h.data = h.data[:0]
+}
+
+// SetComparator sets a new comparator for the heap and rebuilds it.
+// Existing handles remain valid.
+func (h *IndexedHeap[T]) SetComparator(comparator comp.Comparator[T]) {
+	h.comparator = comparator
+	for i := len(h.data)/2 - 1; i >= 0; i-- {
+		h.siftDown(i)
+	}
+}
+
+// Iterator returns an iterator over the heap's elements in arbitrary order.
+func (h *IndexedHeap[T]) Iterator() collections.Iterator[T] {
+	return &indexedHeapIterator[T]{heap: h, index: 0}
+}
+
+type indexedHeapIterator[T any] struct {
+	heap  *IndexedHeap[T]
+	index int
+}
+
+func (it *indexedHeapIterator[T]) HasNext() bool {
+	return it.index < len(it.heap.data)
+}
+
+func (it *indexedHeapIterator[T]) Next() res.Option[T] {
+	if !it.HasNext() {
+		return res.None[T]()
+	}
+	item := it.heap.data[it.index].value
+	it.index++
+	return res.Some(item)
+}
+
+// check returns an error if handle cannot be used with this heap.
+func (h *IndexedHeap[T]) check(handle *Handle[T]) error {
+	if handle == nil || handle.owner != h {
+		return errors.New(errors.ErrInvalidArgument, "handle does not refer to an element of this heap")
+	}
+	return nil
+}
+
+// removeAt removes the element at index i and invalidates its handle.
+func (h *IndexedHeap[T]) removeAt(i int) T {
+	removed := h.data[i]
+	last := len(h.data) - 1
+	if i != last {
+		h.swap(i, last)
+	}
+	h.data[last] = nil
+	h.data = h.data[:last]
+	if i != last {
+		h.fix(i)
+	}
+	removed.owner = nil
+	removed.index = -1
+	return removed.value
+}
+
+// fix moves the element at index i up or down to its proper position.
+func (h *IndexedHeap[T]) fix(i int) {
+	if !h.siftDown(i) {
+		h.siftUp(i)
+	}
+}
+
+func (h *IndexedHeap[T]) swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+	h.data[i].index = i
+	h.data[j].index = j
+}
+
+// siftUp moves the element at index i up to its proper position.
+func (h *IndexedHeap[T]) siftUp(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if h.comparator(h.data[i].value, h.data[parent].value) <= 0 {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// siftDown moves the element at index i down to its proper position and
+// reports whether it moved.
+func (h *IndexedHeap[T]) siftDown(i int) bool {
+	start := i
+	for {
+		largest := i
+		left := 2*i + 1
+		right := 2*i + 2
+
+		if left < len(h.data) && h.comparator(h.data[left].value, h.data[largest].value) > 0 {
+			largest = left
+		}
+		if right < len(h.data) && h.comparator(h.data[right].value, h.data[largest].value) > 0 {
+			largest = right
+		}
+
+		if largest == i {
+			break
+		}
+
+		h.swap(i, largest)
+		i = largest
+	}
+	return i > start
+}
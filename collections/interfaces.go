@@ -17,6 +17,9 @@ type Iterator[T any] interface {
 	Next() res.Option[T]
 }
 
+// Ensure the iterators over optional values implement the Iterator interface
+var _ Iterator[any] = (*res.OptionIterator[any])(nil)
+
 type Pair[K any, V any] struct {
 	Key   K
 	Value V
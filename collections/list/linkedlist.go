@@ -281,6 +281,80 @@ func (l *LinkedList[T]) Clear() {
 	l.size = 0
 }
 
+// Sort sorts the list in place with a stable, bottom-up merge sort.
+// Nodes are relinked rather than copied, so no slice is allocated and
+// existing *Node[T] references stay valid and keep their values.
+// If comparator is nil, the list's own comparator is used.
+//
+// Example:
+//
+//	l.Sort(comp.GenericComparator[int]())
+func (l *LinkedList[T]) Sort(comparator comp.Comparator[T]) {
+	if comparator == nil {
+		comparator = l.comparator
+	}
+	if comparator == nil {
+		panic("comparator not set for non-comparable type")
+	}
+	if l.size < 2 {
+		return
+	}
+
+	// Merge runs of width 1, 2, 4, ... using only the next pointers,
+	// then restore the prev pointers and tail in a final pass.
+	head := l.head
+	for width := 1; ; width *= 2 {
+		var newHead, tail *Node[T]
+		merges := 0
+		left := head
+		for left != nil {
+			merges++
+			right := left
+			leftSize := 0
+			for leftSize < width && right != nil {
+				right = right.next
+				leftSize++
+			}
+			rightSize := width
+
+			for leftSize > 0 || (rightSize > 0 && right != nil) {
+				var next *Node[T]
+				switch {
+				case leftSize == 0:
+					next, right = right, right.next
+					rightSize--
+				case rightSize == 0 || right == nil || comparator(left.value, right.value) <= 0:
+					next, left = left, left.next
+					leftSize--
+				default:
+					next, right = right, right.next
+					rightSize--
+				}
+				if tail == nil {
+					newHead = next
+				} else {
+					tail.next = next
+				}
+				tail = next
+			}
+			left = right
+		}
+		tail.next = nil
+		head = newHead
+		if merges <= 1 {
+			break
+		}
+	}
+
+	var prev *Node[T]
+	for n := head; n != nil; n = n.next {
+		n.prev = prev
+		prev = n
+	}
+	l.head = head
+	l.tail = prev
+}
+
 // traverseForward traverses the list from the head to the specified index.
 func (l *LinkedList[T]) traverseForward(index int) *Node[T] {
 	current := l.head
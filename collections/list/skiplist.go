@@ -3,11 +3,13 @@ package list
 import (
 	"fmt"
 	"math"
+	"math/bits"
 	"sync"
-	"unsafe"
+	"time"
 
 	"github.com/ielm/neostd/collections"
 	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/errors"
 	"github.com/ielm/neostd/hash"
 	"github.com/ielm/neostd/res"
 )
@@ -28,6 +30,10 @@ const (
 // The number of forward pointers (the node's level) is randomly determined during insertion,
 // following a probability distribution that ensures a balanced structure.
 //
+// Each forward pointer also records its span, the number of elements it skips over. Spans make
+// the SkipList an order-statistic structure: elements can be accessed, ranked, and removed by
+// their position in sorted order in O(log n) time.
+//
 // This implementation is thread-safe and uses a comparator for ordering elements and a hasher for
 // generating hash values when needed.
 type SkipList[T any] struct {
@@ -37,6 +43,7 @@ type SkipList[T any] struct {
 	level  int                // Current maximum level of the SkipList
 	comp   comp.Comparator[T] // Comparator function for ordering elements
 	hasher hash.Hasher        // Hasher for generating hash values
+	rng    pcg32              // Generator for node levels, guarded by mu
 	mu     sync.RWMutex       // Read-write mutex for concurrent access
 }
 
@@ -44,6 +51,7 @@ type SkipList[T any] struct {
 type node[T any] struct {
 	value    T          // The value stored in the node
 	forward  []*node[T] // Array of forward pointers to next nodes at each level
+	span     []int      // Number of level 0 steps covered by each forward pointer
 	backward *node[T]   // Pointer to the previous node (for reverse iteration)
 }
 
@@ -62,11 +70,13 @@ func NewWithHasher[T any](comp comp.Comparator[T], hasher hash.Hasher) (*SkipLis
 		level:  1,
 		comp:   comp,
 		hasher: hasher,
+		rng:    newPCG32(uint64(time.Now().UnixNano())),
 	}
 	sl.head = sl.newNode(maxLevel, *new(T))
 	sl.tail = sl.newNode(maxLevel, *new(T))
 	for i := 0; i < maxLevel; i++ {
 		sl.head.forward[i] = sl.tail
+		sl.head.span[i] = 1
 	}
 	sl.tail.backward = sl.head
 	return sl, nil
@@ -74,7 +84,7 @@ func NewWithHasher[T any](comp comp.Comparator[T], hasher hash.Hasher) (*SkipLis
 
 // NewSkipList creates a new SkipList with the given comparator and a default hasher.
 //
-// This is a convenience function that uses a default SipHasher. For more control
+// This is a convenience function that uses hash.Default(). For more control
 // over the hasher, use NewWithHasher instead.
 //
 // Example:
@@ -82,18 +92,36 @@ func NewWithHasher[T any](comp comp.Comparator[T], hasher hash.Hasher) (*SkipLis
 //	comp := collections.GenericComparator[string]()
 //	sl, err := NewSkipList(comp)
 func NewSkipList[T any](comp comp.Comparator[T]) (*SkipList[T], error) {
-	hasher, err := hash.NewSipHasher()
+	hasher, err := hash.Default()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create hasher: %w", err)
 	}
 	return NewWithHasher(comp, hasher)
 }
 
+// NewSkipListWithSeed creates a new SkipList whose node levels are drawn from a
+// generator seeded with seed. Lists built with the same seed and the same
+// sequence of operations have identical structure, which makes tests and
+// benchmarks reproducible.
+//
+// Example:
+//
+//	sl, err := NewSkipListWithSeed(comp.GenericComparator[int](), 42)
+func NewSkipListWithSeed[T any](comp comp.Comparator[T], seed uint64) (*SkipList[T], error) {
+	sl, err := NewSkipList(comp)
+	if err != nil {
+		return nil, err
+	}
+	sl.rng = newPCG32(seed)
+	return sl, nil
+}
+
 // newNode creates a new node with the given level and value
 func (sl *SkipList[T]) newNode(level int, value T) *node[T] {
 	return &node[T]{
 		value:   value,
 		forward: make([]*node[T], level),
+		span:    make([]int, level),
 	}
 }
 
@@ -109,12 +137,21 @@ func (sl *SkipList[T]) newNode(level int, value T) *node[T] {
 func (sl *SkipList[T]) Insert(value T) {
 	sl.mu.Lock()
 	defer sl.mu.Unlock()
+	sl.insert(value)
+}
 
+// insert adds an element to the SkipList. The caller must hold the write lock.
+func (sl *SkipList[T]) insert(value T) {
 	update := make([]*node[T], maxLevel)
+	rank := make([]int, maxLevel) // rank of update[i]; the head has rank 0
 	x := sl.head
 
 	for i := sl.level - 1; i >= 0; i-- {
+		if i < sl.level-1 {
+			rank[i] = rank[i+1]
+		}
 		for x.forward[i] != sl.tail && sl.comp(x.forward[i].value, value) < 0 {
+			rank[i] += x.span[i]
 			x = x.forward[i]
 		}
 		update[i] = x
@@ -124,6 +161,7 @@ func (sl *SkipList[T]) Insert(value T) {
 	if level > sl.level {
 		for i := sl.level; i < level; i++ {
 			update[i] = sl.head
+			sl.head.span[i] = sl.length + 1
 		}
 		sl.level = level
 	}
@@ -132,6 +170,11 @@ func (sl *SkipList[T]) Insert(value T) {
 	for i := 0; i < level; i++ {
 		newNode.forward[i] = update[i].forward[i]
 		update[i].forward[i] = newNode
+		newNode.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := level; i < sl.level; i++ {
+		update[i].span[i]++
 	}
 
 	newNode.backward = update[0]
@@ -168,28 +211,113 @@ func (sl *SkipList[T]) Remove(value T) bool {
 
 	x = x.forward[0]
 	if x != sl.tail && sl.comp(x.value, value) == 0 {
-		for i := 0; i < sl.level; i++ {
-			if update[i].forward[i] != x {
-				break
-			}
+		sl.unlink(x, update)
+		return true
+	}
+
+	return false
+}
+
+// unlink removes node x from the SkipList, given the rightmost node before x at every level.
+func (sl *SkipList[T]) unlink(x *node[T], update []*node[T]) {
+	for i := 0; i < sl.level; i++ {
+		if update[i].forward[i] == x {
+			update[i].span[i] += x.span[i] - 1
 			update[i].forward[i] = x.forward[i]
+		} else {
+			update[i].span[i]--
 		}
+	}
 
-		if x.forward[0] != sl.tail {
-			x.forward[0].backward = update[0]
-		} else {
-			sl.tail.backward = update[0]
+	if x.forward[0] != sl.tail {
+		x.forward[0].backward = update[0]
+	} else {
+		sl.tail.backward = update[0]
+	}
+
+	for sl.level > 1 && sl.head.forward[sl.level-1] == sl.tail {
+		sl.level--
+	}
+
+	sl.length--
+}
+
+// GetByRank returns the element at the given zero-based position in sorted order.
+// If the rank is out of bounds, it returns an error.
+//
+// Example:
+//
+//	median := sl.GetByRank(sl.Size() / 2).Unwrap()
+func (sl *SkipList[T]) GetByRank(rank int) res.Result[T] {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	if rank < 0 || rank >= sl.length {
+		return res.Err[T](errors.New(errors.ErrOutOfBounds, "rank out of bounds"))
+	}
+
+	x, traversed := sl.head, 0
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.forward[i] != sl.tail && traversed+x.span[i] <= rank+1 {
+			traversed += x.span[i]
+			x = x.forward[i]
 		}
+	}
+	return res.Ok(x.value)
+}
+
+// RankOf returns the zero-based position in sorted order of the first element
+// equal to value, or None if the value is not in the SkipList.
+//
+// Example:
+//
+//	rank := sl.RankOf(42) // number of elements less than 42, if 42 is present
+func (sl *SkipList[T]) RankOf(value T) res.Option[int] {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
 
-		for sl.level > 1 && sl.head.forward[sl.level-1] == sl.tail {
-			sl.level--
+	x, traversed := sl.head, 0
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.forward[i] != sl.tail && sl.comp(x.forward[i].value, value) < 0 {
+			traversed += x.span[i]
+			x = x.forward[i]
 		}
+	}
 
-		sl.length--
-		return true
+	x = x.forward[0]
+	if x != sl.tail && sl.comp(x.value, value) == 0 {
+		return res.Some(traversed)
 	}
+	return res.None[int]()
+}
 
-	return false
+// RemoveByRank removes and returns the element at the given zero-based
+// position in sorted order. If the rank is out of bounds, it returns an error.
+//
+// Example:
+//
+//	smallest := sl.RemoveByRank(0).Unwrap()
+func (sl *SkipList[T]) RemoveByRank(rank int) res.Result[T] {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	if rank < 0 || rank >= sl.length {
+		return res.Err[T](errors.New(errors.ErrOutOfBounds, "rank out of bounds"))
+	}
+
+	update := make([]*node[T], maxLevel)
+	x, traversed := sl.head, 0
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.forward[i] != sl.tail && traversed+x.span[i] <= rank {
+			traversed += x.span[i]
+			x = x.forward[i]
+		}
+		update[i] = x
+	}
+
+	x = x.forward[0]
+	sl.unlink(x, update)
+	return res.Ok(x.value)
 }
 
 // Contains checks if an element exists in the SkipList.
@@ -252,6 +380,7 @@ func (sl *SkipList[T]) Clear() {
 	sl.tail = sl.newNode(maxLevel, *new(T))
 	for i := 0; i < maxLevel; i++ {
 		sl.head.forward[i] = sl.tail
+		sl.head.span[i] = 1
 	}
 	sl.tail.backward = sl.head
 	sl.length = 0
@@ -292,6 +421,75 @@ func (sl *SkipList[T]) ReverseIterator() collections.Iterator[T] {
 	}
 }
 
+// RangeIterator returns an iterator over the elements in the closed range
+// [lo, hi], in ascending order. It seeks to lo in O(log n) and stops as soon
+// as it passes hi, so only the elements in the range are visited.
+//
+// Example:
+//
+//	it := sl.RangeIterator(10, 20)
+//	for it.HasNext() {
+//		fmt.Println(it.Next().Unwrap())
+//	}
+func (sl *SkipList[T]) RangeIterator(lo, hi T) collections.Iterator[T] {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	return &skipListRangeIterator[T]{
+		current: sl.seek(lo),
+		tail:    sl.tail,
+		hi:      hi,
+		comp:    sl.comp,
+	}
+}
+
+// Between returns the elements in the closed range [lo, hi], in ascending order.
+//
+// Example:
+//
+//	values := sl.Between(10, 20)
+func (sl *SkipList[T]) Between(lo, hi T) []T {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+
+	var result []T
+	for x := sl.seek(lo); x != sl.tail && sl.comp(x.value, hi) <= 0; x = x.forward[0] {
+		result = append(result, x.value)
+	}
+	return result
+}
+
+// seek returns the first node whose value is not less than value, or the tail.
+func (sl *SkipList[T]) seek(value T) *node[T] {
+	x := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.forward[i] != sl.tail && sl.comp(x.forward[i].value, value) < 0 {
+			x = x.forward[i]
+		}
+	}
+	return x.forward[0]
+}
+
+type skipListRangeIterator[T any] struct {
+	current *node[T]
+	tail    *node[T]
+	hi      T
+	comp    comp.Comparator[T]
+}
+
+func (it *skipListRangeIterator[T]) HasNext() bool {
+	return it.current != it.tail && it.comp(it.current.value, it.hi) <= 0
+}
+
+func (it *skipListRangeIterator[T]) Next() res.Option[T] {
+	if !it.HasNext() {
+		return res.None[T]()
+	}
+	value := it.current.value
+	it.current = it.current.forward[0]
+	return res.Some(value)
+}
+
 type skipListIterator[T any] struct {
 	current *node[T]
 	tail    *node[T]
@@ -334,15 +532,41 @@ func (it *skipListReverseIterator[T]) Next() res.Option[T] {
 // which is crucial for maintaining the SkipList's balance and performance characteristics.
 func (sl *SkipList[T]) randomLevel() int {
 	level := 1
-	for fastrand() < uint32(float32(probability)*math.MaxUint32) && level < maxLevel {
+	for sl.rng.next() < uint32(float32(probability)*math.MaxUint32) && level < maxLevel {
 		level++
 	}
 	return level
 }
 
-// fastrand is a fast, thread-safe random number generator.
-func fastrand() uint32 {
-	return uint32(uintptr(unsafe.Pointer(new(byte))))*1664525 + 1013904223
+// pcg32 is a PCG-XSH-RR pseudo-random generator with 64 bits of state.
+// It is small, fast, and statistically strong enough for level generation,
+// but it is not safe for concurrent use; SkipList only calls it under its lock.
+type pcg32 struct {
+	state uint64
+	inc   uint64
+}
+
+const (
+	pcgMultiplier = 6364136223846793005
+	pcgIncrement  = 1442695040888963407
+)
+
+// newPCG32 creates a generator seeded with seed on the default stream.
+func newPCG32(seed uint64) pcg32 {
+	p := pcg32{inc: pcgIncrement}
+	p.next()
+	p.state += seed
+	p.next()
+	return p
+}
+
+// next returns the next 32-bit pseudo-random value.
+func (p *pcg32) next() uint32 {
+	old := p.state
+	p.state = old*pcgMultiplier + p.inc
+	xorshifted := uint32(((old >> 18) ^ old) >> 27)
+	rot := int(old >> 59)
+	return bits.RotateLeft32(xorshifted, -rot)
 }
 
 // Get retrieves an element from the SkipList by its value.
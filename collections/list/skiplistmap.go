@@ -0,0 +1,225 @@
+package list
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/res"
+)
+
+// SkipListMap is a sorted key-value map backed by a SkipList.
+//
+// Entries are kept in key order by the underlying SkipList, so in addition to
+// the usual map operations it supports ordered queries such as Ceiling, Floor,
+// FirstKey and LastKey, and range scans over a key interval, all in O(log n)
+// expected time. Like SkipList, it is safe for concurrent use.
+//
+// Example:
+//
+//	m, _ := list.NewSkipListMap[string, int](comp.GenericComparator[string]())
+//	m.Put("b", 2)
+//	m.Put("a", 1)
+//	m.Keys()                     // [a b]
+//	m.Ceiling("aa").Unwrap().Key // "b"
+type SkipListMap[K comparable, V any] struct {
+	list    *SkipList[collections.Pair[K, V]]
+	keyComp comp.Comparator[K]
+}
+
+// NewSkipListMap creates a new empty SkipListMap ordered by the given key comparator.
+func NewSkipListMap[K comparable, V any](comparator comp.Comparator[K]) (*SkipListMap[K, V], error) {
+	list, err := NewSkipList(byKey[K, V](comparator))
+	if err != nil {
+		return nil, err
+	}
+	return &SkipListMap[K, V]{list: list, keyComp: comparator}, nil
+}
+
+// byKey lifts a key comparator to a comparator over entries.
+func byKey[K any, V any](comparator comp.Comparator[K]) comp.Comparator[collections.Pair[K, V]] {
+	return func(a, b collections.Pair[K, V]) int {
+		return comparator(a.Key, b.Key)
+	}
+}
+
+// Put inserts a key-value pair into the map.
+// If the key already exists, its value is replaced and the old value is returned.
+// The boolean return value indicates whether an existing entry was updated.
+func (m *SkipListMap[K, V]) Put(key K, value V) (V, bool) {
+	m.list.mu.Lock()
+	defer m.list.mu.Unlock()
+
+	x := m.list.seek(collections.Pair[K, V]{Key: key})
+	if x != m.list.tail && m.keyComp(x.value.Key, key) == 0 {
+		old := x.value.Value
+		x.value.Value = value
+		return old, true
+	}
+
+	m.list.insert(collections.Pair[K, V]{Key: key, Value: value})
+	var zero V
+	return zero, false
+}
+
+// Get returns the value associated with the key and whether it was found.
+func (m *SkipListMap[K, V]) Get(key K) (V, bool) {
+	entry, ok := m.list.Get(collections.Pair[K, V]{Key: key})
+	return entry.Value, ok
+}
+
+// Remove removes the key from the map, returning its value and whether it was present.
+func (m *SkipListMap[K, V]) Remove(key K) (V, bool) {
+	m.list.mu.Lock()
+	defer m.list.mu.Unlock()
+
+	probe := collections.Pair[K, V]{Key: key}
+	update := make([]*node[collections.Pair[K, V]], maxLevel)
+	x := m.list.head
+	for i := m.list.level - 1; i >= 0; i-- {
+		for x.forward[i] != m.list.tail && m.list.comp(x.forward[i].value, probe) < 0 {
+			x = x.forward[i]
+		}
+		update[i] = x
+	}
+
+	x = x.forward[0]
+	if x == m.list.tail || m.keyComp(x.value.Key, key) != 0 {
+		var zero V
+		return zero, false
+	}
+	m.list.unlink(x, update)
+	return x.value.Value, true
+}
+
+// ContainsKey reports whether the map contains the key.
+func (m *SkipListMap[K, V]) ContainsKey(key K) bool {
+	return m.list.Contains(collections.Pair[K, V]{Key: key})
+}
+
+// Keys returns all keys in ascending order.
+func (m *SkipListMap[K, V]) Keys() []K {
+	m.list.mu.RLock()
+	defer m.list.mu.RUnlock()
+
+	keys := make([]K, 0, m.list.length)
+	for x := m.list.head.forward[0]; x != m.list.tail; x = x.forward[0] {
+		keys = append(keys, x.value.Key)
+	}
+	return keys
+}
+
+// Values returns all values in ascending order of their keys.
+func (m *SkipListMap[K, V]) Values() []V {
+	m.list.mu.RLock()
+	defer m.list.mu.RUnlock()
+
+	values := make([]V, 0, m.list.length)
+	for x := m.list.head.forward[0]; x != m.list.tail; x = x.forward[0] {
+		values = append(values, x.value.Value)
+	}
+	return values
+}
+
+// Size returns the number of entries in the map.
+func (m *SkipListMap[K, V]) Size() int {
+	return m.list.Size()
+}
+
+// IsEmpty returns true if the map has no entries.
+func (m *SkipListMap[K, V]) IsEmpty() bool {
+	return m.list.IsEmpty()
+}
+
+// Clear removes all entries from the map.
+func (m *SkipListMap[K, V]) Clear() {
+	m.list.Clear()
+}
+
+// SetComparator sets the key comparator.
+// Changing the comparator on a non-empty map may lead to inconsistencies.
+func (m *SkipListMap[K, V]) SetComparator(comparator comp.Comparator[K]) {
+	m.keyComp = comparator
+	m.list.SetComparator(byKey[K, V](comparator))
+}
+
+// Comparator returns the key comparator.
+func (m *SkipListMap[K, V]) Comparator() comp.Comparator[K] {
+	return m.keyComp
+}
+
+// FirstKey returns the smallest key, or None if the map is empty.
+func (m *SkipListMap[K, V]) FirstKey() res.Option[K] {
+	m.list.mu.RLock()
+	defer m.list.mu.RUnlock()
+
+	if m.list.length == 0 {
+		return res.None[K]()
+	}
+	return res.Some(m.list.head.forward[0].value.Key)
+}
+
+// LastKey returns the largest key, or None if the map is empty.
+func (m *SkipListMap[K, V]) LastKey() res.Option[K] {
+	m.list.mu.RLock()
+	defer m.list.mu.RUnlock()
+
+	if m.list.length == 0 {
+		return res.None[K]()
+	}
+	return res.Some(m.list.tail.backward.value.Key)
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to
+// key, or None if there is no such entry.
+func (m *SkipListMap[K, V]) Ceiling(key K) res.Option[collections.Pair[K, V]] {
+	m.list.mu.RLock()
+	defer m.list.mu.RUnlock()
+
+	x := m.list.seek(collections.Pair[K, V]{Key: key})
+	if x == m.list.tail {
+		return res.None[collections.Pair[K, V]]()
+	}
+	return res.Some(x.value)
+}
+
+// Floor returns the entry with the largest key less than or equal to key,
+// or None if there is no such entry.
+func (m *SkipListMap[K, V]) Floor(key K) res.Option[collections.Pair[K, V]] {
+	m.list.mu.RLock()
+	defer m.list.mu.RUnlock()
+
+	x := m.list.seek(collections.Pair[K, V]{Key: key})
+	if x != m.list.tail && m.keyComp(x.value.Key, key) == 0 {
+		return res.Some(x.value)
+	}
+	if x.backward == m.list.head {
+		return res.None[collections.Pair[K, V]]()
+	}
+	return res.Some(x.backward.value)
+}
+
+// Iterator returns an iterator over all entries in ascending key order.
+func (m *SkipListMap[K, V]) Iterator() collections.Iterator[collections.Pair[K, V]] {
+	return m.list.Iterator()
+}
+
+// RangeIterator returns an iterator over the entries whose keys lie in the
+// closed range [lo, hi], in ascending key order.
+//
+// Example:
+//
+//	it := m.RangeIterator("a", "m")
+//	for it.HasNext() {
+//		entry := it.Next().Unwrap()
+//		fmt.Println(entry.Key, entry.Value)
+//	}
+func (m *SkipListMap[K, V]) RangeIterator(lo, hi K) collections.Iterator[collections.Pair[K, V]] {
+	return m.list.RangeIterator(collections.Pair[K, V]{Key: lo}, collections.Pair[K, V]{Key: hi})
+}
+
+// Between returns the entries whose keys lie in the closed range [lo, hi], in ascending key order.
+func (m *SkipListMap[K, V]) Between(lo, hi K) []collections.Pair[K, V] {
+	return m.list.Between(collections.Pair[K, V]{Key: lo}, collections.Pair[K, V]{Key: hi})
+}
+
+// Ensure SkipListMap implements the Map interface
+var _ collections.Map[int, int] = (*SkipListMap[int, int])(nil)
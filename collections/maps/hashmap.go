@@ -14,10 +14,16 @@ import (
 // Constants
 const (
 	defaultLoadFactor = 0.875
-	minCapacity       = 8
+	minCapacity       = groupSize
 	groupSize         = 16
 	maxProbeDistance  = 128
-	emptyByte         = 0b11111111
+)
+
+// Control bytes. A full slot stores the top 7 bits of its key's hash, so
+// the high bit distinguishes full slots from empty and deleted ones.
+const (
+	emptyByte   = 0b10000000
+	deletedByte = 0b11111110
 )
 
 // HashMap struct definition
@@ -26,9 +32,11 @@ type HashMap[K any, V any] struct {
 	ctrl       []byte
 	entries    []entry[K, V]
 	size       int
+	tombstones int // Deleted slots, which still lengthen probe sequences
 	capacity   int
 	loadFactor float64
-	hasher     hash.Hasher
+	hasher     hash.TypedHasher[K]
+	hasherMu   sync.Mutex // Serializes use of the hasher by concurrent readers
 	comparator comp.Comparator[K]
 }
 
@@ -39,7 +47,8 @@ type entry[K any, V any] struct {
 }
 
 // NewHashMap creates a new HashMap with default settings.
-// It initializes the map with a minimum capacity and default load factor.
+// It initializes the map with a minimum capacity and default load factor,
+// and hashes keys with hash.Default().
 //
 // The comparator parameter is used for key comparison. For built-in types,
 // you can use collections.GenericComparator[K]().
@@ -48,7 +57,7 @@ type entry[K any, V any] struct {
 //
 //	hm := maps.NewHashMap[string, int](collections.GenericComparator[string]())
 func NewHashMap[K any, V any](comparator comp.Comparator[K]) res.Result[*HashMap[K, V]] {
-	hasher, err := hash.NewSipHasher()
+	hasher, err := hash.Default()
 	if err != nil {
 		return res.Err[*HashMap[K, V]](err)
 	}
@@ -58,11 +67,32 @@ func NewHashMap[K any, V any](comparator comp.Comparator[K]) res.Result[*HashMap
 // NewHashMapWithHasher creates a new HashMap with a custom hasher.
 // This allows for more flexibility in how keys are hashed.
 //
+// Keys are hashed from their canonical encoding, as hash.AppendKey
+// describes. It returns an error if K cannot be encoded, such as a struct
+// with a map field; hash such keys with NewHashMapWithTypedHasher, or
+// implement hash.Hashable.
+//
 // Example:
 //
 //	customHasher := &MyCustomHasher{}
 //	hm := maps.NewHashMapWithHasher[string, int](collections.GenericComparator[string](), customHasher)
 func NewHashMapWithHasher[K any, V any](comparator comp.Comparator[K], hasher hash.Hasher) res.Result[*HashMap[K, V]] {
+	if err := hash.CheckKeyType[K](); err != nil {
+		return res.Err[*HashMap[K, V]](err)
+	}
+	return NewHashMapWithTypedHasher[K, V](comparator, hash.NewKeyHasher[K](hasher))
+}
+
+// NewHashMapWithTypedHasher creates a new HashMap that hashes keys with a
+// TypedHasher, so that keys are hashed from their values rather than from
+// their in-memory representation. Keys that are equal according to the
+// comparator must hash alike.
+//
+// Example:
+//
+//	h := hash.NewIntegerHasher[int](hash.NewFNV64Hasher())
+//	hm := maps.NewHashMapWithTypedHasher[int, string](comp.GenericComparator[int](), h)
+func NewHashMapWithTypedHasher[K any, V any](comparator comp.Comparator[K], hasher hash.TypedHasher[K]) res.Result[*HashMap[K, V]] {
 	h := &HashMap[K, V]{
 		capacity:   minCapacity,
 		loadFactor: defaultLoadFactor,
@@ -85,22 +115,7 @@ func NewHashMapWithHasher[K any, V any](comparator comp.Comparator[K], hasher ha
 func (h *HashMap[K, V]) Put(key K, value V) (V, bool) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
-
-	if h.shouldResize() {
-		h.resize(h.capacity * 2)
-	}
-
-	hash := h.hashKey(key)
-	index, existed := h.findOrInsert(hash, key)
-
-	oldValue := h.entries[index].value
-	h.entries[index] = entry[K, V]{key: key, value: value}
-
-	if !existed {
-		h.size++
-	}
-
-	return oldValue, existed
+	return h.insert(key, value)
 }
 
 // Get retrieves a value from the HashMap by its key.
@@ -114,30 +129,9 @@ func (h *HashMap[K, V]) Get(key K) (V, bool) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	hash := h.hashKey(key)
-	index := hash & uint64(h.capacity-1)
-	hashByte := h.hashToByte(hash)
-
-	for i := uint64(0); i < maxProbeDistance; i++ {
-		group := index & ^uint64(groupSize-1)
-		match := h.matchGroup(group, hashByte)
-
-		for match != 0 {
-			matchIndex := group + uint64(bits.TrailingZeros64(uint64(match)))
-			if h.compareKeys(h.entries[matchIndex].key, key) {
-				return h.entries[matchIndex].value, true
-			}
-			match &= match - 1
-		}
-
-		if h.ctrl[group] == emptyByte {
-			var zero V
-			return zero, false
-		}
-
-		index = h.nextProbe(index, i)
+	if index, ok := h.find(key); ok {
+		return h.entries[index].value, true
 	}
-
 	var zero V
 	return zero, false
 }
@@ -152,6 +146,59 @@ func (h *HashMap[K, V]) Remove(key K) (V, bool) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if index, ok := h.find(key); ok {
+		return h.removeEntry(index)
+	}
+	var zero V
+	return zero, false
+}
+
+// Helper methods
+
+// initializeCtrl initializes the control bytes and entries.
+func (h *HashMap[K, V]) initializeCtrl() {
+	h.ctrl = make([]byte, h.capacity+groupSize)
+	for i := range h.ctrl {
+		h.ctrl[i] = emptyByte
+	}
+	h.entries = make([]entry[K, V], h.capacity)
+}
+
+// shouldResize checks if the HashMap needs to be resized.
+// Deleted slots count towards the load, since they lengthen probe sequences.
+func (h *HashMap[K, V]) shouldResize() bool {
+	return h.size+h.tombstones >= int(float64(h.capacity)*h.loadFactor)
+}
+
+// insert stores a key-value pair without locking, growing the map first if
+// needed. It returns the previous value and whether the key existed.
+func (h *HashMap[K, V]) insert(key K, value V) (V, bool) {
+	if h.shouldResize() {
+		// Rehash in place if most of the load is deleted slots.
+		if h.size < int(float64(h.capacity)*h.loadFactor)/2 {
+			h.resize(h.capacity)
+		} else {
+			h.resize(h.capacity * 2)
+		}
+	}
+
+	hash := h.hashKey(key)
+	index, existed := h.findOrInsert(hash, key)
+
+	oldValue := h.entries[index].value
+	h.entries[index] = entry[K, V]{key: key, value: value}
+
+	if !existed {
+		h.size++
+	}
+
+	return oldValue, existed
+}
+
+// find returns the slot holding key, if any, using quadratic probing.
+// The search ends at the first group with an empty slot, since an insertion
+// would have stopped there.
+func (h *HashMap[K, V]) find(key K) (uint64, bool) {
 	hash := h.hashKey(key)
 	index := hash & uint64(h.capacity-1)
 	hashByte := h.hashToByte(hash)
@@ -163,43 +210,27 @@ func (h *HashMap[K, V]) Remove(key K) (V, bool) {
 		for match != 0 {
 			matchIndex := group + uint64(bits.TrailingZeros64(uint64(match)))
 			if h.compareKeys(h.entries[matchIndex].key, key) {
-				return h.removeEntry(matchIndex)
+				return matchIndex, true
 			}
 			match &= match - 1
 		}
 
-		if h.ctrl[group] == emptyByte {
-			var zero V
-			return zero, false
+		if h.matchGroup(group, emptyByte) != 0 {
+			return 0, false
 		}
 
 		index = h.nextProbe(index, i)
 	}
 
-	var zero V
-	return zero, false
-}
-
-// Helper methods
-
-// initializeCtrl initializes the control bytes and entries.
-func (h *HashMap[K, V]) initializeCtrl() {
-	h.ctrl = make([]byte, h.capacity+groupSize)
-	for i := range h.ctrl {
-		h.ctrl[i] = emptyByte
-	}
-	h.entries = make([]entry[K, V], h.capacity)
-}
-
-// shouldResize checks if the HashMap needs to be resized
-func (h *HashMap[K, V]) shouldResize() bool {
-	return h.size >= int(float64(h.capacity)*h.loadFactor)
+	return 0, false
 }
 
 // findOrInsert finds an existing entry or inserts a new one using quadratic probing.
+// New entries take the first empty or deleted slot on the probe sequence.
 func (h *HashMap[K, V]) findOrInsert(hash uint64, key K) (int, bool) {
 	index := hash & uint64(h.capacity-1)
 	hashByte := h.hashToByte(hash)
+	free := -1
 
 	for i := uint64(0); i < maxProbeDistance; i++ {
 		group := index & ^uint64(groupSize-1)
@@ -213,18 +244,29 @@ func (h *HashMap[K, V]) findOrInsert(hash uint64, key K) (int, bool) {
 			match &= match - 1
 		}
 
-		if emptySlot := h.findEmptySlot(group); emptySlot != -1 {
-			slotIndex := int(group) + emptySlot
-			h.ctrl[slotIndex] = hashByte
-			return slotIndex, false
+		if free == -1 {
+			if slot := h.findEmptySlot(group); slot != -1 {
+				free = int(group) + slot
+			}
+		}
+
+		if h.matchGroup(group, emptyByte) != 0 {
+			break
 		}
 
 		index = h.nextProbe(index, i)
 	}
 
-	// If we reach here, we need to resize and try again
-	h.resize(h.capacity * 2)
-	return h.findOrInsert(hash, key)
+	if free == -1 {
+		// No free slot within the probe limit; grow and try again
+		h.resize(h.capacity * 2)
+		return h.findOrInsert(hash, key)
+	}
+	if h.ctrl[free] == deletedByte {
+		h.tombstones--
+	}
+	h.ctrl[free] = hashByte
+	return free, false
 }
 
 // matchGroup performs SIMD-like matching of control bytes.
@@ -238,16 +280,16 @@ func (h *HashMap[K, V]) matchGroup(group uint64, hashByte byte) uint16 {
 		chunk := *(*uint64)(unsafe.Pointer(&vec[i]))
 		// XOR the chunk with the hashByte
 		eq := chunk ^ (uint64(hashByte) * 0x0101010101010101)
-		// This is a bitmask that will be non-zero if the hashByte is found in the chunk
-		bitmask := ((eq - 0x0101010101010101) & ^eq & 0x8080808080808080) >> 7
-		// OR the bitmask with the mask
-		mask |= uint16(bitmask) << i
+		// This is a bitmask with the high bit set in each byte equal to hashByte
+		bitmask := (eq - 0x0101010101010101) & ^eq & 0x8080808080808080
+		// Gather the high bits into one bit per slot and OR them into the mask
+		mask |= uint16((bitmask>>7)*0x0102040810204080>>56) << i
 	}
 
 	return mask
 }
 
-// findEmptySlot finds an empty slot in a group.
+// findEmptySlot finds an empty or deleted slot in a group.
 func (h *HashMap[K, V]) findEmptySlot(group uint64) int {
 	vec := (*[16]uint8)(unsafe.Pointer(&h.ctrl[group]))
 
@@ -255,13 +297,10 @@ func (h *HashMap[K, V]) findEmptySlot(group uint64) int {
 	for i := 0; i < 16; i += 8 {
 		// Load 8 bytes from the vector
 		chunk := *(*uint64)(unsafe.Pointer(&vec[i]))
-		// XOR the chunk with the hashByte
-		eq := chunk ^ (uint64(emptyByte) * 0x0101010101010101)
-		// Bitmask to check if any of the 8 bytes are empty
-		bitmask := ((eq - 0x0101010101010101) & ^eq & 0x8080808080808080) >> 7
-		// If the bitmask is not zero, we found an empty slot
+		// Empty and deleted slots are the ones with the high bit set
+		bitmask := chunk & 0x8080808080808080
 		if bitmask != 0 {
-			return i + bits.TrailingZeros64(bitmask)
+			return i + bits.TrailingZeros64(bitmask)/8
 		}
 	}
 
@@ -283,30 +322,33 @@ func (h *HashMap[K, V]) resize(newCapacity int) {
 	h.capacity = newCapacity
 	h.initializeCtrl()
 	h.size = 0
+	h.tombstones = 0
 
 	for i, entry := range oldEntries {
-		if oldCtrl[i]&0x80 != 0 {
-			h.Put(entry.key, entry.value)
+		if isFull(oldCtrl[i]) {
+			index, _ := h.findOrInsert(h.hashKey(entry.key), entry.key)
+			h.entries[index] = entry
+			h.size++
 		}
 	}
 }
 
 // hashKey hashes the key using the HashMap's hasher.
 func (h *HashMap[K, V]) hashKey(key K) uint64 {
-	keyBytes, err := keyToBytes(key)
-	if err != nil {
-		panic(err) // In production, consider handling this error more gracefully
-	}
-	h.hasher.Reset()
-	h.hasher.Write(keyBytes)
-	hashBytes := h.hasher.Sum(nil)
-	return hash.HashBytesToUint64(hashBytes)
+	h.hasherMu.Lock()
+	defer h.hasherMu.Unlock()
+	return h.hasher.Hash(key)
 }
 
-// hashToByte converts a hash to a control byte.
-// Again, more wicked magic
+// hashToByte converts a hash to a control byte: its top 7 bits, which leave
+// the high bit clear to mark the slot as full.
 func (h *HashMap[K, V]) hashToByte(hash uint64) byte {
-	return byte((hash >> 57) | 0x80)
+	return byte(hash >> 57)
+}
+
+// isFull reports whether a control byte marks a full slot.
+func isFull(ctrl byte) bool {
+	return ctrl&0x80 == 0
 }
 
 // compareKeys compares two keys using the HashMap's comparator.
@@ -317,7 +359,10 @@ func (h *HashMap[K, V]) compareKeys(a, b K) bool {
 // removeEntry removes an entry at the given index
 func (h *HashMap[K, V]) removeEntry(index uint64) (V, bool) {
 	removedValue := h.entries[index].value
-	h.ctrl[index] = emptyByte
+	// Later keys may have probed past this slot, so mark it deleted rather
+	// than empty to keep their probe sequences intact.
+	h.ctrl[index] = deletedByte
+	h.tombstones++
 	var zero V
 	h.entries[index] = entry[K, V]{key: *new(K), value: zero}
 	h.size--
@@ -336,6 +381,7 @@ func (h *HashMap[K, V]) Clear() {
 	defer h.mu.Unlock()
 
 	h.size = 0
+	h.tombstones = 0
 	h.capacity = minCapacity
 	h.initializeCtrl()
 }
@@ -374,8 +420,8 @@ func (h *HashMap[K, V]) Keys() []K {
 	defer h.mu.RUnlock()
 
 	keys := make([]K, 0, h.size)
-	for i, ctrl := range h.ctrl {
-		if ctrl&0x80 != 0 {
+	for i, ctrl := range h.ctrl[:h.capacity] {
+		if isFull(ctrl) {
 			keys = append(keys, h.entries[i].key)
 		}
 	}
@@ -392,8 +438,8 @@ func (h *HashMap[K, V]) Values() []V {
 	defer h.mu.RUnlock()
 
 	values := make([]V, 0, h.size)
-	for i, ctrl := range h.ctrl {
-		if ctrl&0x80 != 0 {
+	for i, ctrl := range h.ctrl[:h.capacity] {
+		if isFull(ctrl) {
 			values = append(values, h.entries[i].value)
 		}
 	}
@@ -411,8 +457,8 @@ func (h *HashMap[K, V]) ForEach(f func(K, V)) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	for i, ctrl := range h.ctrl {
-		if ctrl&0x80 != 0 {
+	for i, ctrl := range h.ctrl[:h.capacity] {
+		if isFull(ctrl) {
 			f(h.entries[i].key, h.entries[i].value)
 		}
 	}
@@ -423,7 +469,7 @@ func (h *HashMap[K, V]) ContainsKey(key K) bool {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	_, found := h.Get(key)
+	_, found := h.find(key)
 	return found
 }
 
@@ -460,15 +506,3 @@ type T interface{}
 
 // Ensure HashMap implements the Map interface for T
 var _ collections.Map[T, any] = (*HashMap[T, any])(nil)
-
-// keyToBytes converts a key of any type to a byte slice
-func keyToBytes(key any) ([]byte, error) {
-	switch k := key.(type) {
-	case string:
-		return []byte(k), nil
-	case []byte:
-		return k, nil
-	default:
-		return hash.ToBinary(k)
-	}
-}
@@ -0,0 +1,245 @@
+// Package sketch provides probabilistic summaries of large data streams,
+// such as cardinality estimators and similarity signatures, that answer
+// approximate queries in a small, fixed amount of memory.
+package sketch
+
+import (
+	"math"
+	"math/bits"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+)
+
+const (
+	// MinPrecision is the smallest precision accepted by NewHyperLogLog.
+	MinPrecision = 4
+	// MaxPrecision is the largest precision accepted by NewHyperLogLog.
+	MaxPrecision = 18
+	// DefaultPrecision is a good general-purpose precision for HyperLogLog.
+	DefaultPrecision = 12
+)
+
+// HyperLogLog estimates the number of distinct elements added to it using
+// 2^precision registers of one byte each. The relative standard error of the
+// estimate is about 1.04/sqrt(2^precision): 1.6% at the default precision of
+// 12, which uses 4 KiB.
+//
+// Sketches built with the same precision and hasher can be merged without
+// loss, so distinct counts can be computed in parallel or per shard and then
+// combined.
+//
+// Example:
+//
+//	hll, _ := sketch.NewHyperLogLog(12)
+//	for _, user := range users {
+//		hll.Add([]byte(user))
+//	}
+//	fmt.Println(hll.Estimate())
+type HyperLogLog struct {
+	registers []uint8
+	precision uint8
+	hasher    hash.Hasher
+}
+
+// NewHyperLogLog creates a new HyperLogLog with 2^precision registers.
+// The precision must be between MinPrecision and MaxPrecision.
+//
+// The sketch hashes with TigerHasher, which is unkeyed, so sketches created
+// in different processes can be merged and deserialized.
+//
+// Example:
+//
+//	hll, err := sketch.NewHyperLogLog(14)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewHyperLogLog(precision int) (*HyperLogLog, error) {
+	return NewHyperLogLogWithHasher(precision, hash.NewTigerHasher())
+}
+
+// NewHyperLogLogWithHasher creates a new HyperLogLog with 2^precision registers
+// and a custom hasher. The hasher must produce at least 8 bytes, and sketches
+// can only be merged if they use hashers that agree on every input.
+//
+// Example:
+//
+//	hll, err := sketch.NewHyperLogLogWithHasher(14, customHasher)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewHyperLogLogWithHasher(precision int, hasher hash.Hasher) (*HyperLogLog, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, errors.New(errors.ErrInvalidArgument, "precision must be between 4 and 18")
+	}
+	if hasher.Size() < 8 {
+		return nil, errors.New(errors.ErrInvalidArgument, "hasher must produce at least 8 bytes")
+	}
+	return &HyperLogLog{
+		registers: make([]uint8, 1<<precision),
+		precision: uint8(precision),
+		hasher:    hasher,
+	}, nil
+}
+
+// Add adds an element to the sketch.
+// It returns true if the element changed the sketch's state, which is always
+// the case for the first occurrence of an element that raises a register.
+//
+// Example:
+//
+//	hll.Add([]byte("example"))
+func (h *HyperLogLog) Add(data []byte) bool {
+	x := hash.Sum64(h.hasher, data)
+
+	// The top precision bits select a register; the rest give the rank,
+	// the position of the first set bit. The guard bit caps the rank.
+	index := x >> (64 - h.precision)
+	w := x<<h.precision | 1<<(h.precision-1)
+	rank := uint8(bits.LeadingZeros64(w)) + 1
+
+	if rank > h.registers[index] {
+		h.registers[index] = rank
+		return true
+	}
+	return false
+}
+
+// Estimate returns the estimated number of distinct elements added so far.
+// Small cardinalities, where many registers are still empty, are estimated
+// with linear counting, which is more accurate in that range.
+//
+// Example:
+//
+//	fmt.Printf("About %d distinct elements\n", hll.Estimate())
+func (h *HyperLogLog) Estimate() uint64 {
+	m := float64(len(h.registers))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := h.alpha() * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Merge combines another sketch into this one, so that this sketch estimates
+// the number of distinct elements added to either. The result is identical
+// to a sketch that had every element added to it directly.
+// Both sketches must have the same precision.
+//
+// Example:
+//
+//	err := hll1.Merge(hll2)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.precision != other.precision {
+		return errors.New(errors.ErrInvalidArgument, "hyperloglog sketches must have the same precision to merge")
+	}
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+	return nil
+}
+
+// Precision returns the precision the sketch was created with.
+func (h *HyperLogLog) Precision() int {
+	return int(h.precision)
+}
+
+// RelativeError returns the relative standard error of the estimate.
+func (h *HyperLogLog) RelativeError() float64 {
+	return 1.04 / math.Sqrt(float64(len(h.registers)))
+}
+
+// IsEmpty returns true if no elements have been added to the sketch.
+func (h *HyperLogLog) IsEmpty() bool {
+	for _, r := range h.registers {
+		if r != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear resets the sketch to its empty state.
+func (h *HyperLogLog) Clear() {
+	clear(h.registers)
+}
+
+// Copy creates a deep copy of the sketch that shares its hasher.
+func (h *HyperLogLog) Copy() *HyperLogLog {
+	registers := make([]uint8, len(h.registers))
+	copy(registers, h.registers)
+	return &HyperLogLog{registers: registers, precision: h.precision, hasher: h.hasher}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// It serializes the sketch as its precision followed by its registers.
+//
+// Example:
+//
+//	data, err := hll.MarshalBinary()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 1+len(h.registers))
+	data[0] = h.precision
+	copy(data[1:], h.registers)
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It deserializes a sketch written by MarshalBinary. A sketch without a
+// hasher is given the default TigerHasher.
+//
+// Example:
+//
+//	var hll sketch.HyperLogLog
+//	err := hll.UnmarshalBinary(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 {
+		return errors.New(errors.ErrInvalidArgument, "invalid data length")
+	}
+	precision := int(data[0])
+	if precision < MinPrecision || precision > MaxPrecision {
+		return errors.New(errors.ErrInvalidArgument, "invalid precision")
+	}
+	if len(data) != 1+1<<precision {
+		return errors.New(errors.ErrInvalidArgument, "invalid data length")
+	}
+	h.precision = uint8(precision)
+	h.registers = make([]uint8, 1<<precision)
+	copy(h.registers, data[1:])
+	if h.hasher == nil {
+		h.hasher = hash.NewTigerHasher()
+	}
+	return nil
+}
+
+// alpha returns the bias correction constant for the number of registers.
+func (h *HyperLogLog) alpha() float64 {
+	switch m := float64(len(h.registers)); h.precision {
+	case 4:
+		return 0.673
+	case 5:
+		return 0.697
+	case 6:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/m)
+	}
+}
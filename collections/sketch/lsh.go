@@ -0,0 +1,183 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/ielm/neostd/errors"
+)
+
+// LSHIndex finds near-duplicate sets by locality-sensitive hashing of their
+// MinHash signatures. Each signature is split into bands of rows values, and
+// two sets become candidates for each other if all rows of at least one band
+// agree. Sets with Jaccard similarity s are found with probability
+// 1 - (1 - s^rows)^bands, an S-curve whose threshold is near
+// (1/bands)^(1/rows); see LSHParams.
+//
+// Example:
+//
+//	mh, _ := sketch.NewMinHash(128, 42)
+//	index, _ := sketch.NewLSHIndex[string](16, 8)
+//	for name, doc := range docs {
+//		index.Insert(name, mh.Signature(shingles(doc)))
+//	}
+//	dups := index.QuerySimilar(mh.Signature(shingles(query)), 0.8)
+type LSHIndex[K comparable] struct {
+	bands      int
+	rows       int
+	buckets    []map[string][]K
+	signatures map[K]Signature
+}
+
+// NewLSHIndex creates a new empty LSHIndex for signatures of bands*rows values.
+//
+// Example:
+//
+//	index, err := sketch.NewLSHIndex[int](20, 5)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewLSHIndex[K comparable](bands, rows int) (*LSHIndex[K], error) {
+	if bands <= 0 || rows <= 0 {
+		return nil, errors.New(errors.ErrInvalidArgument, "bands and rows must be positive")
+	}
+	buckets := make([]map[string][]K, bands)
+	for i := range buckets {
+		buckets[i] = make(map[string][]K)
+	}
+	return &LSHIndex[K]{
+		bands:      bands,
+		rows:       rows,
+		buckets:    buckets,
+		signatures: make(map[K]Signature),
+	}, nil
+}
+
+// LSHParams chooses the number of bands and rows for signatures of numHashes
+// values so that the index's similarity threshold, (1/bands)^(1/rows), is as
+// close as possible to threshold. Only divisors of numHashes are considered,
+// so every value of the signature is used.
+//
+// Example:
+//
+//	bands, rows := sketch.LSHParams(128, 0.8)
+//	index, _ := sketch.NewLSHIndex[string](bands, rows)
+func LSHParams(numHashes int, threshold float64) (bands, rows int) {
+	bands, rows = numHashes, 1
+	best := math.Inf(1)
+	for r := 1; r <= numHashes; r++ {
+		if numHashes%r != 0 {
+			continue
+		}
+		b := numHashes / r
+		if diff := math.Abs(math.Pow(1/float64(b), 1/float64(r)) - threshold); diff < best {
+			best, bands, rows = diff, b, r
+		}
+	}
+	return bands, rows
+}
+
+// Insert adds a set under the given key, replacing any set already stored
+// under it. It returns an error if the signature does not have bands*rows values.
+func (idx *LSHIndex[K]) Insert(key K, sig Signature) error {
+	if len(sig) != idx.bands*idx.rows {
+		return errors.New(errors.ErrInvalidArgument, "signature length must equal bands*rows")
+	}
+	idx.Remove(key)
+
+	stored := make(Signature, len(sig))
+	copy(stored, sig)
+	idx.signatures[key] = stored
+	for band := range idx.buckets {
+		bucket := idx.bandKey(stored, band)
+		idx.buckets[band][bucket] = append(idx.buckets[band][bucket], key)
+	}
+	return nil
+}
+
+// Remove removes the set stored under key and reports whether it was present.
+func (idx *LSHIndex[K]) Remove(key K) bool {
+	sig, ok := idx.signatures[key]
+	if !ok {
+		return false
+	}
+	delete(idx.signatures, key)
+	for band := range idx.buckets {
+		bucket := idx.bandKey(sig, band)
+		keys := idx.buckets[band][bucket]
+		for i, k := range keys {
+			if k == key {
+				keys = append(keys[:i], keys[i+1:]...)
+				break
+			}
+		}
+		if len(keys) == 0 {
+			delete(idx.buckets[band], bucket)
+		} else {
+			idx.buckets[band][bucket] = keys
+		}
+	}
+	return true
+}
+
+// Query returns the keys of every stored set that shares at least one band
+// with sig, without duplicates. Candidates may include sets that are not
+// similar, and may miss similar sets with small probability; use
+// QuerySimilar to filter the candidates by estimated similarity.
+func (idx *LSHIndex[K]) Query(sig Signature) []K {
+	if len(sig) != idx.bands*idx.rows {
+		return nil
+	}
+	var candidates []K
+	seen := make(map[K]struct{})
+	for band := range idx.buckets {
+		for _, key := range idx.buckets[band][idx.bandKey(sig, band)] {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				candidates = append(candidates, key)
+			}
+		}
+	}
+	return candidates
+}
+
+// QuerySimilar returns the keys of the candidate sets whose estimated Jaccard
+// similarity to sig is at least threshold.
+func (idx *LSHIndex[K]) QuerySimilar(sig Signature, threshold float64) []K {
+	var similar []K
+	for _, key := range idx.Query(sig) {
+		if sim, err := sig.Jaccard(idx.signatures[key]); err == nil && sim >= threshold {
+			similar = append(similar, key)
+		}
+	}
+	return similar
+}
+
+// Signature returns the signature stored under key and whether it was found.
+func (idx *LSHIndex[K]) Signature(key K) (Signature, bool) {
+	sig, ok := idx.signatures[key]
+	return sig, ok
+}
+
+// Len returns the number of sets in the index.
+func (idx *LSHIndex[K]) Len() int {
+	return len(idx.signatures)
+}
+
+// Clear removes every set from the index.
+func (idx *LSHIndex[K]) Clear() {
+	for band := range idx.buckets {
+		clear(idx.buckets[band])
+	}
+	clear(idx.signatures)
+}
+
+// bandKey returns the bucket key of the given band of sig: the band's rows
+// encoded as bytes, so that only identical bands share a bucket.
+func (idx *LSHIndex[K]) bandKey(sig Signature, band int) string {
+	buf := make([]byte, 8*idx.rows)
+	for i, v := range sig[band*idx.rows : (band+1)*idx.rows] {
+		binary.LittleEndian.PutUint64(buf[8*i:], v)
+	}
+	return string(buf)
+}
@@ -0,0 +1,152 @@
+package sketch
+
+import (
+	"math"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+)
+
+// Signature is a MinHash signature: for each of a MinHash's permutations, the
+// smallest permuted hash of any element of the set.
+type Signature []uint64
+
+// MinHash computes signatures of sets of byte slices whose agreement estimates
+// the Jaccard similarity |A ∩ B| / |A ∪ B| of the sets. With k permutations the
+// standard error of the estimate is about 1/sqrt(k).
+//
+// Each element is hashed once with the MinHash's hasher, and the k
+// permutations are derived from that hash with a seeded bijective mixer.
+// Signatures are only comparable if they were computed by MinHashes with the
+// same number of permutations, seed and hasher.
+//
+// Example:
+//
+//	mh, _ := sketch.NewMinHash(128, 42)
+//	a := mh.Signature(shingles(doc1))
+//	b := mh.Signature(shingles(doc2))
+//	sim, _ := a.Jaccard(b)
+type MinHash struct {
+	seeds  []uint64
+	hasher hash.Hasher
+}
+
+// NewMinHash creates a new MinHash with numHashes permutations derived from seed.
+// It hashes with TigerHasher, which is unkeyed, so signatures computed in
+// different processes with the same seed are comparable.
+//
+// Example:
+//
+//	mh, err := sketch.NewMinHash(128, 42)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewMinHash(numHashes int, seed uint64) (*MinHash, error) {
+	return NewMinHashWithHasher(numHashes, seed, hash.NewTigerHasher())
+}
+
+// NewMinHashWithHasher creates a new MinHash with numHashes permutations derived
+// from seed and a custom hasher. The hasher must produce at least 8 bytes.
+func NewMinHashWithHasher(numHashes int, seed uint64, hasher hash.Hasher) (*MinHash, error) {
+	if numHashes <= 0 {
+		return nil, errors.New(errors.ErrInvalidArgument, "number of hashes must be positive")
+	}
+	if hasher.Size() < 8 {
+		return nil, errors.New(errors.ErrInvalidArgument, "hasher must produce at least 8 bytes")
+	}
+	seeds := make([]uint64, numHashes)
+	for i := range seeds {
+		seed += 0x9E3779B97F4A7C15
+		seeds[i] = mix64(seed)
+	}
+	return &MinHash{seeds: seeds, hasher: hasher}, nil
+}
+
+// NumHashes returns the number of permutations, which is the length of every signature.
+func (m *MinHash) NumHashes() int {
+	return len(m.seeds)
+}
+
+// Empty returns the signature of the empty set, to be filled in with Update.
+func (m *MinHash) Empty() Signature {
+	sig := make(Signature, len(m.seeds))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	return sig
+}
+
+// Update adds an element to the set summarized by sig, in place.
+// Adding the same element twice has no further effect.
+//
+// Example:
+//
+//	sig := mh.Empty()
+//	for _, word := range words {
+//		mh.Update(sig, []byte(word))
+//	}
+func (m *MinHash) Update(sig Signature, data []byte) {
+	x := hash.Sum64(m.hasher, data)
+	for i, seed := range m.seeds {
+		sig[i] = min(sig[i], mix64(x^seed))
+	}
+}
+
+// Signature returns the signature of the given set of elements.
+func (m *MinHash) Signature(set [][]byte) Signature {
+	sig := m.Empty()
+	for _, data := range set {
+		m.Update(sig, data)
+	}
+	return sig
+}
+
+// Jaccard estimates the Jaccard similarity of the sets summarized by s and
+// other, as the fraction of permutations on which they agree.
+// It returns an error if the signatures have different lengths.
+//
+// Example:
+//
+//	sim, err := a.Jaccard(b)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%.0f%% similar\n", sim*100)
+func (s Signature) Jaccard(other Signature) (float64, error) {
+	if len(s) != len(other) {
+		return 0, errors.New(errors.ErrInvalidArgument, "signatures must have the same length")
+	}
+	if len(s) == 0 {
+		return 0, nil
+	}
+	equal := 0
+	for i := range s {
+		if s[i] == other[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(s)), nil
+}
+
+// Merge updates s in place to the signature of the union of the sets
+// summarized by s and other.
+// It returns an error if the signatures have different lengths.
+func (s Signature) Merge(other Signature) error {
+	if len(s) != len(other) {
+		return errors.New(errors.ErrInvalidArgument, "signatures must have the same length")
+	}
+	for i := range s {
+		s[i] = min(s[i], other[i])
+	}
+	return nil
+}
+
+// mix64 is the SplitMix64 finalizer, a bijection on uint64 with good avalanche.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,139 @@
+package sketch
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/errors"
+)
+
+// Reservoir maintains a uniform random sample of up to k elements from a
+// stream of unknown length, in O(k) memory. After n elements have been added,
+// every one of them is in the sample with probability k/n.
+//
+// It uses Li's Algorithm L, which computes how many elements to skip before
+// the next replacement instead of drawing a random number per element, so
+// Add is O(1) and random numbers are only drawn O(k log(n/k)) times.
+//
+// Example:
+//
+//	r, _ := sketch.NewReservoir[string](10)
+//	r.AddAll(lines)
+//	fmt.Println(r.Sample())
+type Reservoir[T any] struct {
+	sample []T
+	k      int
+	seen   uint64
+	next   uint64  // Index of the next element to enter the sample
+	w      float64 // Largest of k uniform keys; see Algorithm L
+	rng    *rand.Rand
+}
+
+// NewReservoir creates a new empty Reservoir that keeps a sample of k elements.
+//
+// Example:
+//
+//	r, err := sketch.NewReservoir[int](100)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewReservoir[T any](k int) (*Reservoir[T], error) {
+	return NewReservoirWithSeed[T](k, time.Now().UnixNano())
+}
+
+// NewReservoirWithSeed creates a new empty Reservoir that keeps a sample of
+// k elements, drawing random numbers from a generator with the given seed.
+// Reservoirs with the same seed fed the same stream produce the same sample.
+func NewReservoirWithSeed[T any](k int, seed int64) (*Reservoir[T], error) {
+	if k <= 0 {
+		return nil, errors.New(errors.ErrInvalidArgument, "sample size must be positive")
+	}
+	return &Reservoir[T]{
+		sample: make([]T, 0, k),
+		k:      k,
+		rng:    rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// Add offers an element from the stream to the reservoir.
+func (r *Reservoir[T]) Add(item T) {
+	r.seen++
+	if len(r.sample) < r.k {
+		r.sample = append(r.sample, item)
+		if len(r.sample) == r.k {
+			r.w = math.Exp(math.Log(r.random()) / float64(r.k))
+			r.skip()
+		}
+		return
+	}
+	if r.seen == r.next {
+		r.sample[r.rng.Intn(r.k)] = item
+		r.w *= math.Exp(math.Log(r.random()) / float64(r.k))
+		r.skip()
+	}
+}
+
+// AddAll offers every remaining element of the iterator to the reservoir.
+//
+// Example:
+//
+//	r.AddAll(list.Iterator())
+func (r *Reservoir[T]) AddAll(it collections.Iterator[T]) {
+	for it.HasNext() {
+		r.Add(it.Next().Unwrap())
+	}
+}
+
+// Sample returns a copy of the current sample. It holds min(k, Seen())
+// elements, in no particular order.
+func (r *Reservoir[T]) Sample() []T {
+	sample := make([]T, len(r.sample))
+	copy(sample, r.sample)
+	return sample
+}
+
+// Seen returns the number of elements offered to the reservoir.
+func (r *Reservoir[T]) Seen() uint64 {
+	return r.seen
+}
+
+// Len returns the number of elements in the sample.
+func (r *Reservoir[T]) Len() int {
+	return len(r.sample)
+}
+
+// Capacity returns k, the maximum size of the sample.
+func (r *Reservoir[T]) Capacity() int {
+	return r.k
+}
+
+// Clear empties the reservoir so that it can sample a new stream.
+func (r *Reservoir[T]) Clear() {
+	clear(r.sample)
+	r.sample = r.sample[:0]
+	r.seen = 0
+	r.next = 0
+	r.w = 0
+}
+
+// skip sets next to the index of the next element to enter the sample.
+// The gap is geometrically distributed with success probability w.
+func (r *Reservoir[T]) skip() {
+	gap := math.Floor(math.Log(r.random()) / math.Log1p(-r.w))
+	if gap >= float64(math.MaxUint64-r.seen-1) {
+		r.next = math.MaxUint64
+		return
+	}
+	r.next = r.seen + uint64(gap) + 1
+}
+
+// random returns a uniform random number in (0, 1).
+func (r *Reservoir[T]) random() float64 {
+	for {
+		if u := r.rng.Float64(); u > 0 {
+			return u
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package sketch
+
+import (
+	"math/bits"
+
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+)
+
+// SimHash computes 64-bit fingerprints of token streams, such as the words or
+// shingles of a document, such that similar streams get fingerprints that
+// differ in few bits. The Hamming distance between two fingerprints grows with
+// the cosine distance between the streams' token counts.
+//
+// Example:
+//
+//	sh, _ := sketch.NewSimHash()
+//	a := sh.Fingerprint(tokens(doc1))
+//	b := sh.Fingerprint(tokens(doc2))
+//	if sketch.HammingDistance(a, b) <= 3 {
+//		fmt.Println("near-duplicates")
+//	}
+type SimHash struct {
+	hasher hash.Hasher
+}
+
+// NewSimHash creates a new SimHash.
+// It hashes with TigerHasher, which is unkeyed, so fingerprints computed in
+// different processes are comparable.
+func NewSimHash() (*SimHash, error) {
+	return NewSimHashWithHasher(hash.NewTigerHasher())
+}
+
+// NewSimHashWithHasher creates a new SimHash with a custom hasher.
+// The hasher must produce at least 8 bytes.
+func NewSimHashWithHasher(hasher hash.Hasher) (*SimHash, error) {
+	if hasher.Size() < 8 {
+		return nil, errors.New(errors.ErrInvalidArgument, "hasher must produce at least 8 bytes")
+	}
+	return &SimHash{hasher: hasher}, nil
+}
+
+// Fingerprint returns the fingerprint of the given tokens.
+// Each bit of the fingerprint is set if, over all tokens, more token hashes
+// have that bit set than clear. Repeated tokens count once per occurrence.
+func (s *SimHash) Fingerprint(tokens [][]byte) uint64 {
+	var counts [64]int
+	for _, token := range tokens {
+		s.add(&counts, token, 1)
+	}
+	return fingerprintOf(&counts)
+}
+
+// FingerprintFrom returns the fingerprint of the tokens produced by the iterator.
+//
+// Example:
+//
+//	fp := sh.FingerprintFrom(tokenizer.Iterator())
+func (s *SimHash) FingerprintFrom(it collections.Iterator[[]byte]) uint64 {
+	var counts [64]int
+	for it.HasNext() {
+		s.add(&counts, it.Next().Unwrap(), 1)
+	}
+	return fingerprintOf(&counts)
+}
+
+// FingerprintWeighted returns the fingerprint of the given tokens, where each
+// token contributes with the given weight, such as its TF-IDF score.
+// It returns an error if tokens and weights have different lengths.
+func (s *SimHash) FingerprintWeighted(tokens [][]byte, weights []int) (uint64, error) {
+	if len(tokens) != len(weights) {
+		return 0, errors.New(errors.ErrInvalidArgument, "tokens and weights must have the same length")
+	}
+	var counts [64]int
+	for i, token := range tokens {
+		s.add(&counts, token, weights[i])
+	}
+	return fingerprintOf(&counts), nil
+}
+
+// add hashes token and adds weight to the count of every bit that is set in
+// the hash, subtracting it from every bit that is clear.
+func (s *SimHash) add(counts *[64]int, token []byte, weight int) {
+	h := hash.Sum64(s.hasher, token)
+	for bit := range counts {
+		if h&(1<<bit) != 0 {
+			counts[bit] += weight
+		} else {
+			counts[bit] -= weight
+		}
+	}
+}
+
+func fingerprintOf(counts *[64]int) uint64 {
+	var fp uint64
+	for bit, c := range counts {
+		if c > 0 {
+			fp |= 1 << bit
+		}
+	}
+	return fp
+}
+
+// HammingDistance returns the number of bits in which a and b differ.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SimHashIndex finds stored fingerprints within a fixed Hamming distance of a
+// query without comparing against every one of them.
+//
+// With a maximum distance of k, fingerprints are split into k+1 blocks of
+// bits. Two fingerprints that differ in at most k bits agree exactly on at
+// least one block, so the index keeps one table per block, keyed by that
+// block: the fingerprint rotated to bring the block to the front, truncated
+// to the block's width. A query only examines fingerprints that share a block
+// with it.
+//
+// Example:
+//
+//	index, _ := sketch.NewSimHashIndex[string](3)
+//	index.Insert("doc1", fp1)
+//	index.Insert("doc2", fp2)
+//	dups := index.Query(fp) // keys within 3 bits of fp
+type SimHashIndex[K comparable] struct {
+	maxDistance  int
+	rotations    []int
+	widths       []int
+	tables       []map[uint64][]K
+	fingerprints map[K]uint64
+}
+
+// NewSimHashIndex creates a new empty SimHashIndex that answers queries for
+// fingerprints within maxDistance bits, which must be between 0 and 63.
+// Smaller distances give larger blocks and faster queries.
+func NewSimHashIndex[K comparable](maxDistance int) (*SimHashIndex[K], error) {
+	if maxDistance < 0 || maxDistance > 63 {
+		return nil, errors.New(errors.ErrInvalidArgument, "maximum distance must be between 0 and 63")
+	}
+	blocks := maxDistance + 1
+	idx := &SimHashIndex[K]{
+		maxDistance:  maxDistance,
+		rotations:    make([]int, blocks),
+		widths:       make([]int, blocks),
+		tables:       make([]map[uint64][]K, blocks),
+		fingerprints: make(map[K]uint64),
+	}
+	start := 0
+	for i := range idx.tables {
+		// Spread the 64 bits as evenly as possible over the blocks.
+		width := 64 / blocks
+		if i < 64%blocks {
+			width++
+		}
+		idx.rotations[i] = start
+		idx.widths[i] = width
+		idx.tables[i] = make(map[uint64][]K)
+		start += width
+	}
+	return idx, nil
+}
+
+// MaxDistance returns the Hamming distance the index answers queries for.
+func (idx *SimHashIndex[K]) MaxDistance() int {
+	return idx.maxDistance
+}
+
+// Insert adds a fingerprint under the given key, replacing any fingerprint
+// already stored under it.
+func (idx *SimHashIndex[K]) Insert(key K, fp uint64) {
+	idx.Remove(key)
+	idx.fingerprints[key] = fp
+	for i, table := range idx.tables {
+		block := idx.block(fp, i)
+		table[block] = append(table[block], key)
+	}
+}
+
+// Remove removes the fingerprint stored under key and reports whether it was present.
+func (idx *SimHashIndex[K]) Remove(key K) bool {
+	fp, ok := idx.fingerprints[key]
+	if !ok {
+		return false
+	}
+	delete(idx.fingerprints, key)
+	for i, table := range idx.tables {
+		block := idx.block(fp, i)
+		keys := table[block]
+		for j, k := range keys {
+			if k == key {
+				keys = append(keys[:j], keys[j+1:]...)
+				break
+			}
+		}
+		if len(keys) == 0 {
+			delete(table, block)
+		} else {
+			table[block] = keys
+		}
+	}
+	return true
+}
+
+// Query returns the keys of every stored fingerprint within MaxDistance bits of fp.
+func (idx *SimHashIndex[K]) Query(fp uint64) []K {
+	var matches []K
+	seen := make(map[K]struct{})
+	for i, table := range idx.tables {
+		for _, key := range table[idx.block(fp, i)] {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			if HammingDistance(fp, idx.fingerprints[key]) <= idx.maxDistance {
+				matches = append(matches, key)
+			}
+		}
+	}
+	return matches
+}
+
+// Fingerprint returns the fingerprint stored under key and whether it was found.
+func (idx *SimHashIndex[K]) Fingerprint(key K) (uint64, bool) {
+	fp, ok := idx.fingerprints[key]
+	return fp, ok
+}
+
+// Len returns the number of fingerprints in the index.
+func (idx *SimHashIndex[K]) Len() int {
+	return len(idx.fingerprints)
+}
+
+// Clear removes every fingerprint from the index.
+func (idx *SimHashIndex[K]) Clear() {
+	for _, table := range idx.tables {
+		clear(table)
+	}
+	clear(idx.fingerprints)
+}
+
+// block returns the i-th block of fp: fp rotated so the block leads, truncated to its width.
+func (idx *SimHashIndex[K]) block(fp uint64, i int) uint64 {
+	return bits.RotateLeft64(fp, idx.rotations[i]) >> (64 - idx.widths[i])
+}
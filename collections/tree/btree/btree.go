@@ -1,6 +1,8 @@
 package btree
 
 import (
+	"slices"
+
 	"github.com/ielm/neostd/collections"
 	"github.com/ielm/neostd/collections/comp"
 	"github.com/ielm/neostd/collections/tree"
@@ -139,24 +141,14 @@ func (t *BTree[K, V]) IsEmpty() bool {
 	return t.size == 0
 }
 
-// Traverse traverses the BTree in the specified order.
+// Traverse traverses the BTree in the specified order and collects every pair.
+// Use TraverseIter to walk large trees without materializing the result.
 func (t *BTree[K, V]) Traverse(order tree.TraversalOrder) []collections.Pair[K, V] {
 	result := make([]collections.Pair[K, V], 0, t.size)
-	if t.root == nil {
-		return result
+	it := t.TraverseIter(order)
+	for it.HasNext() {
+		result = append(result, it.Next().Unwrap())
 	}
-
-	switch order {
-	case tree.InOrder:
-		t.inOrderTraversal(t.root, &result)
-	case tree.PreOrder:
-		t.preOrderTraversal(t.root, &result)
-	case tree.PostOrder:
-		t.postOrderTraversal(t.root, &result)
-	case tree.LevelOrder:
-		t.levelOrderTraversal(t.root, &result)
-	}
-
 	return result
 }
 
@@ -177,8 +169,8 @@ func (t *BTree[K, V]) splitChild(parent *node[K, V], index int) {
 	child := parent.children[index]
 	newChild := t.createNode(child.leaf)
 
-	parent.keys = append(parent.keys, child.keys[t.degree-1])
-	parent.values = append(parent.values, child.values[t.degree-1])
+	parent.keys = slices.Insert(parent.keys, index, child.keys[t.degree-1])
+	parent.values = slices.Insert(parent.values, index, child.values[t.degree-1])
 	parent.children = append(parent.children, nil)
 
 	copy(parent.children[index+2:], parent.children[index+1:])
@@ -338,81 +330,6 @@ func (t *BTree[K, V]) search(n *node[K, V], key K) (*node[K, V], int) {
 	return n, index
 }
 
-// Traversal methods
-
-func (t *BTree[K, V]) inOrderTraversal(n *node[K, V], result *[]collections.Pair[K, V]) {
-	if n == nil {
-		return
-	}
-
-	for i := 0; i < len(n.keys); i++ {
-		t.inOrderTraversal(n.children[i], result)
-		*result = append(*result, collections.Pair[K, V]{
-			Key:   n.keys[i],
-			Value: n.values[i],
-		})
-	}
-	t.inOrderTraversal(n.children[len(n.keys)], result)
-}
-
-func (t *BTree[K, V]) preOrderTraversal(n *node[K, V], result *[]collections.Pair[K, V]) {
-	if n == nil {
-		return
-	}
-
-	for i := 0; i < len(n.keys); i++ {
-		*result = append(*result, collections.Pair[K, V]{
-			Key:   n.keys[i],
-			Value: n.values[i],
-		})
-		t.preOrderTraversal(n.children[i], result)
-	}
-	t.preOrderTraversal(n.children[len(n.keys)], result)
-}
-
-func (t *BTree[K, V]) postOrderTraversal(n *node[K, V], result *[]collections.Pair[K, V]) {
-	if n == nil {
-		return
-	}
-
-	for i := 0; i < len(n.keys); i++ {
-		t.postOrderTraversal(n.children[i], result)
-	}
-
-	for i := 0; i < len(n.keys); i++ {
-		*result = append(*result, collections.Pair[K, V]{
-			Key:   n.keys[i],
-			Value: n.values[i],
-		})
-	}
-	t.postOrderTraversal(n.children[len(n.keys)], result)
-}
-
-func (t *BTree[K, V]) levelOrderTraversal(n *node[K, V], result *[]collections.Pair[K, V]) {
-	if n == nil {
-		return
-	}
-
-	queue := []*node[K, V]{n}
-	for len(queue) > 0 {
-		curr := queue[0]
-		queue = queue[1:]
-
-		for i := 0; i < len(curr.keys); i++ {
-			*result = append(*result, collections.Pair[K, V]{
-				Key:   curr.keys[i],
-				Value: curr.values[i],
-			})
-			if !curr.leaf {
-				queue = append(queue, curr.children[i])
-			}
-		}
-		if !curr.leaf {
-			queue = append(queue, curr.children[len(curr.keys)])
-		}
-	}
-}
-
 // Implement Map interface methods
 
 // Put inserts a key-value pair into the BTree.
@@ -463,16 +380,23 @@ func (t *BTree[K, V]) ContainsKey(key K) bool {
 // Keys returns a slice of all keys in the BTree.
 func (t *BTree[K, V]) Keys() []K {
 	keys := make([]K, 0, t.size)
-	t.Traverse(tree.InOrder)
+	it := t.TraverseIter(tree.InOrder)
+	for it.HasNext() {
+		keys = append(keys, it.Next().Unwrap().Key)
+	}
 	return keys
 }
 
 // Values returns a slice of all values in the BTree.
 func (t *BTree[K, V]) Values() []V {
 	values := make([]V, 0, t.size)
-	t.Traverse(tree.InOrder)
+	it := t.TraverseIter(tree.InOrder)
+	for it.HasNext() {
+		values = append(values, it.Next().Unwrap().Value)
+	}
 	return values
 }
 
-// Ensure BTree implements the Map interface
+// Ensure BTree implements the Map and Tree interfaces
 var _ collections.Map[int, int] = (*BTree[int, int])(nil)
+var _ tree.Tree[int, int] = (*BTree[int, int])(nil)
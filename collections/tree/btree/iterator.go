@@ -0,0 +1,135 @@
+package btree
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/collections/tree"
+	"github.com/ielm/neostd/res"
+)
+
+// TraverseIter returns a lazy iterator over the BTree in the specified order.
+// Only the path from the root to the current node is held in memory, so deep
+// or very large trees can be walked without materializing every pair.
+//
+// InOrder yields pairs in ascending key order. PreOrder yields a node's keys
+// before its children, PostOrder after them, and LevelOrder walks the tree
+// breadth-first.
+//
+// Example:
+//
+//	it := bt.TraverseIter(tree.InOrder)
+//	for it.HasNext() {
+//		pair := it.Next().Unwrap()
+//		fmt.Println(pair.Key, pair.Value)
+//	}
+func (t *BTree[K, V]) TraverseIter(order tree.TraversalOrder) collections.Iterator[collections.Pair[K, V]] {
+	it := &traversalIterator[K, V]{order: order}
+	if t.root != nil {
+		it.stack = append(it.stack, frame[K, V]{n: t.root})
+	}
+	it.next = it.advance()
+	return it
+}
+
+// frame tracks the progress of a traversal through a single node.
+type frame[K any, V any] struct {
+	n     *node[K, V]
+	key   int
+	child int
+}
+
+// traversalIterator walks a BTree lazily. The stack is used as a LIFO for the
+// depth-first orders and as a FIFO queue for LevelOrder.
+type traversalIterator[K any, V any] struct {
+	order tree.TraversalOrder
+	stack []frame[K, V]
+	next  res.Option[collections.Pair[K, V]]
+}
+
+func (it *traversalIterator[K, V]) HasNext() bool {
+	return it.next.IsSome()
+}
+
+func (it *traversalIterator[K, V]) Next() res.Option[collections.Pair[K, V]] {
+	current := it.next
+	if current.IsSome() {
+		it.next = it.advance()
+	}
+	return current
+}
+
+// advance produces the next pair in traversal order, or None when exhausted.
+func (it *traversalIterator[K, V]) advance() res.Option[collections.Pair[K, V]] {
+	if it.order == tree.LevelOrder {
+		return it.advanceLevelOrder()
+	}
+
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		n := top.n
+
+		switch it.order {
+		case tree.InOrder:
+			// Child i is visited before key i, and the last child after the last key.
+			if !n.leaf && top.child <= top.key && top.child < len(n.children) {
+				child := n.children[top.child]
+				top.child++
+				it.stack = append(it.stack, frame[K, V]{n: child})
+				continue
+			}
+			if top.key < len(n.keys) {
+				top.key++
+				return res.Some(pairAt(n, top.key-1))
+			}
+		case tree.PreOrder:
+			if top.key < len(n.keys) {
+				top.key++
+				return res.Some(pairAt(n, top.key-1))
+			}
+			if !n.leaf && top.child < len(n.children) {
+				child := n.children[top.child]
+				top.child++
+				it.stack = append(it.stack, frame[K, V]{n: child})
+				continue
+			}
+		case tree.PostOrder:
+			if !n.leaf && top.child < len(n.children) {
+				child := n.children[top.child]
+				top.child++
+				it.stack = append(it.stack, frame[K, V]{n: child})
+				continue
+			}
+			if top.key < len(n.keys) {
+				top.key++
+				return res.Some(pairAt(n, top.key-1))
+			}
+		}
+
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+
+	return res.None[collections.Pair[K, V]]()
+}
+
+// advanceLevelOrder yields all keys of the front node before moving on,
+// queueing its children once the node is exhausted.
+func (it *traversalIterator[K, V]) advanceLevelOrder() res.Option[collections.Pair[K, V]] {
+	for len(it.stack) > 0 {
+		front := &it.stack[0]
+		if front.key < len(front.n.keys) {
+			front.key++
+			return res.Some(pairAt(front.n, front.key-1))
+		}
+		if !front.n.leaf {
+			for _, child := range front.n.children {
+				it.stack = append(it.stack, frame[K, V]{n: child})
+			}
+		}
+		it.stack = it.stack[1:]
+	}
+	return res.None[collections.Pair[K, V]]()
+}
+
+// pairAt returns the key-value pair stored at index i of a node.
+func pairAt[K any, V any](n *node[K, V], i int) collections.Pair[K, V] {
+	return collections.Pair[K, V]{Key: n.keys[i], Value: n.values[i]}
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/gob"
 	"fmt"
 	"math/bits"
+	"runtime"
+	"slices"
 	"sync"
 
 	"github.com/ielm/neostd/collections"
 	"github.com/ielm/neostd/collections/comp"
+	"github.com/ielm/neostd/collections/tree/merkle"
 	"github.com/ielm/neostd/errors"
 	"github.com/ielm/neostd/hash"
 	"github.com/ielm/neostd/res"
@@ -63,6 +66,8 @@ func NewWithHasher(data [][]byte, hasher *hash.SipHasher) (*MerkleTree, error) {
 }
 
 // Build constructs the Merkle Tree from the given data.
+// Every leaf node is kept in memory; to only commit to very large inputs,
+// use merkle.Builder, which produces the same root in O(log n) space.
 func (mt *MerkleTree) Build(data [][]byte) error {
 	if len(data) == 0 {
 		return errors.New(errors.ErrInvalidArgument, "cannot build tree with no data")
@@ -92,8 +97,12 @@ func (mt *MerkleTree) buildTree(nodes []*Node[[]byte, []byte]) *Node[[]byte, []b
 	if len(nodes) == 1 {
 		return nodes[0]
 	}
+	return mt.buildTree(buildLevel(mt.hasher, nodes))
+}
 
-	var nextLevel []*Node[[]byte, []byte]
+// buildLevel pairs up nodes into their parent level, duplicating the last node if odd.
+func buildLevel(hasher hash.Hasher, nodes []*Node[[]byte, []byte]) []*Node[[]byte, []byte] {
+	nextLevel := make([]*Node[[]byte, []byte], 0, (len(nodes)+1)/2)
 
 	for i := 0; i < len(nodes); i += 2 {
 		left := nodes[i]
@@ -104,12 +113,70 @@ func (mt *MerkleTree) buildTree(nodes []*Node[[]byte, []byte]) *Node[[]byte, []b
 			right = &Node[[]byte, []byte]{Key: left.Key, Value: left.Value} // Duplicate last node if odd
 		}
 
-		parentHash := mt.hashChildren(left.Value, right.Value)
+		parentHash := merkle.HashNode(hasher, left.Value, right.Value)
 		parent := &Node[[]byte, []byte]{Value: parentHash, Children: []*Node[[]byte, []byte]{left, right}}
 		nextLevel = append(nextLevel, parent)
 	}
 
-	return mt.buildTree(nextLevel)
+	return nextLevel
+}
+
+// BuildParallel constructs the Merkle Tree like Build, but hashes leaves and
+// subtrees on a pool of workers. The leaves are split into aligned chunks of
+// 2^k leaves; each worker builds the complete subtree of height k for a chunk
+// with its own clone of the hasher, and the chunk roots are then merged into
+// the top of the tree. The resulting tree is identical to the one Build produces.
+// If workers is less than 1, runtime.GOMAXPROCS(0) workers are used.
+func (mt *MerkleTree) BuildParallel(data [][]byte, workers int) error {
+	if len(data) == 0 {
+		return errors.New(errors.ErrInvalidArgument, "cannot build tree with no data")
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	n := len(data)
+	chunkHeight := bits.Len(uint((n+workers-1)/workers - 1))
+	chunkSize := 1 << chunkHeight
+	chunkCount := (n + chunkSize - 1) / chunkSize
+
+	mt.leaves = make([]*Node[[]byte, []byte], n)
+	chunkRoots := make([]*Node[[]byte, []byte], chunkCount)
+
+	chunks := make(chan int, chunkCount)
+	for c := 0; c < chunkCount; c++ {
+		chunks <- c
+	}
+	close(chunks)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < chunkCount; w++ {
+		wg.Add(1)
+		go func(hasher hash.Hasher) {
+			defer wg.Done()
+			for c := range chunks {
+				start, end := c*chunkSize, min((c+1)*chunkSize, n)
+				nodes := make([]*Node[[]byte, []byte], 0, end-start)
+				for i := start; i < end; i++ {
+					leaf := &Node[[]byte, []byte]{Key: data[i], Value: merkle.HashLeaf(hasher, data[i])}
+					mt.leaves[i] = leaf
+					nodes = append(nodes, leaf)
+				}
+				// A short final chunk still has to reach the full chunk height so
+				// its root lines up with the roots of the complete chunks.
+				for level := 0; level < chunkHeight; level++ {
+					nodes = buildLevel(hasher, nodes)
+				}
+				chunkRoots[c] = nodes[0]
+			}
+		}(mt.hasher.Clone())
+	}
+	wg.Wait()
+
+	mt.root = mt.buildTree(chunkRoots)
+	mt.size = n
+	mt.levelCount = mt.calculateLevelCount(n)
+	return nil
 }
 
 // GetRoot returns the root hash of the Merkle Tree.
@@ -121,39 +188,42 @@ func (mt *MerkleTree) GetRoot() res.Option[[]byte] {
 }
 
 // GetProof generates a Merkle proof for the data at the given index.
-func (mt *MerkleTree) GetProof(index int) res.Result[[][]byte] {
+// The proof records on which side each sibling sits, so it can be checked
+// with VerifyProof or the standalone merkle.Verify.
+func (mt *MerkleTree) GetProof(index int) res.Result[*merkle.Proof] {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+
 	if index < 0 || index >= len(mt.leaves) {
-		return res.Err[[][]byte](errors.New(errors.ErrOutOfBounds, "index out of bounds"))
+		return res.Err[*merkle.Proof](errors.New(errors.ErrOutOfBounds, "index out of bounds"))
 	}
 
-	proof := make([][]byte, 0, bits.Len(uint(len(mt.leaves)-1)))
-	current := mt.leaves[index]
-	currentIndex := index
-
-	for current != mt.root {
-		isRightChild := currentIndex%2 == 1
-		sibling := mt.getSibling(current, isRightChild)
+	proof := &merkle.Proof{
+		Index:      uint64(index),
+		Siblings:   make([][]byte, mt.levelCount),
+		Directions: make([]merkle.Direction, mt.levelCount),
+	}
 
-		if sibling != nil {
-			proof = append(proof, sibling.Value)
+	// Walk down from the root following the bits of the index; the sibling at
+	// depth d belongs at position levelCount-1-d of the bottom-up proof.
+	current := mt.root
+	for depth := mt.levelCount - 1; depth >= 0; depth-- {
+		bit := (index >> depth) & 1
+		proof.Siblings[depth] = current.Children[1-bit].Value
+		if bit == 1 {
+			proof.Directions[depth] = merkle.Left
+		} else {
+			proof.Directions[depth] = merkle.Right
 		}
-
-		current = mt.getParent(current)
-		currentIndex /= 2
+		current = current.Children[bit]
 	}
 
 	return res.Ok(proof)
 }
 
 // VerifyProof verifies a Merkle proof for the given data and root hash.
-func (mt *MerkleTree) VerifyProof(data []byte, proof [][]byte, rootHash []byte) bool {
-	computedHash := mt.hashData(data)
-
-	for _, proofElement := range proof {
-		computedHash = mt.hashChildren(computedHash, proofElement)
-	}
-
-	return comp.ByteSliceComparator(computedHash, rootHash) == 0
+func (mt *MerkleTree) VerifyProof(data []byte, proof *merkle.Proof, rootHash []byte) bool {
+	return merkle.Verify(rootHash, data, proof, mt.hasher)
 }
 
 // Update updates the value at the given index and recalculates the affected hashes.
@@ -222,19 +292,14 @@ func (mt *MerkleTree) Diff(other *MerkleTree) res.Result[[]int] {
 	return res.Ok(diffIndices)
 }
 
-// hashData now uses the SipHasher
+// hashData hashes a leaf's data with the tree's hasher.
 func (mt *MerkleTree) hashData(data []byte) []byte {
-	mt.hasher.Reset()
-	mt.hasher.Write(data)
-	return mt.hasher.Sum(nil)
+	return merkle.HashLeaf(mt.hasher, data)
 }
 
-// hashChildren now uses the SipHasher
+// hashChildren hashes a pair of child hashes with the tree's hasher.
 func (mt *MerkleTree) hashChildren(left, right []byte) []byte {
-	mt.hasher.Reset()
-	mt.hasher.Write(left)
-	mt.hasher.Write(right)
-	return mt.hasher.Sum(nil)
+	return merkle.HashNode(mt.hasher, left, right)
 }
 
 // getSibling returns the sibling node of the given node.
@@ -344,6 +409,47 @@ func (it *merkleReverseIterator) Next() res.Option[[]byte] {
 	return res.Some(value)
 }
 
+// TraverseIter returns a lazy iterator over the leaves of the tree, yielding
+// the same pairs as Traverse: each leaf's data as the key and its hash as the
+// value, in insertion order for every traversal order. The iterator walks the
+// leaves present when TraverseIter was called.
+//
+// Example:
+//
+//	it := mt.TraverseIter(tree.LevelOrder)
+//	for it.HasNext() {
+//		leaf := it.Next().Unwrap()
+//		fmt.Printf("%s: %x\n", leaf.Key, leaf.Value)
+//	}
+func (mt *MerkleTree) TraverseIter(order TraversalOrder) collections.Iterator[collections.Pair[[]byte, []byte]] {
+	mt.mu.RLock()
+	defer mt.mu.RUnlock()
+	// Copy the leaf pointers, since Remove shifts leaves within the slice
+	return &merkleLeafIterator{tree: mt, leaves: slices.Clone(mt.leaves)}
+}
+
+type merkleLeafIterator struct {
+	tree   *MerkleTree
+	leaves []*Node[[]byte, []byte]
+	index  int
+}
+
+func (it *merkleLeafIterator) HasNext() bool {
+	return it.index < len(it.leaves)
+}
+
+func (it *merkleLeafIterator) Next() res.Option[collections.Pair[[]byte, []byte]] {
+	if !it.HasNext() {
+		return res.None[collections.Pair[[]byte, []byte]]()
+	}
+	// Leaf hashes are updated in place, so read them under the tree's lock
+	it.tree.mu.RLock()
+	defer it.tree.mu.RUnlock()
+	leaf := it.leaves[it.index]
+	it.index++
+	return res.Some(collections.Pair[[]byte, []byte]{Key: leaf.Key, Value: leaf.Value})
+}
+
 // Ensure MerkleTree implements the Set interface
 var _ collections.Set[[]byte] = (*MerkleTree)(nil)
 
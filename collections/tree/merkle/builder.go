@@ -0,0 +1,101 @@
+package merkle
+
+import (
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+	"github.com/ielm/neostd/res"
+)
+
+// Builder computes a Merkle root over a stream of leaves while holding only
+// O(log n) hashes in memory.
+//
+// It keeps a frontier with one slot per level: slot l holds the root of a
+// complete, still unpaired subtree of 2^l leaves. Appending a leaf works like
+// incrementing a binary counter, merging equal-sized subtrees as it carries.
+// The root produced by Finalize is identical to the one tree.MerkleTree
+// computes for the same leaves, including its duplicate-last-node rule for
+// levels with an odd number of nodes.
+//
+// Example:
+//
+//	b := merkle.NewBuilder(hash.NewBlake3Hasher())
+//	for chunk := range chunks {
+//		b.Append(chunk)
+//	}
+//	root := b.Finalize().Unwrap()
+type Builder struct {
+	hasher   hash.Hasher
+	frontier [][]byte
+	count    uint64
+}
+
+// NewBuilder creates a new Builder that hashes with the given hasher.
+// A cryptographic hasher such as hash.Blake3Hasher is recommended: roots
+// are only as collision resistant as the hash behind them.
+// For roots that interoperate with THEX, the Tiger tree hash of file-sharing
+// protocols, use hash.TigerTreeHasher instead, which pairs odd nodes
+// differently and prefixes leaves and nodes.
+func NewBuilder(hasher hash.Hasher) *Builder {
+	return &Builder{hasher: hasher}
+}
+
+// Append hashes a leaf and folds it into the frontier.
+func (b *Builder) Append(leaf []byte) {
+	carry := HashLeaf(b.hasher, leaf)
+	for level := 0; ; level++ {
+		if level == len(b.frontier) {
+			b.frontier = append(b.frontier, carry)
+			break
+		}
+		if b.frontier[level] == nil {
+			b.frontier[level] = carry
+			break
+		}
+		carry = HashNode(b.hasher, b.frontier[level], carry)
+		b.frontier[level] = nil
+	}
+	b.count++
+}
+
+// Count returns the number of leaves appended so far.
+func (b *Builder) Count() uint64 {
+	return b.count
+}
+
+// Finalize returns the root over all leaves appended so far.
+// It does not modify the builder, so more leaves may be appended afterwards.
+func (b *Builder) Finalize() res.Result[[]byte] {
+	if b.count == 0 {
+		return res.Err[[]byte](errors.New(errors.ErrInvalidArgument, "cannot finalize builder with no leaves"))
+	}
+
+	top := len(b.frontier) - 1
+	for b.frontier[top] == nil {
+		top--
+	}
+
+	// Close the pending subtrees from the bottom up. A node without a partner
+	// on its level is paired with itself, unless it is the final root.
+	var carry []byte
+	for level := 0; level <= top; level++ {
+		pending := b.frontier[level]
+		switch {
+		case pending != nil && carry != nil:
+			carry = HashNode(b.hasher, pending, carry)
+		case pending != nil && level == top:
+			carry = pending
+		case pending != nil:
+			carry = HashNode(b.hasher, pending, pending)
+		case carry != nil:
+			carry = HashNode(b.hasher, carry, carry)
+		}
+	}
+
+	return res.Ok(carry)
+}
+
+// Reset discards all appended leaves so the builder can be reused.
+func (b *Builder) Reset() {
+	b.frontier = b.frontier[:0]
+	b.count = 0
+}
@@ -0,0 +1,172 @@
+// Package merkle provides the hashing primitives, inclusion proof format, and
+// standalone verification shared by the Merkle structures in the tree package.
+//
+// Proofs produced by a tree can be serialized with MarshalBinary, shipped to a
+// client, and checked with Verify against a trusted root without the client
+// ever constructing a tree.
+package merkle
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+)
+
+// Direction indicates on which side of the running hash a proof sibling sits.
+type Direction uint8
+
+const (
+	// Left means the sibling is the left child, so the running hash is hashed on the right.
+	Left Direction = iota
+	// Right means the sibling is the right child, so the running hash is hashed on the left.
+	Right
+)
+
+// Proof is an inclusion proof for a single leaf of a Merkle tree.
+// Siblings and Directions are ordered from the leaf level up to the root.
+type Proof struct {
+	Index      uint64
+	Siblings   [][]byte
+	Directions []Direction
+}
+
+// Len returns the number of sibling hashes in the proof.
+func (p *Proof) Len() int {
+	return len(p.Siblings)
+}
+
+// HashLeaf computes the hash of a leaf's data with the given hasher.
+func HashLeaf(h hash.Hasher, data []byte) []byte {
+	h.Reset()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// HashNode computes the hash of an internal node from its children's hashes.
+func HashNode(h hash.Hasher, left, right []byte) []byte {
+	h.Reset()
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// Verify checks that leaf is included under root according to proof.
+// The hasher must be configured identically to the one that built the tree.
+//
+// Example:
+//
+//	proof := mt.GetProof(2).Unwrap()
+//	ok := merkle.Verify(root, []byte("data3"), proof, hasher)
+func Verify(root, leaf []byte, proof *Proof, hasher hash.Hasher) bool {
+	if proof == nil || len(proof.Siblings) != len(proof.Directions) {
+		return false
+	}
+
+	computed := HashLeaf(hasher, leaf)
+	for i, sibling := range proof.Siblings {
+		if proof.Directions[i] == Left {
+			computed = HashNode(hasher, sibling, computed)
+		} else {
+			computed = HashNode(hasher, computed, sibling)
+		}
+	}
+
+	return bytes.Equal(computed, root)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The format is compact: the leaf index, sibling count, and hash length as
+// uvarints, followed by a direction bitmap (one bit per sibling, set for Left)
+// and the concatenated sibling hashes. All siblings must have the same length.
+//
+// Example:
+//
+//	data, err := proof.MarshalBinary()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	if len(p.Siblings) != len(p.Directions) {
+		return nil, errors.New(errors.ErrInvalidArgument, "proof siblings and directions differ in length")
+	}
+
+	hashLen := 0
+	if len(p.Siblings) > 0 {
+		hashLen = len(p.Siblings[0])
+	}
+	for _, sibling := range p.Siblings {
+		if len(sibling) != hashLen {
+			return nil, errors.New(errors.ErrInvalidArgument, "proof siblings must have equal length")
+		}
+	}
+
+	count := len(p.Siblings)
+	data := make([]byte, 0, 3*binary.MaxVarintLen64+(count+7)/8+count*hashLen)
+	data = binary.AppendUvarint(data, p.Index)
+	data = binary.AppendUvarint(data, uint64(count))
+	data = binary.AppendUvarint(data, uint64(hashLen))
+
+	bitmap := make([]byte, (count+7)/8)
+	for i, dir := range p.Directions {
+		if dir == Left {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	data = append(data, bitmap...)
+
+	for _, sibling := range p.Siblings {
+		data = append(data, sibling...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+//
+// Example:
+//
+//	var proof merkle.Proof
+//	if err := proof.UnmarshalBinary(data); err != nil {
+//		log.Fatal(err)
+//	}
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	index, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New(errors.ErrInvalidArgument, "invalid proof index")
+	}
+	data = data[n:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New(errors.ErrInvalidArgument, "invalid proof sibling count")
+	}
+	data = data[n:]
+
+	hashLen, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errors.New(errors.ErrInvalidArgument, "invalid proof hash length")
+	}
+	data = data[n:]
+
+	bitmapLen := (count + 7) / 8
+	if count > uint64(len(data)) || hashLen > uint64(len(data)) || uint64(len(data)) != bitmapLen+count*hashLen {
+		return errors.New(errors.ErrInvalidArgument, "invalid data length")
+	}
+	bitmap := data[:bitmapLen]
+	data = data[bitmapLen:]
+
+	p.Index = index
+	p.Siblings = make([][]byte, count)
+	p.Directions = make([]Direction, count)
+	for i := uint64(0); i < count; i++ {
+		p.Siblings[i] = append([]byte(nil), data[i*hashLen:(i+1)*hashLen]...)
+		if bitmap[i/8]&(1<<(i%8)) != 0 {
+			p.Directions[i] = Left
+		} else {
+			p.Directions[i] = Right
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package tree
+
+import (
+	"bytes"
+	"math/bits"
+	"sync"
+
+	"github.com/ielm/neostd/collections/tree/merkle"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+	"github.com/ielm/neostd/res"
+)
+
+// MMR is a Merkle Mountain Range: an append-only commitment made of a list of
+// perfect binary Merkle trees ("mountains") whose sizes follow the binary
+// representation of the leaf count.
+//
+// Appending a leaf only hashes the mountains it merges, so appends cost
+// O(log n) instead of the full rebuild MerkleTree.Add performs. Node hashes
+// are stored in post-order and never rewritten, which means the MMR at any
+// earlier leaf count is a prefix of the current one: roots and inclusion
+// proofs can be produced against any historical size.
+//
+// The root is obtained by "bagging" the mountain peaks from right to left.
+//
+// Example:
+//
+//	mmr := tree.NewMMR(hasher)
+//	mmr.Append([]byte("a"))
+//	mmr.Append([]byte("b"))
+//	root := mmr.GetRoot().Unwrap()
+//	proof := mmr.GetProof(0, mmr.Size()).Unwrap()
+//	ok := tree.VerifyMMRProof(root, []byte("a"), proof, hasher)
+type MMR struct {
+	nodes    [][]byte
+	leaves   uint64
+	hasher   hash.Hasher
+	hasherMu sync.Mutex // Serializes use of the hasher by concurrent readers
+	mu       sync.RWMutex
+}
+
+// MMRProof is an inclusion proof for a leaf against the root of an MMR with
+// LeafCount leaves. Path proves the leaf against the peak of its mountain,
+// and Peaks holds every peak at that size, in left to right order.
+type MMRProof struct {
+	LeafIndex uint64
+	LeafCount uint64
+	Path      *merkle.Proof
+	Peaks     [][]byte
+	PeakIndex int
+}
+
+// NewMMR creates a new empty MMR that hashes with the given hasher.
+func NewMMR(hasher hash.Hasher) *MMR {
+	return &MMR{hasher: hasher}
+}
+
+// Append adds a leaf to the MMR and returns its leaf index.
+func (m *MMR) Append(data []byte) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := m.leaves
+	current := merkle.HashLeaf(m.hasher, data)
+	m.nodes = append(m.nodes, current)
+
+	// Each trailing one bit of the old leaf count is a mountain of equal
+	// height directly to the left that now merges with the new one.
+	for height := 0; height < bits.TrailingZeros64(index+1); height++ {
+		left := m.nodes[len(m.nodes)-1-mountainNodes(height)]
+		current = merkle.HashNode(m.hasher, left, current)
+		m.nodes = append(m.nodes, current)
+	}
+
+	m.leaves++
+	return index
+}
+
+// Size returns the number of leaves in the MMR.
+func (m *MMR) Size() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.leaves
+}
+
+// NodeCount returns the total number of stored nodes, leaves included.
+func (m *MMR) NodeCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.nodes)
+}
+
+// IsEmpty returns true if the MMR has no leaves.
+func (m *MMR) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Clear removes all leaves from the MMR.
+func (m *MMR) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodes = nil
+	m.leaves = 0
+}
+
+// GetRoot returns the current root of the MMR.
+func (m *MMR) GetRoot() res.Option[[]byte] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.leaves == 0 {
+		return res.None[[]byte]()
+	}
+	return res.Some(m.bagPeaks(m.peaks(m.leaves)))
+}
+
+// GetRootAt returns the root the MMR had when it contained leafCount leaves.
+func (m *MMR) GetRootAt(leafCount uint64) res.Result[[]byte] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if leafCount == 0 || leafCount > m.leaves {
+		return res.Err[[]byte](errors.New(errors.ErrOutOfBounds, "leaf count out of bounds"))
+	}
+	return res.Ok(m.bagPeaks(m.peaks(leafCount)))
+}
+
+// GetProof generates an inclusion proof for the leaf at leafIndex against the
+// root of the MMR at leafCount leaves. Passing Size() proves against the
+// current root; smaller counts prove against historical roots.
+func (m *MMR) GetProof(leafIndex, leafCount uint64) res.Result[*MMRProof] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if leafCount == 0 || leafCount > m.leaves {
+		return res.Err[*MMRProof](errors.New(errors.ErrOutOfBounds, "leaf count out of bounds"))
+	}
+	if leafIndex >= leafCount {
+		return res.Err[*MMRProof](errors.New(errors.ErrOutOfBounds, "index out of bounds"))
+	}
+
+	proof := &MMRProof{
+		LeafIndex: leafIndex,
+		LeafCount: leafCount,
+		Peaks:     m.peaks(leafCount),
+	}
+
+	// Find the mountain containing the leaf, tracking the leaf and node
+	// offsets at which that mountain starts.
+	leafOffset, nodeOffset := uint64(0), 0
+	for height := bits.Len64(leafCount) - 1; height >= 0; height-- {
+		if leafCount&(1<<height) == 0 {
+			continue
+		}
+		if leafIndex < leafOffset+1<<height {
+			proof.Path = m.mountainPath(nodeOffset, height, leafIndex-leafOffset)
+			proof.Path.Index = leafIndex
+			break
+		}
+		leafOffset += 1 << height
+		nodeOffset += mountainNodes(height)
+		proof.PeakIndex++
+	}
+
+	return res.Ok(proof)
+}
+
+// VerifyMMRProof checks that leaf is included under root according to proof.
+// The hasher must be configured identically to the one used by the MMR.
+func VerifyMMRProof(root, leaf []byte, proof *MMRProof, hasher hash.Hasher) bool {
+	if proof == nil || proof.PeakIndex < 0 || proof.PeakIndex >= len(proof.Peaks) {
+		return false
+	}
+	if !merkle.Verify(proof.Peaks[proof.PeakIndex], leaf, proof.Path, hasher) {
+		return false
+	}
+
+	bagged := proof.Peaks[len(proof.Peaks)-1]
+	for i := len(proof.Peaks) - 2; i >= 0; i-- {
+		bagged = merkle.HashNode(hasher, proof.Peaks[i], bagged)
+	}
+	return bytes.Equal(bagged, root)
+}
+
+// mountainPath builds the bottom-up proof for the leaf at localIndex within
+// the mountain of the given height starting at nodeOffset.
+func (m *MMR) mountainPath(nodeOffset, height int, localIndex uint64) *merkle.Proof {
+	path := &merkle.Proof{
+		Siblings:   make([][]byte, height),
+		Directions: make([]merkle.Direction, height),
+	}
+
+	for h := height; h > 0; h-- {
+		half := uint64(1) << (h - 1)
+		leftRoot := nodeOffset + mountainNodes(h-1) - 1
+		rightRoot := leftRoot + mountainNodes(h-1)
+		if localIndex < half {
+			path.Siblings[h-1] = m.nodes[rightRoot]
+			path.Directions[h-1] = merkle.Right
+		} else {
+			path.Siblings[h-1] = m.nodes[leftRoot]
+			path.Directions[h-1] = merkle.Left
+			nodeOffset += mountainNodes(h - 1)
+			localIndex -= half
+		}
+	}
+
+	return path
+}
+
+// peaks returns the mountain peaks of the MMR at leafCount, left to right.
+func (m *MMR) peaks(leafCount uint64) [][]byte {
+	peaks := make([][]byte, 0, bits.OnesCount64(leafCount))
+	nodeOffset := 0
+	for height := bits.Len64(leafCount) - 1; height >= 0; height-- {
+		if leafCount&(1<<height) != 0 {
+			nodeOffset += mountainNodes(height)
+			peaks = append(peaks, m.nodes[nodeOffset-1])
+		}
+	}
+	return peaks
+}
+
+// bagPeaks folds the peaks from right to left into a single root hash.
+// Readers call it under the read lock, so it takes the hasher lock itself.
+func (m *MMR) bagPeaks(peaks [][]byte) []byte {
+	m.hasherMu.Lock()
+	defer m.hasherMu.Unlock()
+	bagged := peaks[len(peaks)-1]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		bagged = merkle.HashNode(m.hasher, peaks[i], bagged)
+	}
+	return bagged
+}
+
+// mountainNodes returns the number of nodes in a perfect mountain of the given height.
+func mountainNodes(height int) int {
+	return 1<<(height+1) - 1
+}
@@ -0,0 +1,319 @@
+package tree
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// SuffixTree is a compressed trie of all suffixes of a string, built in O(n)
+// time with Ukkonen's algorithm.
+//
+// Once built, substring queries run in O(m) time in the length of the pattern,
+// independent of the length of the text, and structural queries such as the
+// longest repeated substring are answered with a single walk of the tree.
+// The tree operates on runes, so multi-byte UTF-8 text is handled correctly;
+// positions reported to callers are byte offsets into the original text, like
+// the ones returned by kmp.KMP and rabinkarp.RabinKarp.
+//
+// Example:
+//
+//	st := tree.NewSuffixTree("banana")
+//	st.Contains("nan")                   // true
+//	st.FindAll("ana")                    // [1 3]
+//	st.LongestRepeatedSubstring()        // "ana"
+//	st.LongestCommonSubstring("cabana")  // "bana"
+type SuffixTree struct {
+	text    string
+	symbols []rune
+	offsets []int
+	owners  []int
+	nodes   []suffixNode
+	leafEnd int
+}
+
+// suffixNode is a node of the suffix tree. The edge leading into the node is
+// labelled symbols[start:end]; leaves use an end of -1, meaning the shared
+// leaf end, so that every leaf grows in O(1) during construction.
+type suffixNode struct {
+	start    int
+	end      int
+	link     int
+	suffix   int
+	children map[rune]int
+}
+
+const suffixRoot = 0
+
+// NewSuffixTree builds a suffix tree over text.
+func NewSuffixTree(text string) *SuffixTree {
+	return buildSuffixTree(text)
+}
+
+// buildSuffixTree builds a generalized suffix tree over texts. Each text is
+// followed by its own unique negative terminator so that no suffix is a prefix
+// of another and every leaf can be attributed to exactly one text.
+func buildSuffixTree(texts ...string) *SuffixTree {
+	st := &SuffixTree{text: texts[0]}
+
+	for i, text := range texts {
+		for offset, r := range text {
+			st.symbols = append(st.symbols, r)
+			st.offsets = append(st.offsets, offset)
+			st.owners = append(st.owners, i)
+		}
+		st.symbols = append(st.symbols, rune(-1-i))
+		st.offsets = append(st.offsets, len(text))
+		st.owners = append(st.owners, i)
+	}
+
+	st.nodes = make([]suffixNode, 0, 2*len(st.symbols))
+	st.newNode(-1, -1, -1)
+	st.build()
+	return st
+}
+
+// build runs Ukkonen's algorithm over the symbols.
+func (st *SuffixTree) build() {
+	activeNode, activeEdge, activeLength := suffixRoot, 0, 0
+	remainder := 0
+
+	for i, symbol := range st.symbols {
+		st.leafEnd = i + 1
+		remainder++
+		lastInternal := -1
+
+		for remainder > 0 {
+			if activeLength == 0 {
+				activeEdge = i
+			}
+
+			next, ok := st.nodes[activeNode].children[st.symbols[activeEdge]]
+			if !ok {
+				// Rule 2: no edge starts with the symbol, so hang a new leaf.
+				st.nodes[activeNode].children[st.symbols[activeEdge]] = st.newNode(i, -1, i-remainder+1)
+				if lastInternal != -1 {
+					st.nodes[lastInternal].link = activeNode
+					lastInternal = -1
+				}
+			} else {
+				// Walk down when the active point spans the whole edge.
+				if edgeLen := st.edgeLength(next); activeLength >= edgeLen {
+					activeEdge += edgeLen
+					activeLength -= edgeLen
+					activeNode = next
+					continue
+				}
+
+				// Rule 3: the symbol is already present, so this phase ends.
+				if st.symbols[st.nodes[next].start+activeLength] == symbol {
+					if lastInternal != -1 && activeNode != suffixRoot {
+						st.nodes[lastInternal].link = activeNode
+					}
+					activeLength++
+					break
+				}
+
+				// Rule 2: split the edge and hang a new leaf from the split.
+				start := st.nodes[next].start
+				split := st.newNode(start, start+activeLength, -1)
+				st.nodes[activeNode].children[st.symbols[activeEdge]] = split
+				st.nodes[split].children[symbol] = st.newNode(i, -1, i-remainder+1)
+				st.nodes[next].start += activeLength
+				st.nodes[split].children[st.symbols[st.nodes[next].start]] = next
+
+				if lastInternal != -1 {
+					st.nodes[lastInternal].link = split
+				}
+				lastInternal = split
+			}
+
+			remainder--
+			if activeNode == suffixRoot && activeLength > 0 {
+				activeLength--
+				activeEdge = i - remainder + 1
+			} else if activeNode != suffixRoot {
+				activeNode = st.nodes[activeNode].link
+			}
+		}
+	}
+}
+
+// newNode appends a node and returns its index. Suffix links default to the root.
+func (st *SuffixTree) newNode(start, end, suffix int) int {
+	st.nodes = append(st.nodes, suffixNode{
+		start:    start,
+		end:      end,
+		link:     suffixRoot,
+		suffix:   suffix,
+		children: make(map[rune]int),
+	})
+	return len(st.nodes) - 1
+}
+
+// edgeLength returns the number of symbols on the edge leading into node n.
+func (st *SuffixTree) edgeLength(n int) int {
+	end := st.nodes[n].end
+	if end == -1 {
+		end = st.leafEnd
+	}
+	return end - st.nodes[n].start
+}
+
+// Text returns the text the suffix tree was built from.
+func (st *SuffixTree) Text() string {
+	return st.text
+}
+
+// Len returns the number of runes in the text.
+func (st *SuffixTree) Len() int {
+	return utf8.RuneCountInString(st.text)
+}
+
+// Contains reports whether pattern is a substring of the text.
+// The empty pattern is contained in every text.
+func (st *SuffixTree) Contains(pattern string) bool {
+	_, ok := st.locate(pattern)
+	return ok
+}
+
+// Count returns the number of, possibly overlapping, occurrences of pattern in the text.
+// The empty pattern occurs at every rune boundary, Len()+1 times.
+func (st *SuffixTree) Count(pattern string) int {
+	if pattern == "" {
+		return st.Len() + 1
+	}
+	n, ok := st.locate(pattern)
+	if !ok {
+		return 0
+	}
+	count := 0
+	st.forEachLeaf(n, func(int) { count++ })
+	return count
+}
+
+// FindAll returns the byte offsets of every, possibly overlapping, occurrence
+// of pattern in the text, in ascending order. The empty pattern matches at
+// every rune boundary, including the end of the text.
+//
+// Example:
+//
+//	st := tree.NewSuffixTree("abracadabra")
+//	st.FindAll("abra") // [0 7]
+func (st *SuffixTree) FindAll(pattern string) []int {
+	if pattern == "" {
+		matches := make([]int, 0, st.Len()+1)
+		for offset := range st.text {
+			matches = append(matches, offset)
+		}
+		return append(matches, len(st.text))
+	}
+	n, ok := st.locate(pattern)
+	if !ok {
+		return []int{}
+	}
+	matches := []int{}
+	st.forEachLeaf(n, func(suffix int) {
+		matches = append(matches, st.offsets[suffix])
+	})
+	sort.Ints(matches)
+	return matches
+}
+
+// LongestRepeatedSubstring returns the longest substring that occurs at least
+// twice in the text, or an empty string if no symbol repeats. Occurrences may
+// overlap. Ties are broken arbitrarily.
+func (st *SuffixTree) LongestRepeatedSubstring() string {
+	best, bestDepth := -1, 0
+	var walk func(n, depth int)
+	walk = func(n, depth int) {
+		if len(st.nodes[n].children) == 0 {
+			return
+		}
+		if depth > bestDepth {
+			best, bestDepth = n, depth
+		}
+		for _, child := range st.nodes[n].children {
+			walk(child, depth+st.edgeLength(child))
+		}
+	}
+	walk(suffixRoot, 0)
+
+	if best == -1 {
+		return ""
+	}
+	return st.label(best, bestDepth)
+}
+
+// LongestCommonSubstring returns the longest substring shared by the text and
+// other, or an empty string if they have no symbol in common. It builds a
+// generalized suffix tree over both strings in O(n + m) time.
+func (st *SuffixTree) LongestCommonSubstring(other string) string {
+	gst := buildSuffixTree(st.text, other)
+
+	best, bestDepth := -1, 0
+	// walk returns a bitmask of the texts whose suffixes lie below n.
+	var walk func(n, depth int) int
+	walk = func(n, depth int) int {
+		node := &gst.nodes[n]
+		if len(node.children) == 0 {
+			return 1 << gst.owners[node.suffix]
+		}
+		mask := 0
+		for _, child := range node.children {
+			mask |= walk(child, depth+gst.edgeLength(child))
+		}
+		if mask == 3 && depth > bestDepth {
+			best, bestDepth = n, depth
+		}
+		return mask
+	}
+	walk(suffixRoot, 0)
+
+	if best == -1 {
+		return ""
+	}
+	return gst.label(best, bestDepth)
+}
+
+// locate walks pattern down from the root and returns the node at or directly
+// below the end of the match.
+func (st *SuffixTree) locate(pattern string) (int, bool) {
+	n := suffixRoot
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); {
+		child, ok := st.nodes[n].children[runes[i]]
+		if !ok {
+			return 0, false
+		}
+		start, length := st.nodes[child].start, st.edgeLength(child)
+		for j := 0; j < length && i < len(runes); j, i = j+1, i+1 {
+			if st.symbols[start+j] != runes[i] {
+				return 0, false
+			}
+		}
+		n = child
+	}
+	return n, true
+}
+
+// forEachLeaf calls fn with the suffix start of every leaf below node n.
+func (st *SuffixTree) forEachLeaf(n int, fn func(suffix int)) {
+	stack := []int{n}
+	for len(stack) > 0 {
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if len(st.nodes[n].children) == 0 {
+			fn(st.nodes[n].suffix)
+			continue
+		}
+		for _, child := range st.nodes[n].children {
+			stack = append(stack, child)
+		}
+	}
+}
+
+// label returns the path label of internal node n, which has the given string depth.
+func (st *SuffixTree) label(n, depth int) string {
+	end := st.nodes[n].end
+	return string(st.symbols[end-depth : end])
+}
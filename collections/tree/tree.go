@@ -15,6 +15,7 @@ type Tree[K comparable, V any] interface {
 	Delete(key K) error
 	Search(key K) (*Node[K, V], bool)
 	Traverse(order TraversalOrder) []collections.Pair[K, V]
+	TraverseIter(order TraversalOrder) collections.Iterator[collections.Pair[K, V]]
 }
 
 // Node represents a node in the tree
@@ -126,5 +127,96 @@ func (it *treeIterator[K, V]) Next() res.Option[collections.Pair[K, V]] {
 	return res.Some(collections.Pair[K, V]{Key: node.Key, Value: node.Value})
 }
 
+// TraverseIter returns a lazy iterator over the tree's nodes in the specified order.
+// For nodes with more than two children, InOrder visits the first child, then
+// the node itself, then the remaining children.
+func (t *BaseTree[K, V]) TraverseIter(order TraversalOrder) collections.Iterator[collections.Pair[K, V]] {
+	return newNodeIterator(t.root, order)
+}
+
+// nodeFrame tracks the progress of a traversal through a single node.
+type nodeFrame[K any, V any] struct {
+	node    *Node[K, V]
+	child   int
+	visited bool
+}
+
+// nodeIterator walks a tree of Nodes lazily, holding only the current path
+// for the depth-first orders and the frontier for LevelOrder.
+type nodeIterator[K any, V any] struct {
+	order TraversalOrder
+	stack []nodeFrame[K, V]
+	next  res.Option[collections.Pair[K, V]]
+}
+
+func newNodeIterator[K any, V any](root *Node[K, V], order TraversalOrder) *nodeIterator[K, V] {
+	it := &nodeIterator[K, V]{order: order}
+	if root != nil {
+		it.stack = append(it.stack, nodeFrame[K, V]{node: root})
+	}
+	it.next = it.advance()
+	return it
+}
+
+func (it *nodeIterator[K, V]) HasNext() bool {
+	return it.next.IsSome()
+}
+
+func (it *nodeIterator[K, V]) Next() res.Option[collections.Pair[K, V]] {
+	current := it.next
+	if current.IsSome() {
+		it.next = it.advance()
+	}
+	return current
+}
+
+func (it *nodeIterator[K, V]) advance() res.Option[collections.Pair[K, V]] {
+	if it.order == LevelOrder {
+		if len(it.stack) == 0 {
+			return res.None[collections.Pair[K, V]]()
+		}
+		node := it.stack[0].node
+		it.stack = it.stack[1:]
+		for _, child := range node.Children {
+			if child != nil {
+				it.stack = append(it.stack, nodeFrame[K, V]{node: child})
+			}
+		}
+		return res.Some(collections.Pair[K, V]{Key: node.Key, Value: node.Value})
+	}
+
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		node := top.node
+
+		// Decide whether the node itself is due before its next child.
+		due := false
+		switch it.order {
+		case PreOrder:
+			due = !top.visited
+		case InOrder:
+			due = !top.visited && (top.child >= 1 || len(node.Children) == 0)
+		case PostOrder:
+			due = !top.visited && top.child >= len(node.Children)
+		}
+		if due {
+			top.visited = true
+			return res.Some(collections.Pair[K, V]{Key: node.Key, Value: node.Value})
+		}
+
+		if top.child < len(node.Children) {
+			child := node.Children[top.child]
+			top.child++
+			if child != nil {
+				it.stack = append(it.stack, nodeFrame[K, V]{node: child})
+			}
+			continue
+		}
+
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+	return res.None[collections.Pair[K, V]]()
+}
+
 // Ensure baseTree implements the Collection interface
 // var _ collections.Collection[int] = (*baseTree[int])(nil)
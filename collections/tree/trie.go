@@ -27,6 +27,7 @@
 package tree
 
 import (
+	"slices"
 	"unicode/utf8"
 
 	"github.com/ielm/neostd/collections"
@@ -144,22 +145,109 @@ func (t *Trie[T]) Search(key string) (*Node[string, T], bool) {
 // Traverse returns the words in the trie based on the given traversal order.
 func (t *Trie[T]) Traverse(order TraversalOrder) []collections.Pair[string, T] {
 	var result []collections.Pair[string, T]
-	var dfs func(node *trieNode[T], current []rune)
-	dfs = func(node *trieNode[T], current []rune) {
-		if node.isEnd {
-			result = append(result, collections.Pair[string, T]{
-				Key:   string(current),
-				Value: *node.value,
-			})
-		}
-		node.children.ForEach(func(ch rune, child *trieNode[T]) {
-			dfs(child, append(current, ch))
-		})
+	it := t.TraverseIter(order)
+	for it.HasNext() {
+		result = append(result, it.Next().Unwrap())
 	}
-	dfs(t.root, []rune{})
 	return result
 }
 
+// TraverseIter returns a lazy iterator over the words in the trie and their values.
+// PreOrder and InOrder yield words in lexicographic order, PostOrder yields each
+// word after all words it prefixes, and LevelOrder yields shorter words first.
+//
+// Example:
+//
+//	it := trie.TraverseIter(tree.InOrder)
+//	for it.HasNext() {
+//		pair := it.Next().Unwrap()
+//		fmt.Println(pair.Key, pair.Value)
+//	}
+func (t *Trie[T]) TraverseIter(order TraversalOrder) collections.Iterator[collections.Pair[string, T]] {
+	it := &trieTraversalIterator[T]{order: order}
+	it.stack = append(it.stack, newTrieFrame(t.root, nil))
+	it.next = it.advance()
+	return it
+}
+
+// trieFrame tracks the progress of a traversal through a single trie node.
+type trieFrame[T any] struct {
+	node    *trieNode[T]
+	prefix  []rune
+	runes   []rune
+	child   int
+	visited bool
+}
+
+func newTrieFrame[T any](node *trieNode[T], prefix []rune) trieFrame[T] {
+	runes := node.children.Keys()
+	slices.Sort(runes)
+	return trieFrame[T]{node: node, prefix: prefix, runes: runes}
+}
+
+// trieTraversalIterator walks a Trie lazily. The stack is used as a LIFO for
+// the depth-first orders and as a FIFO queue for LevelOrder.
+type trieTraversalIterator[T any] struct {
+	order TraversalOrder
+	stack []trieFrame[T]
+	next  res.Option[collections.Pair[string, T]]
+}
+
+func (it *trieTraversalIterator[T]) HasNext() bool {
+	return it.next.IsSome()
+}
+
+func (it *trieTraversalIterator[T]) Next() res.Option[collections.Pair[string, T]] {
+	current := it.next
+	if current.IsSome() {
+		it.next = it.advance()
+	}
+	return current
+}
+
+func (it *trieTraversalIterator[T]) advance() res.Option[collections.Pair[string, T]] {
+	for len(it.stack) > 0 {
+		if it.order == LevelOrder {
+			front := it.stack[0]
+			it.stack = it.stack[1:]
+			for _, ch := range front.runes {
+				child, _ := front.node.children.Get(ch)
+				it.stack = append(it.stack, newTrieFrame(child, childPrefix(front.prefix, ch)))
+			}
+			if front.node.isEnd {
+				return res.Some(collections.Pair[string, T]{Key: string(front.prefix), Value: *front.node.value})
+			}
+			continue
+		}
+
+		top := &it.stack[len(it.stack)-1]
+		due := !top.visited && (it.order != PostOrder || top.child >= len(top.runes))
+		if due {
+			top.visited = true
+			if top.node.isEnd {
+				return res.Some(collections.Pair[string, T]{Key: string(top.prefix), Value: *top.node.value})
+			}
+			continue
+		}
+
+		if top.child < len(top.runes) {
+			ch := top.runes[top.child]
+			top.child++
+			child, _ := top.node.children.Get(ch)
+			it.stack = append(it.stack, newTrieFrame(child, childPrefix(top.prefix, ch)))
+			continue
+		}
+
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+	return res.None[collections.Pair[string, T]]()
+}
+
+// childPrefix returns prefix extended by ch without aliasing sibling prefixes.
+func childPrefix(prefix []rune, ch rune) []rune {
+	return append(prefix[:len(prefix):len(prefix)], ch)
+}
+
 // Root returns the root node of the trie.
 func (t *Trie[T]) Root() *Node[string, T] {
 	// Convert trieNode to Node
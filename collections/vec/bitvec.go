@@ -0,0 +1,273 @@
+package vec
+
+import (
+	"math/bits"
+
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+const wordBits = 64
+
+// BitVec is a growable vector of bits packed into 64-bit words.
+//
+// Besides single-bit access it supports rank and select queries, bulk
+// AND/OR/XOR against another BitVec, and iteration over the raw words or
+// the positions of set bits. Bits past Len() are always kept clear.
+//
+// Example:
+//
+//	bv := vec.NewBitVec(128)
+//	bv.Set(3)
+//	bv.Set(70)
+//	bv.Test(3)            // true
+//	bv.Rank(64)           // 1
+//	bv.Select(1).Unwrap() // 70
+type BitVec struct {
+	words []uint64
+	len   int
+}
+
+// NewBitVec creates a new BitVec of n bits, all clear.
+func NewBitVec(n int) *BitVec {
+	n = max(n, 0)
+	return &BitVec{
+		words: make([]uint64, wordsFor(n)),
+		len:   n,
+	}
+}
+
+// Len returns the number of bits in the BitVec.
+func (b *BitVec) Len() int {
+	return b.len
+}
+
+// IsEmpty returns true if the BitVec holds no bits.
+func (b *BitVec) IsEmpty() bool {
+	return b.len == 0
+}
+
+// Push appends a bit to the end of the BitVec.
+func (b *BitVec) Push(bit bool) {
+	b.Resize(b.len + 1)
+	if bit {
+		b.words[(b.len-1)/wordBits] |= 1 << ((b.len - 1) % wordBits)
+	}
+}
+
+// Set sets the bit at index i, growing the BitVec if i is past the end.
+// If the index is negative, it returns an error.
+func (b *BitVec) Set(i int) error {
+	if i < 0 {
+		return errors.New(errors.ErrOutOfBounds, "index out of bounds")
+	}
+	if i >= b.len {
+		b.Resize(i + 1)
+	}
+	b.words[i/wordBits] |= 1 << (i % wordBits)
+	return nil
+}
+
+// Clear clears the bit at index i.
+// If the index is out of bounds, it returns an error.
+func (b *BitVec) Clear(i int) error {
+	if i < 0 || i >= b.len {
+		return errors.New(errors.ErrOutOfBounds, "index out of bounds")
+	}
+	b.words[i/wordBits] &^= 1 << (i % wordBits)
+	return nil
+}
+
+// Test reports whether the bit at index i is set.
+// Bits outside the BitVec are reported as clear.
+func (b *BitVec) Test(i int) bool {
+	if i < 0 || i >= b.len {
+		return false
+	}
+	return b.words[i/wordBits]&(1<<(i%wordBits)) != 0
+}
+
+// Reset clears every bit without changing the length.
+func (b *BitVec) Reset() {
+	clear(b.words)
+}
+
+// Resize changes the length of the BitVec to n bits. New bits are clear,
+// and bits dropped when shrinking are cleared so they do not reappear.
+func (b *BitVec) Resize(n int) {
+	n = max(n, 0)
+	need := wordsFor(n)
+	switch {
+	case need > cap(b.words):
+		words := make([]uint64, need, max(need, 2*cap(b.words)))
+		copy(words, b.words)
+		b.words = words
+	case need > len(b.words):
+		b.words = b.words[:need]
+	default:
+		clear(b.words[need:])
+		b.words = b.words[:need]
+	}
+	b.len = n
+	b.trim()
+}
+
+// Count returns the number of set bits.
+func (b *BitVec) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Rank returns the number of set bits in the range [0, i).
+// Indices past the end count every set bit.
+func (b *BitVec) Rank(i int) int {
+	i = min(max(i, 0), b.len)
+	rank := 0
+	for _, w := range b.words[:i/wordBits] {
+		rank += bits.OnesCount64(w)
+	}
+	if rem := i % wordBits; rem != 0 {
+		rank += bits.OnesCount64(b.words[i/wordBits] & (1<<rem - 1))
+	}
+	return rank
+}
+
+// Select returns the index of the k-th set bit, counting from zero,
+// or None if fewer than k+1 bits are set. It is the inverse of Rank:
+// Rank(Select(k)) == k.
+func (b *BitVec) Select(k int) res.Option[int] {
+	if k < 0 {
+		return res.None[int]()
+	}
+	for wi, w := range b.words {
+		ones := bits.OnesCount64(w)
+		if k >= ones {
+			k -= ones
+			continue
+		}
+		for ; k > 0; k-- {
+			w &= w - 1 // clear the lowest set bit
+		}
+		return res.Some(wi*wordBits + bits.TrailingZeros64(w))
+	}
+	return res.None[int]()
+}
+
+// And sets b to the bitwise AND of b and other. Bits past the end of other
+// are treated as clear. The length of b is unchanged.
+func (b *BitVec) And(other *BitVec) {
+	for i := range b.words {
+		if i < len(other.words) {
+			b.words[i] &= other.words[i]
+		} else {
+			b.words[i] = 0
+		}
+	}
+	b.trim()
+}
+
+// Or sets b to the bitwise OR of b and other, growing b to other's length if it is shorter.
+func (b *BitVec) Or(other *BitVec) {
+	if other.len > b.len {
+		b.Resize(other.len)
+	}
+	for i, w := range other.words {
+		b.words[i] |= w
+	}
+}
+
+// Xor sets b to the bitwise XOR of b and other, growing b to other's length if it is shorter.
+func (b *BitVec) Xor(other *BitVec) {
+	if other.len > b.len {
+		b.Resize(other.len)
+	}
+	for i, w := range other.words {
+		b.words[i] ^= w
+	}
+}
+
+// Equal reports whether b and other have the same length and bits.
+func (b *BitVec) Equal(other *BitVec) bool {
+	if b.len != other.len {
+		return false
+	}
+	for i, w := range b.words {
+		if w != other.words[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of the BitVec.
+func (b *BitVec) Clone() *BitVec {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	return &BitVec{words: words, len: b.len}
+}
+
+// Words returns an iterator over the underlying 64-bit words, least
+// significant bit first. Bit i lives in word i/64 at position i%64.
+//
+// Example:
+//
+//	it := bv.Words()
+//	for it.HasNext() {
+//		w := it.Next().Unwrap()
+//		fmt.Printf("%064b\n", w)
+//	}
+func (b *BitVec) Words() collections.Iterator[uint64] {
+	return &vecIterator[uint64]{vec: &Vec[uint64]{data: b.words, len: len(b.words), cap: len(b.words)}, index: 0}
+}
+
+// Ones returns an iterator over the indices of the set bits in ascending
+// order. Empty words are skipped a whole word at a time.
+func (b *BitVec) Ones() collections.Iterator[int] {
+	it := &bitVecOnesIterator{bv: b, word: -1}
+	it.advance()
+	return it
+}
+
+type bitVecOnesIterator struct {
+	bv      *BitVec
+	word    int
+	current uint64
+}
+
+// advance moves to the next word with a set bit, if the current one is spent.
+func (it *bitVecOnesIterator) advance() {
+	for it.current == 0 && it.word+1 < len(it.bv.words) {
+		it.word++
+		it.current = it.bv.words[it.word]
+	}
+}
+
+func (it *bitVecOnesIterator) HasNext() bool {
+	return it.current != 0
+}
+
+func (it *bitVecOnesIterator) Next() res.Option[int] {
+	if !it.HasNext() {
+		return res.None[int]()
+	}
+	index := it.word*wordBits + bits.TrailingZeros64(it.current)
+	it.current &= it.current - 1
+	it.advance()
+	return res.Some(index)
+}
+
+// trim clears the unused bits of the last word.
+func (b *BitVec) trim() {
+	if rem := b.len % wordBits; rem != 0 {
+		b.words[len(b.words)-1] &= 1<<rem - 1
+	}
+}
+
+// wordsFor returns the number of words needed to hold n bits.
+func wordsFor(n int) int {
+	return (n + wordBits - 1) / wordBits
+}
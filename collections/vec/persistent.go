@@ -0,0 +1,277 @@
+package vec
+
+import (
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+const (
+	pvBits  = 5
+	pvWidth = 1 << pvBits
+	pvMask  = pvWidth - 1
+)
+
+// PersistentVec is an immutable vector implemented as a 32-way bit-partitioned
+// trie with a tail buffer, in the style of Clojure's and Scala's vectors.
+//
+// Every "modifying" operation returns a new PersistentVec and leaves the
+// receiver untouched. New versions share all unchanged nodes with the old one,
+// so Push, Pop, Set and Get run in O(log32 n) time, which is effectively
+// constant, and copy at most one path of 32-element nodes. Because no version
+// is ever mutated, snapshots can be shared freely across goroutines without
+// locking.
+//
+// Example:
+//
+//	v1 := vec.NewPersistentVec[int]().Push(1).Push(2)
+//	v2 := v1.Set(0, 10).Unwrap()
+//	v1.Get(0).Unwrap() // 1
+//	v2.Get(0).Unwrap() // 10
+type PersistentVec[T any] struct {
+	root  *pvNode[T]
+	tail  []T
+	len   int
+	shift uint
+}
+
+// pvNode is a node of the trie. Internal nodes hold children and leaves hold
+// values; both hold at most 32 entries.
+type pvNode[T any] struct {
+	children []*pvNode[T]
+	values   []T
+}
+
+// NewPersistentVec creates a new empty PersistentVec.
+func NewPersistentVec[T any]() *PersistentVec[T] {
+	return &PersistentVec[T]{root: &pvNode[T]{}, shift: pvBits}
+}
+
+// PersistentFromSlice creates a new PersistentVec containing the given items.
+func PersistentFromSlice[T any](items []T) *PersistentVec[T] {
+	pv := NewPersistentVec[T]()
+	for _, item := range items {
+		pv = pv.Push(item)
+	}
+	return pv
+}
+
+// Len returns the number of elements in the PersistentVec.
+func (pv *PersistentVec[T]) Len() int {
+	return pv.len
+}
+
+// IsEmpty returns true if the PersistentVec contains no elements.
+func (pv *PersistentVec[T]) IsEmpty() bool {
+	return pv.len == 0
+}
+
+// Get returns the element at the given index.
+// If the index is out of bounds, it returns an error.
+func (pv *PersistentVec[T]) Get(index int) res.Result[T] {
+	if index < 0 || index >= pv.len {
+		return res.Err[T](errors.New(errors.ErrOutOfBounds, "index out of bounds"))
+	}
+	return res.Ok(pv.leafFor(index)[index&pvMask])
+}
+
+// Push returns a new PersistentVec with item appended.
+func (pv *PersistentVec[T]) Push(item T) *PersistentVec[T] {
+	// Room in the tail: copy just the tail.
+	if pv.len-pv.tailOffset() < pvWidth {
+		tail := make([]T, len(pv.tail)+1, pvWidth)
+		copy(tail, pv.tail)
+		tail[len(pv.tail)] = item
+		return &PersistentVec[T]{root: pv.root, tail: tail, len: pv.len + 1, shift: pv.shift}
+	}
+
+	// The tail is full: move it into the trie and start a new one.
+	leaf := &pvNode[T]{values: pv.tail}
+	root, shift := pv.root, pv.shift
+	if pv.len>>pvBits > 1<<shift {
+		// The trie is full at this height, so grow a new root.
+		root = &pvNode[T]{children: []*pvNode[T]{pv.root, newPath(shift, leaf)}}
+		shift += pvBits
+	} else {
+		root = pv.pushTail(shift, pv.root, leaf)
+	}
+
+	tail := make([]T, 1, pvWidth)
+	tail[0] = item
+	return &PersistentVec[T]{root: root, tail: tail, len: pv.len + 1, shift: shift}
+}
+
+// Set returns a new PersistentVec with the element at the given index replaced.
+// If the index is out of bounds, it returns an error.
+func (pv *PersistentVec[T]) Set(index int, item T) res.Result[*PersistentVec[T]] {
+	if index < 0 || index >= pv.len {
+		return res.Err[*PersistentVec[T]](errors.New(errors.ErrOutOfBounds, "index out of bounds"))
+	}
+
+	if index >= pv.tailOffset() {
+		tail := make([]T, len(pv.tail), pvWidth)
+		copy(tail, pv.tail)
+		tail[index&pvMask] = item
+		return res.Ok(&PersistentVec[T]{root: pv.root, tail: tail, len: pv.len, shift: pv.shift})
+	}
+
+	root := assoc(pv.shift, pv.root, index, item)
+	return res.Ok(&PersistentVec[T]{root: root, tail: pv.tail, len: pv.len, shift: pv.shift})
+}
+
+// Pop returns a new PersistentVec with the last element removed.
+// Read the element with Get(Len()-1) first if it is needed.
+// If the PersistentVec is empty, it returns an error.
+func (pv *PersistentVec[T]) Pop() res.Result[*PersistentVec[T]] {
+	switch {
+	case pv.len == 0:
+		return res.Err[*PersistentVec[T]](errors.New(errors.ErrOutOfBounds, "cannot pop from an empty vector"))
+	case pv.len == 1:
+		return res.Ok(NewPersistentVec[T]())
+	case pv.len-pv.tailOffset() > 1:
+		n := len(pv.tail) - 1
+		return res.Ok(&PersistentVec[T]{root: pv.root, tail: pv.tail[:n:n], len: pv.len - 1, shift: pv.shift})
+	}
+
+	// The tail holds a single element: pull the last leaf out of the trie.
+	tail := pv.leafFor(pv.len - 2)
+	root := pv.popTail(pv.shift, pv.root)
+	shift := pv.shift
+	if root == nil {
+		root = &pvNode[T]{}
+	}
+	if shift > pvBits && len(root.children) == 1 {
+		root = root.children[0]
+		shift -= pvBits
+	}
+	return res.Ok(&PersistentVec[T]{root: root, tail: tail, len: pv.len - 1, shift: shift})
+}
+
+// Iterator returns an iterator over the elements of the PersistentVec.
+// The iterator walks a fixed snapshot, so later versions do not affect it.
+func (pv *PersistentVec[T]) Iterator() collections.Iterator[T] {
+	return &persistentVecIterator[T]{pv: pv}
+}
+
+// ToVec returns a new mutable Vec holding the elements of the PersistentVec.
+func (pv *PersistentVec[T]) ToVec() *Vec[T] {
+	data := make([]T, 0, pv.len)
+	for i := 0; i < pv.len; i += pvWidth {
+		data = append(data, pv.leafFor(i)...)
+	}
+	return &Vec[T]{data: data, len: pv.len, cap: pv.len}
+}
+
+// tailOffset returns the index of the first element stored in the tail.
+func (pv *PersistentVec[T]) tailOffset() int {
+	if pv.len < pvWidth {
+		return 0
+	}
+	return ((pv.len - 1) >> pvBits) << pvBits
+}
+
+// leafFor returns the 32-element leaf, or the tail, holding the given index.
+func (pv *PersistentVec[T]) leafFor(index int) []T {
+	if index >= pv.tailOffset() {
+		return pv.tail
+	}
+	n := pv.root
+	for level := pv.shift; level > 0; level -= pvBits {
+		n = n.children[(index>>level)&pvMask]
+	}
+	return n.values
+}
+
+// pushTail returns a copy of the path to the rightmost leaf position with
+// leaf inserted there.
+func (pv *PersistentVec[T]) pushTail(level uint, parent, leaf *pvNode[T]) *pvNode[T] {
+	sub := ((pv.len - 1) >> level) & pvMask
+	children := make([]*pvNode[T], len(parent.children), pvWidth)
+	copy(children, parent.children)
+
+	var child *pvNode[T]
+	switch {
+	case level == pvBits:
+		child = leaf
+	case sub < len(parent.children):
+		child = pv.pushTail(level-pvBits, parent.children[sub], leaf)
+	default:
+		child = newPath(level-pvBits, leaf)
+	}
+
+	if sub < len(children) {
+		children[sub] = child
+	} else {
+		children = append(children, child)
+	}
+	return &pvNode[T]{children: children}
+}
+
+// popTail returns a copy of the path to the rightmost leaf with that leaf
+// removed, or nil if the node becomes empty.
+func (pv *PersistentVec[T]) popTail(level uint, n *pvNode[T]) *pvNode[T] {
+	sub := ((pv.len - 2) >> level) & pvMask
+	if level > pvBits {
+		child := pv.popTail(level-pvBits, n.children[sub])
+		if child == nil && sub == 0 {
+			return nil
+		}
+		children := make([]*pvNode[T], sub, pvWidth)
+		copy(children, n.children[:sub])
+		if child != nil {
+			children = append(children, child)
+		}
+		return &pvNode[T]{children: children}
+	}
+	if sub == 0 {
+		return nil
+	}
+	children := make([]*pvNode[T], sub, pvWidth)
+	copy(children, n.children[:sub])
+	return &pvNode[T]{children: children}
+}
+
+// newPath wraps leaf in single-child internal nodes up to the given level.
+func newPath[T any](level uint, leaf *pvNode[T]) *pvNode[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &pvNode[T]{children: []*pvNode[T]{newPath(level-pvBits, leaf)}}
+}
+
+// assoc returns a copy of the path to index with the element replaced.
+func assoc[T any](level uint, n *pvNode[T], index int, item T) *pvNode[T] {
+	if level == 0 {
+		values := make([]T, len(n.values))
+		copy(values, n.values)
+		values[index&pvMask] = item
+		return &pvNode[T]{values: values}
+	}
+	children := make([]*pvNode[T], len(n.children))
+	copy(children, n.children)
+	sub := (index >> level) & pvMask
+	children[sub] = assoc(level-pvBits, n.children[sub], index, item)
+	return &pvNode[T]{children: children}
+}
+
+type persistentVecIterator[T any] struct {
+	pv    *PersistentVec[T]
+	leaf  []T
+	index int
+}
+
+func (it *persistentVecIterator[T]) HasNext() bool {
+	return it.index < it.pv.len
+}
+
+func (it *persistentVecIterator[T]) Next() res.Option[T] {
+	if !it.HasNext() {
+		return res.None[T]()
+	}
+	if it.index&pvMask == 0 {
+		it.leaf = it.pv.leafFor(it.index)
+	}
+	item := it.leaf[it.index&pvMask]
+	it.index++
+	return res.Some(item)
+}
@@ -0,0 +1,158 @@
+package vec
+
+import (
+	"slices"
+
+	"github.com/ielm/neostd/collections"
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// SparseVec is a fixed-length vector in which most positions are unset.
+// Only set positions are stored, in a map from index to value, so memory use
+// is proportional to the number of set positions rather than the length.
+// Unset positions read as the zero value of T.
+//
+// Example:
+//
+//	sv := vec.NewSparseVec[float64](1_000_000)
+//	sv.Set(42, 0.5)
+//	sv.Set(999_999, 1.5)
+//	sv.Density() // 2e-06
+//	it := sv.Iterator()
+//	for it.HasNext() {
+//		p := it.Next().Unwrap()
+//		fmt.Println(p.Key, p.Value) // 42 0.5, then 999999 1.5
+//	}
+type SparseVec[T any] struct {
+	values map[int]T
+	len    int
+}
+
+// NewSparseVec creates a new SparseVec of the given length with no positions set.
+func NewSparseVec[T any](length int) *SparseVec[T] {
+	return &SparseVec[T]{
+		values: make(map[int]T),
+		len:    max(length, 0),
+	}
+}
+
+// SparseFromVec creates a SparseVec with the same length as v, storing only
+// the elements for which isZero returns false.
+//
+// Example:
+//
+//	sv := vec.SparseFromVec(v, func(x float64) bool { return x == 0 })
+func SparseFromVec[T any](v *Vec[T], isZero func(T) bool) *SparseVec[T] {
+	sv := NewSparseVec[T](v.len)
+	for i, item := range v.data[:v.len] {
+		if !isZero(item) {
+			sv.values[i] = item
+		}
+	}
+	return sv
+}
+
+// Len returns the length of the SparseVec, counting unset positions.
+func (sv *SparseVec[T]) Len() int {
+	return sv.len
+}
+
+// Count returns the number of set positions.
+func (sv *SparseVec[T]) Count() int {
+	return len(sv.values)
+}
+
+// Density returns the fraction of positions that are set, between 0 and 1.
+// An empty SparseVec has a density of 0.
+func (sv *SparseVec[T]) Density() float64 {
+	if sv.len == 0 {
+		return 0
+	}
+	return float64(len(sv.values)) / float64(sv.len)
+}
+
+// Set stores item at the given index.
+// If the index is out of bounds, it returns an error.
+func (sv *SparseVec[T]) Set(index int, item T) error {
+	if index < 0 || index >= sv.len {
+		return errors.New(errors.ErrOutOfBounds, "index out of bounds")
+	}
+	sv.values[index] = item
+	return nil
+}
+
+// Get returns the element at the given index, or the zero value of T if the
+// position is unset. If the index is out of bounds, it returns an error.
+func (sv *SparseVec[T]) Get(index int) res.Result[T] {
+	if index < 0 || index >= sv.len {
+		return res.Err[T](errors.New(errors.ErrOutOfBounds, "index out of bounds"))
+	}
+	return res.Ok(sv.values[index])
+}
+
+// IsSet reports whether the position at the given index holds a value.
+func (sv *SparseVec[T]) IsSet(index int) bool {
+	_, ok := sv.values[index]
+	return ok
+}
+
+// Unset clears the position at the given index and returns the value it held.
+func (sv *SparseVec[T]) Unset(index int) res.Option[T] {
+	item, ok := sv.values[index]
+	if !ok {
+		return res.None[T]()
+	}
+	delete(sv.values, index)
+	return res.Some(item)
+}
+
+// Clear unsets every position without changing the length.
+func (sv *SparseVec[T]) Clear() {
+	clear(sv.values)
+}
+
+// Indices returns the set positions in ascending order.
+func (sv *SparseVec[T]) Indices() []int {
+	indices := make([]int, 0, len(sv.values))
+	for i := range sv.values {
+		indices = append(indices, i)
+	}
+	slices.Sort(indices)
+	return indices
+}
+
+// Iterator returns an iterator over the set positions and their values, in
+// ascending index order. Unset positions are skipped.
+func (sv *SparseVec[T]) Iterator() collections.Iterator[collections.Pair[int, T]] {
+	return &sparseVecIterator[T]{sv: sv, indices: sv.Indices()}
+}
+
+// ToVec returns a dense Vec of the same length, with unset positions holding
+// the zero value of T.
+func (sv *SparseVec[T]) ToVec() *Vec[T] {
+	data := make([]T, sv.len)
+	for i, item := range sv.values {
+		data[i] = item
+	}
+	return &Vec[T]{data: data, len: sv.len, cap: sv.len}
+}
+
+type sparseVecIterator[T any] struct {
+	sv      *SparseVec[T]
+	indices []int
+	index   int
+}
+
+func (it *sparseVecIterator[T]) HasNext() bool {
+	return it.index < len(it.indices)
+}
+
+func (it *sparseVecIterator[T]) Next() res.Option[collections.Pair[int, T]] {
+	if !it.HasNext() {
+		return res.None[collections.Pair[int, T]]()
+	}
+	i := it.indices[it.index]
+	it.index++
+	return res.Some(collections.Pair[int, T]{Key: i, Value: it.sv.values[i]})
+}
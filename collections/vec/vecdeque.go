@@ -1,6 +1,8 @@
 package vec
 
 import (
+	"sort"
+
 	"github.com/ielm/neostd/collections"
 	"github.com/ielm/neostd/collections/comp"
 	"github.com/ielm/neostd/errors"
@@ -253,6 +255,122 @@ func (vd *VecDeque[T]) MakeContiguous() []T {
 	return vd.buf[:vd.len]
 }
 
+// AsSlices returns the contents of the VecDeque as two slices, in order: the
+// elements from the head up to the end of the ring buffer, and the elements
+// that wrapped around to its start. The second slice is empty if the contents
+// do not wrap. Unlike MakeContiguous, nothing is moved or copied.
+//
+// The slices alias the internal buffer and are only valid until the next
+// modification of the VecDeque; they are intended for read-only bulk access.
+//
+// Example:
+//
+//	front, back := vd.AsSlices()
+//	for _, item := range front {
+//		process(item)
+//	}
+//	for _, item := range back {
+//		process(item)
+//	}
+func (vd *VecDeque[T]) AsSlices() ([]T, []T) {
+	if vd.head+vd.len <= vd.cap {
+		return vd.buf[vd.head : vd.head+vd.len], nil
+	}
+	return vd.buf[vd.head:], vd.buf[:vd.head+vd.len-vd.cap]
+}
+
+// Range returns an iterator over the elements in the range [start, end).
+// The iterator walks the ring buffer directly instead of recomputing the
+// wrapped position for every element like repeated Get calls do.
+// If the range is invalid, it returns an error.
+//
+// Example:
+//
+//	it := vd.Range(2, 5).Unwrap()
+//	for it.HasNext() {
+//		fmt.Println(it.Next().Unwrap())
+//	}
+func (vd *VecDeque[T]) Range(start, end int) res.Result[collections.Iterator[T]] {
+	if start < 0 || end > vd.len || start > end {
+		return res.Err[collections.Iterator[T]](errors.New(errors.ErrOutOfBounds, "range out of bounds"))
+	}
+	pos := vd.head + start
+	if pos >= vd.cap {
+		pos -= vd.cap
+	}
+	return res.Ok[collections.Iterator[T]](&vecDequeRangeIterator[T]{vd: vd, pos: pos, remaining: end - start})
+}
+
+// CopyRange returns a new slice holding a copy of the elements in the range
+// [start, end), copied with at most two bulk copies.
+// If the range is invalid, it returns nil.
+func (vd *VecDeque[T]) CopyRange(start, end int) []T {
+	if start < 0 || end > vd.len || start > end {
+		return nil
+	}
+	result := make([]T, end-start)
+	front, back := vd.AsSlices()
+	if start < len(front) {
+		n := copy(result, front[start:min(end, len(front))])
+		copy(result[n:], back)
+	} else {
+		copy(result, back[start-len(front):])
+	}
+	return result
+}
+
+// PartitionPoint returns the index of the first element for which pred
+// returns false, assuming the VecDeque is partitioned so that pred holds for a
+// prefix of its elements and not for the rest. It returns Len() if pred holds
+// for every element.
+//
+// Example:
+//
+//	// vd is [1, 2, 4, 8, 16]
+//	i := vd.PartitionPoint(func(x int) bool { return x < 5 }) // 3
+func (vd *VecDeque[T]) PartitionPoint(pred func(T) bool) int {
+	front, back := vd.AsSlices()
+	if len(back) > 0 && pred(front[len(front)-1]) {
+		return len(front) + sort.Search(len(back), func(i int) bool { return !pred(back[i]) })
+	}
+	return sort.Search(len(front), func(i int) bool { return !pred(front[i]) })
+}
+
+// BinarySearch searches the sorted VecDeque for item using its comparator,
+// handling a ring buffer that wraps around.
+// If the item is found, it returns the index of the first matching element.
+// Otherwise, it returns an ErrNotFound error carrying the index at which item
+// could be inserted to keep the VecDeque sorted; read it with InsertionPoint.
+//
+// Example:
+//
+//	// keep a sorted sliding window
+//	_, err := window.BinarySearch(x).ToTuple()
+//	if at, ok := vec.InsertionPoint(err); ok {
+//		fmt.Println("insert at", at)
+//	}
+func (vd *VecDeque[T]) BinarySearch(item T) res.Result[int] {
+	if vd.comparator == nil {
+		return res.Err[int](errors.New(errors.ErrInvalidArgument, "comparator not set"))
+	}
+	index := vd.PartitionPoint(func(elem T) bool {
+		return vd.comparator(elem, item) < 0
+	})
+	if index < vd.len && vd.comparator(vd.at(index), item) == 0 {
+		return res.Ok(index)
+	}
+	return res.Err[int](notFoundAt(index))
+}
+
+// at returns the element at the given logical index, which must be in bounds.
+func (vd *VecDeque[T]) at(index int) T {
+	i := vd.head + index
+	if i >= vd.cap {
+		i -= vd.cap
+	}
+	return vd.buf[i]
+}
+
 // Iterator returns an iterator for the VecDeque.
 func (vd *VecDeque[T]) Iterator() collections.Iterator[T] {
 	return &vecDequeIterator[T]{vd: vd, index: 0}
@@ -285,6 +403,29 @@ func (it *vecDequeIterator[T]) Next() res.Option[T] {
 	return res.Some(item.Unwrap())
 }
 
+type vecDequeRangeIterator[T any] struct {
+	vd        *VecDeque[T]
+	pos       int
+	remaining int
+}
+
+func (it *vecDequeRangeIterator[T]) HasNext() bool {
+	return it.remaining > 0
+}
+
+func (it *vecDequeRangeIterator[T]) Next() res.Option[T] {
+	if !it.HasNext() {
+		return res.None[T]()
+	}
+	item := it.vd.buf[it.pos]
+	it.pos++
+	if it.pos == it.vd.cap {
+		it.pos = 0
+	}
+	it.remaining--
+	return res.Some(item)
+}
+
 type vecDequeReverseIterator[T any] struct {
 	vd    *VecDeque[T]
 	index int
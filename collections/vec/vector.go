@@ -1,6 +1,8 @@
 package vec
 
 import (
+	"slices"
+
 	"github.com/ielm/neostd/collections"
 	"github.com/ielm/neostd/collections/comp"
 	"github.com/ielm/neostd/errors"
@@ -34,6 +36,32 @@ func VecWithCapacity[T any](capacity int, comparator comp.Comparator[T]) *Vec[T]
 	}
 }
 
+// FromSlice creates a new Vec containing a copy of the given items.
+//
+// Example:
+//
+//	v := vec.FromSlice([]int{1, 2, 3})
+func FromSlice[T any](items []T) *Vec[T] {
+	data := make([]T, len(items))
+	copy(data, items)
+	return &Vec[T]{
+		data: data,
+		len:  len(items),
+		cap:  len(items),
+	}
+}
+
+// FromIterator creates a new Vec containing the remaining elements of the iterator.
+//
+// Example:
+//
+//	v := vec.FromIterator(list.Iterator())
+func FromIterator[T any](it collections.Iterator[T]) *Vec[T] {
+	v := New[T]()
+	v.ExtendFrom(it)
+	return v
+}
+
 // Push appends an element to the back of the Vec.
 func (v *Vec[T]) Push(item T) {
 	if v.len == v.cap {
@@ -48,6 +76,32 @@ func (v *Vec[T]) Push(item T) {
 	v.len++
 }
 
+// Extend appends all items to the back of the Vec, growing it at most once.
+//
+// Example:
+//
+//	v.Extend(4, 5, 6)
+//	v.Extend(others...)
+func (v *Vec[T]) Extend(items ...T) {
+	if len(items) == 0 {
+		return
+	}
+	v.reserve(len(items))
+	v.data = append(v.data, items...)
+	v.len += len(items)
+}
+
+// ExtendFrom appends the remaining elements of the iterator to the back of the Vec.
+//
+// Example:
+//
+//	v.ExtendFrom(other.Iterator())
+func (v *Vec[T]) ExtendFrom(it collections.Iterator[T]) {
+	for it.HasNext() {
+		v.Push(it.Next().Unwrap())
+	}
+}
+
 // Pop removes and returns the last element from the Vec.
 // If the Vec is empty, it returns the zero value of T and false.
 func (v *Vec[T]) Pop() (T, bool) {
@@ -61,6 +115,49 @@ func (v *Vec[T]) Pop() (T, bool) {
 	return item, true
 }
 
+// Insert inserts an element at the given index, shifting all elements after it
+// to the right. Inserting at index Len() appends the element.
+// If the index is out of bounds, it returns an error.
+//
+// Example:
+//
+//	// v is [1, 3]
+//	err := v.Insert(1, 2) // v is now [1, 2, 3]
+func (v *Vec[T]) Insert(index int, item T) error {
+	if index < 0 || index > v.len {
+		return errors.New(errors.ErrOutOfBounds, "index out of bounds")
+	}
+	v.reserve(1)
+	v.data = v.data[:v.len+1]
+	copy(v.data[index+1:], v.data[index:v.len])
+	v.data[index] = item
+	v.len++
+	return nil
+}
+
+// InsertAll inserts all items at the given index, preserving their order and
+// shifting the elements after it to the right with a single copy.
+// If the index is out of bounds, it returns an error.
+//
+// Example:
+//
+//	// v is [1, 4]
+//	err := v.InsertAll(1, []int{2, 3}) // v is now [1, 2, 3, 4]
+func (v *Vec[T]) InsertAll(index int, items []T) error {
+	if index < 0 || index > v.len {
+		return errors.New(errors.ErrOutOfBounds, "index out of bounds")
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	v.reserve(len(items))
+	v.data = v.data[:v.len+len(items)]
+	copy(v.data[index+len(items):], v.data[index:v.len])
+	copy(v.data[index:], items)
+	v.len += len(items)
+	return nil
+}
+
 // Get returns the element at the given index.
 // If the index is out of bounds, it returns the zero value of T and an error.
 func (v *Vec[T]) Get(index int) res.Result[T] {
@@ -96,6 +193,32 @@ func (v *Vec[T]) Clear() {
 	v.len = 0
 }
 
+// Truncate shortens the Vec to its first n elements, zeroing the dropped
+// elements so anything they reference can be garbage collected.
+// It has no effect if n is greater than or equal to the length.
+// The capacity is unchanged; use ShrinkToFit to release memory.
+func (v *Vec[T]) Truncate(n int) {
+	if n >= v.len {
+		return
+	}
+	n = max(n, 0)
+	clear(v.data[n:v.len])
+	v.data = v.data[:n]
+	v.len = n
+}
+
+// ShrinkToFit reallocates the Vec so its capacity matches its length,
+// releasing the memory held by unused capacity.
+func (v *Vec[T]) ShrinkToFit() {
+	if v.cap == v.len {
+		return
+	}
+	data := make([]T, v.len)
+	copy(data, v.data[:v.len])
+	v.data = data
+	v.cap = v.len
+}
+
 // IsEmpty returns true if the Vec contains no elements.
 func (v *Vec[T]) IsEmpty() bool {
 	return v.len == 0
@@ -110,6 +233,15 @@ func (v *Vec[T]) grow() {
 	v.Grow(newCap)
 }
 
+// reserve ensures there is room for at least additional more elements,
+// at least doubling the capacity when it has to grow.
+func (v *Vec[T]) reserve(additional int) {
+	if v.len+additional <= v.cap {
+		return
+	}
+	v.Grow(max(v.cap*2, v.len+additional))
+}
+
 // Grow increases the capacity of the Vec to the specified size.
 func (v *Vec[T]) Grow(newCap int) {
 	if newCap > v.cap {
@@ -176,10 +308,299 @@ func (v *Vec[T]) RemoveAt(index int) res.Result[T] {
 	if index < 0 || index >= v.len {
 		return res.Err[T](errors.New(errors.ErrOutOfBounds, "index out of bounds"))
 	}
-	copy(v.data[index:], v.data[index+1:])
+	item := v.data[index]
+	copy(v.data[index:], v.data[index+1:v.len])
 	v.len--
 	v.data[v.len] = *new(T) // zero the last element
-	return res.Ok(v.data[index])
+	v.data = v.data[:v.len]
+	return res.Ok(item)
+}
+
+// SwapRemove removes the element at the given index in O(1) by moving the
+// last element into its place. Unlike RemoveAt, it does not preserve order.
+// If the index is out of bounds, it returns an error.
+//
+// Example:
+//
+//	// v is [1, 2, 3, 4]
+//	item := v.SwapRemove(1).Unwrap() // item is 2, v is now [1, 4, 3]
+func (v *Vec[T]) SwapRemove(index int) res.Result[T] {
+	if index < 0 || index >= v.len {
+		return res.Err[T](errors.New(errors.ErrOutOfBounds, "index out of bounds"))
+	}
+	item := v.data[index]
+	v.len--
+	v.data[index] = v.data[v.len]
+	v.data[v.len] = *new(T) // zero the last element
+	v.data = v.data[:v.len]
+	return res.Ok(item)
+}
+
+// Swap exchanges the elements at indices i and j.
+// If either index is out of bounds, it returns an error.
+func (v *Vec[T]) Swap(i, j int) error {
+	if i < 0 || i >= v.len || j < 0 || j >= v.len {
+		return errors.New(errors.ErrOutOfBounds, "index out of bounds")
+	}
+	v.data[i], v.data[j] = v.data[j], v.data[i]
+	return nil
+}
+
+// Retain keeps only the elements for which pred returns true, removing the
+// rest in place. The relative order of the retained elements is preserved and
+// the Vec is compacted in a single pass.
+//
+// Example:
+//
+//	// v is [1, 2, 3, 4]
+//	v.Retain(func(x int) bool { return x%2 == 0 }) // v is now [2, 4]
+func (v *Vec[T]) Retain(pred func(T) bool) {
+	kept := 0
+	for i := 0; i < v.len; i++ {
+		if pred(v.data[i]) {
+			v.data[kept] = v.data[i]
+			kept++
+		}
+	}
+	clear(v.data[kept:v.len])
+	v.data = v.data[:kept]
+	v.len = kept
+}
+
+// RemoveIf removes all elements for which pred returns true, preserving the
+// order of the remaining elements, and returns the number of elements removed.
+//
+// Example:
+//
+//	// v is [1, 2, 3, 4]
+//	removed := v.RemoveIf(func(x int) bool { return x > 2 }) // removed is 2, v is now [1, 2]
+func (v *Vec[T]) RemoveIf(pred func(T) bool) int {
+	before := v.len
+	v.Retain(func(item T) bool { return !pred(item) })
+	return before - v.len
+}
+
+// Dedup removes consecutive elements that are equal according to the
+// comparator, keeping the first of each run. On a sorted Vec this removes
+// all duplicates.
+//
+// Example:
+//
+//	// v is [1, 1, 2, 3, 3, 3, 1]
+//	v.Dedup() // v is now [1, 2, 3, 1]
+func (v *Vec[T]) Dedup() {
+	if v.comparator == nil {
+		panic("comparator not set for non-comparable type")
+	}
+	v.DedupBy(func(a, b T) bool {
+		return v.comparator(a, b) == 0
+	})
+}
+
+// DedupBy removes consecutive elements for which eq reports true, keeping the
+// first of each run. eq is called with the last retained element and the
+// element being considered.
+//
+// Example:
+//
+//	// v is ["a", "A", "b"]
+//	v.DedupBy(strings.EqualFold) // v is now ["a", "b"]
+func (v *Vec[T]) DedupBy(eq func(a, b T) bool) {
+	if v.len < 2 {
+		return
+	}
+	kept := 1
+	for i := 1; i < v.len; i++ {
+		if !eq(v.data[kept-1], v.data[i]) {
+			v.data[kept] = v.data[i]
+			kept++
+		}
+	}
+	clear(v.data[kept:v.len])
+	v.data = v.data[:kept]
+	v.len = kept
+}
+
+// Sort sorts the Vec in place using its comparator. The sort is not stable.
+// It returns an error if no comparator is set.
+//
+// Example:
+//
+//	v := vec.VecWithCapacity[int](8, comp.GenericComparator[int]())
+//	v.Extend(3, 1, 2)
+//	v.Sort() // v is now [1, 2, 3]
+func (v *Vec[T]) Sort() res.Result[collections.Sortable[T]] {
+	if v.comparator == nil {
+		return res.Err[collections.Sortable[T]](errors.New(errors.ErrInvalidArgument, "comparator not set"))
+	}
+	v.SortBy(v.comparator)
+	return res.Ok[collections.Sortable[T]](v)
+}
+
+// SortBy sorts the Vec in place using the given comparator. The sort is not stable.
+func (v *Vec[T]) SortBy(cmp comp.Comparator[T]) {
+	slices.SortFunc(v.data[:v.len], cmp)
+}
+
+// SortStable sorts the Vec in place using its comparator, keeping equal
+// elements in their original order.
+// It returns an error if no comparator is set.
+func (v *Vec[T]) SortStable() res.Result[collections.Sortable[T]] {
+	if v.comparator == nil {
+		return res.Err[collections.Sortable[T]](errors.New(errors.ErrInvalidArgument, "comparator not set"))
+	}
+	slices.SortStableFunc(v.data[:v.len], v.comparator)
+	return res.Ok[collections.Sortable[T]](v)
+}
+
+// SortWith sorts the Vec in place using the provided less function.
+func (v *Vec[T]) SortWith(less func(a, b T) bool) res.Result[collections.Sortable[T]] {
+	v.SortBy(lessToComparator(less))
+	return res.Ok[collections.Sortable[T]](v)
+}
+
+// Sorted returns a sorted copy of the Vec without modifying the original.
+// It returns an error if no comparator is set.
+func (v *Vec[T]) Sorted() res.Result[collections.Sortable[T]] {
+	return v.clone().Sort()
+}
+
+// SortedWith returns a copy of the Vec sorted with the provided less function,
+// without modifying the original.
+func (v *Vec[T]) SortedWith(less func(a, b T) bool) res.Result[collections.Sortable[T]] {
+	return v.clone().SortWith(less)
+}
+
+// insertIndexField is the field of a binary search's ErrNotFound error that
+// holds the insertion point.
+const insertIndexField = "index"
+
+// notFoundAt returns the ErrNotFound error of a binary search that found no
+// match, carrying the insertion point as its "index" field.
+func notFoundAt(index int) error {
+	return errors.New(errors.ErrNotFound, "item not found").WithField(insertIndexField, index)
+}
+
+// InsertionPoint returns the insertion point carried by the error of a failed
+// BinarySearch or BinarySearchBy, and false if err is not such an error.
+//
+// Example:
+//
+//	_, err := v.BinarySearch(4).ToTuple()
+//	if at, ok := vec.InsertionPoint(err); ok {
+//		v.Insert(at, 4)
+//	}
+func InsertionPoint(err error) (int, bool) {
+	var e *errors.Error
+	if !errors.As(err, &e) || e.Code != errors.ErrNotFound {
+		return 0, false
+	}
+	for _, f := range e.Fields() {
+		if index, ok := f.Value.(int); ok && f.Key == insertIndexField {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+// BinarySearch searches the sorted Vec for item using its comparator.
+// If the item is found, it returns the index of the first matching element.
+// Otherwise, it returns an ErrNotFound error whose "index" field holds the
+// index at which item could be inserted to keep the Vec sorted; read it with
+// InsertionPoint. The Vec must be sorted by its comparator, or the result is
+// unspecified.
+//
+// Example:
+//
+//	_, err := v.BinarySearch(4).ToTuple()
+//	if at, ok := vec.InsertionPoint(err); ok {
+//		v.Insert(at, 4)
+//	}
+func (v *Vec[T]) BinarySearch(item T) res.Result[int] {
+	if v.comparator == nil {
+		return res.Err[int](errors.New(errors.ErrInvalidArgument, "comparator not set"))
+	}
+	return v.BinarySearchBy(func(elem T) int {
+		return v.comparator(elem, item)
+	})
+}
+
+// BinarySearchBy searches the sorted Vec with a probe function, which reports
+// how an element compares to the target: negative if it orders before the
+// target, zero if it matches, and positive if it orders after it.
+// If a match is found, it returns the index of the first matching element.
+// Otherwise, it returns an ErrNotFound error carrying the index of the first
+// element that does not order before the target, as BinarySearch does.
+//
+// Example:
+//
+//	// find the first user with ID 42 in a Vec sorted by ID
+//	result := users.BinarySearchBy(func(u User) int { return u.ID - 42 })
+func (v *Vec[T]) BinarySearchBy(probe func(T) int) res.Result[int] {
+	lo, hi := 0, v.len
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if probe(v.data[mid]) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo < v.len && probe(v.data[lo]) == 0 {
+		return res.Ok(lo)
+	}
+	return res.Err[int](notFoundAt(lo))
+}
+
+// clone returns a shallow copy of the Vec that shares its comparator.
+func (v *Vec[T]) clone() *Vec[T] {
+	c := FromSlice(v.data[:v.len])
+	c.comparator = v.comparator
+	return c
+}
+
+// lessToComparator adapts a less function to a Comparator.
+func lessToComparator[T any](less func(a, b T) bool) comp.Comparator[T] {
+	return func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// Drain removes the elements in the range [start, end) and returns an iterator
+// over them. The removed elements are moved out up front and the tail of the
+// Vec is compacted exactly once, so the Vec may be used again immediately,
+// whether or not the iterator is consumed.
+// If the range is invalid, it returns an error.
+//
+// Example:
+//
+//	// v is [1, 2, 3, 4, 5]
+//	it := v.Drain(1, 3).Unwrap() // v is now [1, 4, 5]
+//	for it.HasNext() {
+//		fmt.Println(it.Next().Unwrap()) // 2, 3
+//	}
+func (v *Vec[T]) Drain(start, end int) res.Result[collections.Iterator[T]] {
+	if start < 0 || end > v.len || start > end {
+		return res.Err[collections.Iterator[T]](errors.New(errors.ErrOutOfBounds, "drain range out of bounds"))
+	}
+
+	drained := make([]T, end-start)
+	copy(drained, v.data[start:end])
+
+	copy(v.data[start:], v.data[end:v.len])
+	newLen := v.len - (end - start)
+	clear(v.data[newLen:v.len])
+	v.data = v.data[:newLen]
+	v.len = newLen
+
+	return res.Ok[collections.Iterator[T]](&vecIterator[T]{vec: FromSlice(drained), index: 0})
 }
 
 // Iterator returns an iterator for the Vec.
@@ -239,5 +660,69 @@ func (v *Vec[T]) Size() int {
 	return v.len
 }
 
-// Ensure Vec implements the Vector interface
+// Ensure Vec implements the Vector and Sortable interfaces
 var _ collections.Vector[any] = (*Vec[any])(nil)
+var _ collections.Sortable[any] = (*Vec[any])(nil)
+
+// Map returns a new Vec holding the result of applying f to each element of v, in order.
+//
+// Example:
+//
+//	lengths := vec.Map(words, func(s string) int { return len(s) })
+func Map[T any, U any](v *Vec[T], f func(T) U) *Vec[U] {
+	data := make([]U, v.len)
+	for i, item := range v.data[:v.len] {
+		data[i] = f(item)
+	}
+	return &Vec[U]{data: data, len: v.len, cap: v.len}
+}
+
+// Filter returns a new Vec holding the elements of v for which pred returns
+// true, in order. The new Vec shares v's comparator.
+//
+// Example:
+//
+//	evens := vec.Filter(v, func(x int) bool { return x%2 == 0 })
+func Filter[T any](v *Vec[T], pred func(T) bool) *Vec[T] {
+	result := VecWithCapacity[T](0, v.comparator)
+	for _, item := range v.data[:v.len] {
+		if pred(item) {
+			result.Push(item)
+		}
+	}
+	return result
+}
+
+// Reduce folds the elements of v from left to right into an accumulator,
+// starting from init.
+//
+// Example:
+//
+//	sum := vec.Reduce(v, 0, func(acc, x int) int { return acc + x })
+func Reduce[T any, U any](v *Vec[T], init U, f func(U, T) U) U {
+	acc := init
+	for _, item := range v.data[:v.len] {
+		acc = f(acc, item)
+	}
+	return acc
+}
+
+// Partition splits v into two new Vecs: the elements for which pred returns
+// true and those for which it returns false, each in their original order.
+// Both Vecs share v's comparator.
+//
+// Example:
+//
+//	small, large := vec.Partition(v, func(x int) bool { return x < 10 })
+func Partition[T any](v *Vec[T], pred func(T) bool) (*Vec[T], *Vec[T]) {
+	matched := VecWithCapacity[T](0, v.comparator)
+	rest := VecWithCapacity[T](0, v.comparator)
+	for _, item := range v.data[:v.len] {
+		if pred(item) {
+			matched.Push(item)
+		} else {
+			rest.Push(item)
+		}
+	}
+	return matched, rest
+}
@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Group collects multiple errors into one, such as the failures of a batch of
+// operations. It is safe for concurrent use, so goroutines can append to a shared Group,
+// and its zero value is ready to use.
+//
+// A Group implements Unwrap() []error, so the standard library's errors.Is and errors.As
+// match any of its errors.
+//
+// Example:
+//
+//	var g errors.Group
+//	for _, item := range items {
+//		if err := process(item); err != nil {
+//			g.Append(err)
+//		}
+//	}
+//	return g.Err()
+type Group struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewGroup returns a Group holding the non-nil errors among errs. With the Ok values
+// and errors that res.Partition returns, it gathers all the errors of a batch:
+//
+//	values, errs := res.Partition(results)
+//	if err := errors.NewGroup(errs...).Err(); err != nil {
+//		return err
+//	}
+func NewGroup(errs ...error) *Group {
+	g := &Group{}
+	g.Append(errs...)
+	return g
+}
+
+// Append adds errors to the group, skipping nil ones.
+func (g *Group) Append(errs ...error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, err := range errs {
+		if err != nil {
+			g.errs = append(g.errs, err)
+		}
+	}
+}
+
+// Len returns the number of errors in the group.
+func (g *Group) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.errs)
+}
+
+// Errors returns a copy of the errors in the group, in the order they were appended.
+func (g *Group) Errors() []error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]error(nil), g.errs...)
+}
+
+// Err returns the group as an error, or nil if it is empty. Return it rather than the
+// Group itself, which is never a nil error even when empty.
+func (g *Group) Err() error {
+	if g.Len() == 0 {
+		return nil
+	}
+	return g
+}
+
+// Error formats the group as a count followed by a bulleted list of its errors.
+func (g *Group) Error() string {
+	errs := g.Errors()
+	var sb strings.Builder
+	if len(errs) == 1 {
+		sb.WriteString("1 error occurred:")
+	} else {
+		fmt.Fprintf(&sb, "%d errors occurred:", len(errs))
+	}
+	for _, err := range errs {
+		// Indent the continuation lines of multi-line errors under their bullet
+		fmt.Fprintf(&sb, "\n\t* %s", strings.ReplaceAll(err.Error(), "\n", "\n\t  "))
+	}
+	return sb.String()
+}
+
+// Unwrap returns the errors in the group, for the standard library's errors.Is and
+// errors.As.
+func (g *Group) Unwrap() []error {
+	return g.Errors()
+}
@@ -0,0 +1,243 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// FirstCustomCode is the lowest ErrorCode available to applications. Codes below it are
+// reserved for this library.
+//
+// Example:
+//
+//	const (
+//		ErrQuotaExceeded = errors.FirstCustomCode + iota
+//		ErrAccountLocked
+//	)
+const FirstCustomCode ErrorCode = 1000
+
+// gRPC status codes, as numbered by google.golang.org/grpc/codes, so that this package
+// need not depend on gRPC.
+const (
+	grpcUnknown          uint32 = 2
+	grpcInvalidArgument  uint32 = 3
+	grpcDeadlineExceeded uint32 = 4
+	grpcNotFound         uint32 = 5
+	grpcOutOfRange       uint32 = 11
+	grpcUnimplemented    uint32 = 12
+	grpcInternal         uint32 = 13
+	grpcUnavailable      uint32 = 14
+)
+
+// CodeInfo describes an ErrorCode and how it maps onto transport status codes.
+type CodeInfo struct {
+	// Name is a short identifier for the code, such as "NotFound"
+	Name string
+	// Description explains when the code is used
+	Description string
+	// HTTPStatus is the HTTP status code for errors with this code, such as 404
+	HTTPStatus int
+	// GRPCCode is the gRPC status code for errors with this code, as the number of
+	// a google.golang.org/grpc/codes.Code
+	GRPCCode uint32
+	// Retryable reports whether an operation that failed with this code may succeed
+	// if it is tried again, as for a timeout or an unavailable dependency
+	Retryable bool
+}
+
+// registry holds the CodeInfo of the known codes and the override hooks.
+var registry = struct {
+	sync.RWMutex
+	codes    map[ErrorCode]CodeInfo
+	httpHook func(ErrorCode) (int, bool)
+	grpcHook func(ErrorCode) (uint32, bool)
+}{
+	codes: map[ErrorCode]CodeInfo{
+		ErrInvalidArgument: {
+			Name:        "InvalidArgument",
+			Description: "an argument is invalid",
+			HTTPStatus:  http.StatusBadRequest,
+			GRPCCode:    grpcInvalidArgument,
+		},
+		ErrConstructionFailed: {
+			Name:        "ConstructionFailed",
+			Description: "a value could not be constructed",
+			HTTPStatus:  http.StatusInternalServerError,
+			GRPCCode:    grpcInternal,
+		},
+		ErrOutOfBounds: {
+			Name:        "OutOfBounds",
+			Description: "an index or value is out of range",
+			HTTPStatus:  http.StatusBadRequest,
+			GRPCCode:    grpcOutOfRange,
+		},
+		ErrNotFound: {
+			Name:        "NotFound",
+			Description: "a requested item does not exist",
+			HTTPStatus:  http.StatusNotFound,
+			GRPCCode:    grpcNotFound,
+		},
+		ErrNotImplemented: {
+			Name:        "NotImplemented",
+			Description: "an operation is not supported",
+			HTTPStatus:  http.StatusNotImplemented,
+			GRPCCode:    grpcUnimplemented,
+		},
+		ErrUnwrapOnErr: {
+			Name:        "UnwrapOnErr",
+			Description: "a Result holding an error was unwrapped",
+			HTTPStatus:  http.StatusInternalServerError,
+			GRPCCode:    grpcInternal,
+		},
+		ErrInternal: {
+			Name:        "Internal",
+			Description: "an internal invariant was broken",
+			HTTPStatus:  http.StatusInternalServerError,
+			GRPCCode:    grpcInternal,
+		},
+		ErrUnavailable: {
+			Name:        "Unavailable",
+			Description: "a dependency is temporarily unavailable",
+			HTTPStatus:  http.StatusServiceUnavailable,
+			GRPCCode:    grpcUnavailable,
+			Retryable:   true,
+		},
+		ErrTimeout: {
+			Name:        "Timeout",
+			Description: "an operation did not complete in time",
+			HTTPStatus:  http.StatusGatewayTimeout,
+			GRPCCode:    grpcDeadlineExceeded,
+			Retryable:   true,
+		},
+	},
+}
+
+// Register records the metadata of an application-defined code, which must be at least
+// FirstCustomCode. It returns an error if the code is reserved or already registered.
+//
+// Example:
+//
+//	err := errors.Register(ErrQuotaExceeded, errors.CodeInfo{
+//		Name:       "QuotaExceeded",
+//		HTTPStatus: http.StatusTooManyRequests,
+//		GRPCCode:   8, // ResourceExhausted
+//	})
+func Register(code ErrorCode, info CodeInfo) error {
+	if code < FirstCustomCode {
+		return New(ErrInvalidArgument, fmt.Sprintf("error code %d is reserved; custom codes start at %d", code, FirstCustomCode))
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if existing, ok := registry.codes[code]; ok {
+		return New(ErrInvalidArgument, fmt.Sprintf("error code %d is already registered as %s", code, existing.Name))
+	}
+	registry.codes[code] = info
+	return nil
+}
+
+// Lookup returns the metadata of a code, and false if the code is not known.
+func Lookup(code ErrorCode) (CodeInfo, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	info, ok := registry.codes[code]
+	return info, ok
+}
+
+// String returns the code's registered name, or its number if it has none.
+func (c ErrorCode) String() string {
+	if info, ok := Lookup(c); ok && info.Name != "" {
+		return info.Name
+	}
+	return fmt.Sprintf("ErrorCode(%d)", int(c))
+}
+
+// SetHTTPStatusHook installs a function consulted by ToHTTPStatus before the registry,
+// to override the status of any code, including built-in ones. The hook returns false
+// to fall back to the registry. A nil hook removes the override.
+//
+// Example:
+//
+//	errors.SetHTTPStatusHook(func(code errors.ErrorCode) (int, bool) {
+//		if code == errors.ErrNotImplemented {
+//			return http.StatusMethodNotAllowed, true
+//		}
+//		return 0, false
+//	})
+func SetHTTPStatusHook(hook func(ErrorCode) (int, bool)) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.httpHook = hook
+}
+
+// SetGRPCCodeHook installs a function consulted by ToGRPCCode before the registry, as
+// SetHTTPStatusHook does for ToHTTPStatus.
+func SetGRPCCodeHook(hook func(ErrorCode) (uint32, bool)) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.grpcHook = hook
+}
+
+// ToHTTPStatus returns the HTTP status code for errors with the given code: the one the
+// hook set by SetHTTPStatusHook returns, or else the registered one. Unknown codes, and
+// registered codes without a status, map to 500 Internal Server Error.
+func ToHTTPStatus(code ErrorCode) int {
+	registry.RLock()
+	hook := registry.httpHook
+	info, ok := registry.codes[code]
+	registry.RUnlock()
+
+	if hook != nil {
+		if status, ok := hook(code); ok {
+			return status
+		}
+	}
+	if ok && info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// ToGRPCCode returns the gRPC status code for errors with the given code: the one the
+// hook set by SetGRPCCodeHook returns, or else the registered one. Unknown codes, and
+// registered codes without one, map to Unknown (2). The result converts directly to a
+// google.golang.org/grpc/codes.Code.
+//
+// Example:
+//
+//	return status.Error(codes.Code(errors.ToGRPCCode(e.Code)), e.Message)
+func ToGRPCCode(code ErrorCode) uint32 {
+	registry.RLock()
+	hook := registry.grpcHook
+	info, ok := registry.codes[code]
+	registry.RUnlock()
+
+	if hook != nil {
+		if grpcCode, ok := hook(code); ok {
+			return grpcCode
+		}
+	}
+	if ok && info.GRPCCode != 0 {
+		return info.GRPCCode
+	}
+	return grpcUnknown
+}
+
+// IsRetryable reports whether err, or an error it wraps, is an *Error whose code is
+// registered as retryable, such as ErrUnavailable or ErrTimeout. Errors of other types
+// are not retryable.
+//
+// Example:
+//
+//	if errors.IsRetryable(err) {
+//		time.Sleep(backoff)
+//		continue
+//	}
+func IsRetryable(err error) bool {
+	code, ok := CodeOf(err)
+	if !ok {
+		return false
+	}
+	info, ok := Lookup(code)
+	return ok && info.Retryable
+}
@@ -0,0 +1,38 @@
+package hash
+
+import (
+	"fmt"
+	"testing"
+)
+
+// BenchmarkAESHasher compares AESHasher with SipHash on inputs from hash map
+// keys up to the long inputs AESHasher is meant for.
+func BenchmarkAESHasher(b *testing.B) {
+	sip, err := NewSipHasher()
+	if err != nil {
+		b.Fatal(err)
+	}
+	aes, err := NewAESHasher()
+	if err != nil {
+		b.Fatal(err)
+	}
+	hashers := []struct {
+		name string
+		h    Hasher
+	}{
+		{"SipHash", sip},
+		{"AES", aes},
+	}
+	for _, size := range []int{16, 256, 4 << 10, 64 << 10} {
+		data := make([]byte, size)
+		for _, hasher := range hashers {
+			b.Run(fmt.Sprintf("%s/%d", hasher.name, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				for i := 0; i < b.N; i++ {
+					data[0] = byte(i)
+					Sum64(hasher.h, data)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package hash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+const (
+	blake3BlockSize  = 64
+	blake3ChunkSize  = 1024
+	blake3DigestSize = 32
+	blake3KeySize    = 32
+
+	blake3ChunkStart = 1 << 0
+	blake3ChunkEnd   = 1 << 1
+	blake3Parent     = 1 << 2
+	blake3Root       = 1 << 3
+	blake3KeyedHash  = 1 << 4
+)
+
+var blake3IV = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+}
+
+var blake3Permutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+
+// Blake3Hasher implements the BLAKE3 hash function, in its plain and keyed
+// modes. It is a cryptographic hash, and the recommended one for Merkle
+// trees built with merkle.NewBuilder and checked with merkle.Verify: unlike
+// SipHash, its outputs are collision resistant without a secret key.
+//
+// Sum returns a 32-byte digest; XOF reads an output of any length. Input is
+// hashed a 1 KiB chunk at a time as it is written, so only the chaining
+// values of the chunk tree, at most one per level, are kept in memory.
+//
+// Example:
+//
+//	h := hash.NewBlake3Hasher()
+//	h.Write([]byte("hello"))
+//	digest := h.Sum(nil)
+type Blake3Hasher struct {
+	BaseHasher
+	key   [8]uint32
+	flags uint32
+	chunk blake3ChunkState
+	stack [54][8]uint32 // Chaining values of completed subtrees, one per level
+	depth int
+}
+
+// NewBlake3Hasher creates a new BLAKE3 hasher
+func NewBlake3Hasher() *Blake3Hasher {
+	b := &Blake3Hasher{key: blake3IV}
+	b.Reset()
+	return b
+}
+
+// NewBlake3KeyedHasher creates a new BLAKE3 hasher in keyed mode, which
+// computes a message authentication code under the given 32-byte key.
+//
+// Example:
+//
+//	h, err := hash.NewBlake3KeyedHasher(key)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	h.Write(message)
+//	mac := h.Sum(nil)
+func NewBlake3KeyedHasher(key []byte) (*Blake3Hasher, error) {
+	if len(key) != blake3KeySize {
+		return nil, fmt.Errorf("invalid BLAKE3 key length: %d", len(key))
+	}
+	b := &Blake3Hasher{flags: blake3KeyedHash}
+	for i := range b.key {
+		b.key[i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	b.Reset()
+	return b, nil
+}
+
+// Write adds more data to the running hash
+func (b *Blake3Hasher) Write(p []byte) (n int, err error) {
+	n = len(p)
+	for len(p) > 0 {
+		if b.chunk.len() == blake3ChunkSize {
+			cv := b.chunk.output().chainingValue()
+			total := b.chunk.counter + 1
+			b.pushChunk(cv, total)
+			b.chunk = newBlake3ChunkState(b.key, total, b.flags)
+		}
+		take := min(blake3ChunkSize-b.chunk.len(), len(p))
+		b.chunk.update(p[:take])
+		p = p[take:]
+	}
+	return n, nil
+}
+
+// pushChunk adds the chaining value of a completed chunk to the stack,
+// first merging it with the completed subtrees it closes. total is the
+// number of chunks completed so far; each trailing zero bit in it marks a
+// subtree that the new chunk completes.
+func (b *Blake3Hasher) pushChunk(cv [8]uint32, total uint64) {
+	for total&1 == 0 {
+		b.depth--
+		cv = blake3ParentOutput(b.stack[b.depth], cv, b.key, b.flags).chainingValue()
+		total >>= 1
+	}
+	b.stack[b.depth] = cv
+	b.depth++
+}
+
+// root returns the output of the root node over the data written so far.
+// It leaves the hasher unchanged.
+func (b *Blake3Hasher) root() blake3Output {
+	out := b.chunk.output()
+	for i := b.depth - 1; i >= 0; i-- {
+		out = blake3ParentOutput(b.stack[i], out.chainingValue(), b.key, b.flags)
+	}
+	return out
+}
+
+// Sum appends the current 32-byte hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (b *Blake3Hasher) Sum(in []byte) []byte {
+	var digest [blake3DigestSize]byte
+	b.root().read(digest[:], 0)
+	return append(in, digest[:]...)
+}
+
+// Sum128 returns the first 16 bytes of the current hash as two 64-bit
+// halves, in little-endian order
+func (b *Blake3Hasher) Sum128() (h1, h2 uint64) {
+	var digest [16]byte
+	b.root().read(digest[:], 0)
+	return binary.LittleEndian.Uint64(digest[:]), binary.LittleEndian.Uint64(digest[8:])
+}
+
+// XOF returns a reader over the extendable output of the hash of the data
+// written so far. The first 32 bytes it reads are those Sum returns, and it
+// can read up to 2^64 bytes. The reader does not change as more data is
+// written to the hasher.
+//
+// Example:
+//
+//	h := hash.NewBlake3Hasher()
+//	h.Write(seed)
+//	stream := make([]byte, 1<<20)
+//	h.XOF().Read(stream)
+func (b *Blake3Hasher) XOF() *Blake3XOF {
+	return &Blake3XOF{out: b.root()}
+}
+
+// Reset resets the hash to its initial state, keeping its key
+func (b *Blake3Hasher) Reset() {
+	b.chunk = newBlake3ChunkState(b.key, 0, b.flags)
+	b.depth = 0
+}
+
+// Size returns the number of bytes Sum will return
+func (b *Blake3Hasher) Size() int {
+	return blake3DigestSize
+}
+
+// BlockSize returns the hash's underlying block size
+func (b *Blake3Hasher) BlockSize() int {
+	return blake3BlockSize
+}
+
+// HashKey computes the BLAKE3 hash of the given key
+func (b *Blake3Hasher) HashKey(key any) ([]byte, error) {
+	data, err := keyToBytes(key)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Reset()
+	b.Write(data)
+	return b.Sum(nil), nil
+}
+
+// Blake3XOF reads the extendable output of a BLAKE3 hash. It implements
+// io.Reader; reads never fail.
+type Blake3XOF struct {
+	out    blake3Output
+	offset uint64
+}
+
+// Read fills p with the next len(p) bytes of output
+func (x *Blake3XOF) Read(p []byte) (n int, err error) {
+	x.out.read(p, x.offset)
+	x.offset += uint64(len(p))
+	return len(p), nil
+}
+
+// blake3Output holds the inputs of a node's final compression, from which
+// either its chaining value or, for the root, any amount of output can be
+// computed.
+type blake3Output struct {
+	cv       [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (o blake3Output) chainingValue() [8]uint32 {
+	state := blake3Compress(&o.cv, &o.block, o.counter, o.blockLen, o.flags)
+	var cv [8]uint32
+	copy(cv[:], state[:8])
+	return cv
+}
+
+// read fills p with root output starting at the given byte offset. Each
+// 64-byte block of output is the root compressed with the block's index as
+// its counter.
+func (o blake3Output) read(p []byte, offset uint64) {
+	var buf [blake3BlockSize]byte
+	for len(p) > 0 {
+		state := blake3Compress(&o.cv, &o.block, offset/blake3BlockSize, o.blockLen, o.flags|blake3Root)
+		for i, w := range state {
+			binary.LittleEndian.PutUint32(buf[4*i:], w)
+		}
+		n := copy(p, buf[offset%blake3BlockSize:])
+		p = p[n:]
+		offset += uint64(n)
+	}
+}
+
+func blake3ParentOutput(left, right, key [8]uint32, flags uint32) blake3Output {
+	o := blake3Output{cv: key, blockLen: blake3BlockSize, flags: flags | blake3Parent}
+	copy(o.block[:8], left[:])
+	copy(o.block[8:], right[:])
+	return o
+}
+
+// blake3ChunkState hashes one chunk of up to 1 KiB.
+type blake3ChunkState struct {
+	cv         [8]uint32
+	counter    uint64 // Index of the chunk in the input
+	block      [blake3BlockSize]byte
+	blockLen   int
+	compressed int // Number of blocks compressed
+	flags      uint32
+}
+
+func newBlake3ChunkState(key [8]uint32, counter uint64, flags uint32) blake3ChunkState {
+	return blake3ChunkState{cv: key, counter: counter, flags: flags}
+}
+
+func (c *blake3ChunkState) len() int {
+	return blake3BlockSize*c.compressed + c.blockLen
+}
+
+func (c *blake3ChunkState) startFlag() uint32 {
+	if c.compressed == 0 {
+		return blake3ChunkStart
+	}
+	return 0
+}
+
+// update adds data to the chunk. The last block is only compressed once
+// more data arrives, since if it ends the chunk it needs the end flag.
+func (c *blake3ChunkState) update(p []byte) {
+	for len(p) > 0 {
+		if c.blockLen == blake3BlockSize {
+			words := blake3Words(&c.block)
+			state := blake3Compress(&c.cv, &words, c.counter, blake3BlockSize, c.flags|c.startFlag())
+			copy(c.cv[:], state[:8])
+			c.compressed++
+			c.block = [blake3BlockSize]byte{}
+			c.blockLen = 0
+		}
+		n := copy(c.block[c.blockLen:], p)
+		c.blockLen += n
+		p = p[n:]
+	}
+}
+
+func (c *blake3ChunkState) output() blake3Output {
+	return blake3Output{
+		cv:       c.cv,
+		block:    blake3Words(&c.block),
+		counter:  c.counter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.flags | c.startFlag() | blake3ChunkEnd,
+	}
+}
+
+func blake3Words(block *[blake3BlockSize]byte) [16]uint32 {
+	var words [16]uint32
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	return words
+}
+
+// blake3Compress is the BLAKE3 compression function.
+func blake3Compress(cv *[8]uint32, block *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	s := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		blake3IV[0], blake3IV[1], blake3IV[2], blake3IV[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	m := *block
+	for r := 0; r < 7; r++ {
+		blake3G(&s, 0, 4, 8, 12, m[0], m[1])
+		blake3G(&s, 1, 5, 9, 13, m[2], m[3])
+		blake3G(&s, 2, 6, 10, 14, m[4], m[5])
+		blake3G(&s, 3, 7, 11, 15, m[6], m[7])
+		blake3G(&s, 0, 5, 10, 15, m[8], m[9])
+		blake3G(&s, 1, 6, 11, 12, m[10], m[11])
+		blake3G(&s, 2, 7, 8, 13, m[12], m[13])
+		blake3G(&s, 3, 4, 9, 14, m[14], m[15])
+		if r < 6 {
+			var permuted [16]uint32
+			for i, j := range blake3Permutation {
+				permuted[i] = m[j]
+			}
+			m = permuted
+		}
+	}
+	for i := 0; i < 8; i++ {
+		s[i] ^= s[i+8]
+		s[i+8] ^= cv[i]
+	}
+	return s
+}
+
+func blake3G(s *[16]uint32, a, b, c, d int, mx, my uint32) {
+	s[a] += s[b] + mx
+	s[d] = bits.RotateLeft32(s[d]^s[a], -16)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -12)
+	s[a] += s[b] + my
+	s[d] = bits.RotateLeft32(s[d]^s[a], -8)
+	s[c] += s[d]
+	s[b] = bits.RotateLeft32(s[b]^s[c], -7)
+}
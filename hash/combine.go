@@ -0,0 +1,142 @@
+package hash
+
+import "encoding/binary"
+
+// Combine combines two hashes into one, for composite keys whose parts are
+// hashed separately. It depends on the order of its arguments, so that
+// Combine(a, b) and Combine(b, a) differ, and for a fixed h1 it maps
+// distinct h2 to distinct results.
+//
+// Example:
+//
+//	h := hash.Combine(hash.Combine(seed, userHash), orderHash)
+func Combine(h1, h2 uint64) uint64 {
+	return mix64(h1 ^ (h2 + 0x9E3779B97F4A7C15 + h1<<6 + h1>>2))
+}
+
+// OfFields hashes the fields of a composite key with h, and returns the
+// 64-bit hash as Sum64 would. Each field is written with its length in
+// front, so that ("ab", "c") and ("a", "bc") hash differently.
+//
+// Strings and byte slices are written as they are, Hashable values as
+// HashInto writes them, and other values with their canonical encoding, as
+// AppendKey describes: integers by value, so equal values of different
+// integer types hash alike, and floats by their bits, with 0 and -0 treated
+// as equal. It panics if a field cannot be encoded.
+//
+// Example:
+//
+//	h := hash.NewWyHasherWithSeed(0)
+//	keyHasher := hash.TypedHasherFunc[RouteKey](func(k RouteKey) uint64 {
+//		return hash.OfFields(h, k.Host, k.Port, k.Path)
+//	})
+//	routes := maps.NewHashMapWithTypedHasher[RouteKey, Route](routeKeyComparator, keyHasher).Unwrap()
+func OfFields(h Hasher, fields ...any) uint64 {
+	var buf []byte
+	for _, field := range fields {
+		start := len(buf)
+		buf = append(buf, 0, 0, 0, 0) // Length, filled in below
+		buf = appendField(buf, field)
+		binary.LittleEndian.PutUint32(buf[start:], uint32(len(buf)-start-4))
+	}
+	return Sum64(h, buf)
+}
+
+// appendField appends the encoding of a single field to buf: the bytes of
+// strings and byte slices, and the canonical encoding of other values.
+func appendField(buf []byte, field any) []byte {
+	switch f := field.(type) {
+	case string:
+		return append(buf, f...)
+	case []byte:
+		return append(buf, f...)
+	case Hashable:
+		var r recorder
+		f.HashInto(&r)
+		return append(buf, r.buf...)
+	}
+	buf, err := AppendKey(buf, field)
+	if err != nil {
+		panic(err)
+	}
+	return buf
+}
+
+// OrderedCombiner combines a sequence of hashes into one that depends on
+// their order, as for the elements of a list or the fields of a struct.
+// The zero value is ready to use.
+//
+// Example:
+//
+//	var c hash.OrderedCombiner
+//	for _, v := range path {
+//		c.Add(vertexHash(v))
+//	}
+//	h := c.Sum64()
+type OrderedCombiner struct {
+	h uint64
+	n uint64
+}
+
+// Add adds a hash to the end of the sequence
+func (c *OrderedCombiner) Add(h uint64) {
+	c.h = Combine(c.h, h)
+	c.n++
+}
+
+// Sum64 returns the combined hash of the sequence
+func (c *OrderedCombiner) Sum64() uint64 {
+	return Combine(c.h, c.n)
+}
+
+// Reset empties the sequence
+func (c *OrderedCombiner) Reset() {
+	*c = OrderedCombiner{}
+}
+
+// UnorderedCombiner combines a multiset of hashes into one that does not
+// depend on the order they are added in, as for the elements of a set or
+// the entries of a map. Each hash is mixed before it is summed, so that
+// unlike a plain XOR, duplicates do not cancel out and related hashes do
+// not combine into predictable values. The zero value is ready to use.
+//
+// Example:
+//
+//	var c hash.UnorderedCombiner
+//	for key, value := range m {
+//		c.Add(hash.Combine(keyHash(key), valueHash(value)))
+//	}
+//	h := c.Sum64()
+type UnorderedCombiner struct {
+	sum uint64
+	xor uint64
+	n   uint64
+}
+
+// Add adds a hash to the multiset
+func (c *UnorderedCombiner) Add(h uint64) {
+	m := mix64(h)
+	c.sum += m
+	c.xor ^= mix64(m)
+	c.n++
+}
+
+// Sum64 returns the combined hash of the multiset
+func (c *UnorderedCombiner) Sum64() uint64 {
+	return Combine(Combine(c.sum, c.xor), c.n)
+}
+
+// Reset empties the multiset
+func (c *UnorderedCombiner) Reset() {
+	*c = UnorderedCombiner{}
+}
+
+// mix64 is the SplitMix64 finalizer, a bijection on uint64 with good avalanche.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,84 @@
+package hash
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// CRC32C returns the CRC-32C (Castagnoli) checksum of data. It uses the
+// SSE4.2 CRC32 instruction on amd64 and the CRC instructions on arm64 when
+// the processor has them, detected at run time, and a portable table-driven
+// implementation otherwise.
+//
+// Example:
+//
+//	sum := hash.CRC32C(block)
+func CRC32C(data []byte) uint32 {
+	return crc32.Checksum(data, castagnoliTable)
+}
+
+// CRC32CHasher computes CRC-32C checksums, as CRC32C does, over data
+// written in pieces. A CRC detects accidental corruption, not tampering, and
+// its 32 bits spread keys poorly compared to a hash; use it for checksums in
+// storage and network pipelines. It implements hash.Hash32.
+//
+// Example:
+//
+//	h := hash.NewCRC32CHasher()
+//	io.Copy(h, file)
+//	sum := h.Sum32()
+type CRC32CHasher struct {
+	BaseHasher
+	crc uint32
+}
+
+// NewCRC32CHasher creates a new CRC32CHasher
+func NewCRC32CHasher() *CRC32CHasher {
+	return &CRC32CHasher{}
+}
+
+// Write adds more data to the running checksum
+func (c *CRC32CHasher) Write(p []byte) (n int, err error) {
+	c.crc = crc32.Update(c.crc, castagnoliTable, p)
+	return len(p), nil
+}
+
+// Sum appends the current checksum to b, in little-endian order, and returns
+// the resulting slice
+func (c *CRC32CHasher) Sum(b []byte) []byte {
+	return binary.LittleEndian.AppendUint32(b, c.crc)
+}
+
+// Sum32 returns the current checksum
+func (c *CRC32CHasher) Sum32() uint32 {
+	return c.crc
+}
+
+// Reset resets the checksum to its initial state
+func (c *CRC32CHasher) Reset() {
+	c.crc = 0
+}
+
+// Size returns the number of bytes Sum will return
+func (c *CRC32CHasher) Size() int {
+	return 4
+}
+
+// BlockSize returns the hash's underlying block size
+func (c *CRC32CHasher) BlockSize() int {
+	return 1
+}
+
+// HashKey computes the CRC-32C checksum of the given key
+func (c *CRC32CHasher) HashKey(key any) ([]byte, error) {
+	data, err := keyToBytes(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Reset()
+	c.Write(data)
+	return c.Sum(nil), nil
+}
@@ -0,0 +1,256 @@
+package hash
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/ielm/neostd/errors"
+)
+
+// AppendKey appends the canonical encoding of key to buf and returns the
+// extended slice. Keys that are equal encode identically, whatever their
+// memory layout, so the encoding can be hashed:
+//
+//   - integers are encoded by value as 8-byte little-endian words, so equal
+//     values of different integer types encode alike
+//   - floats are encoded by the bits of their float64 value, with -0 encoded
+//     as 0; complex numbers as their real and imaginary parts
+//   - booleans as a single byte
+//   - strings and byte slices as their length followed by their bytes
+//   - arrays and slices as their length followed by their elements
+//   - structs as their fields in order, including unexported ones
+//   - pointers and channels by address, so that they encode alike exactly
+//     when == finds them equal
+//   - interface values as the encoding of the value they hold; a nil
+//     interface value, including a nil key, as a single zero byte
+//   - Hashable values as the length and bytes of what HashInto writes
+//
+// Maps and functions cannot be compared and are not supported; AppendKey
+// returns an error for them. The encoder for each type is built once with
+// reflection and cached.
+//
+// Example:
+//
+//	type Edge struct {
+//		From, To string
+//		Weight   int
+//	}
+//
+//	buf, err := hash.AppendKey(nil, Edge{"a", "b", 3})
+func AppendKey(buf []byte, key any) ([]byte, error) {
+	// Fast paths for common keys
+	switch k := key.(type) {
+	case int:
+		return binary.LittleEndian.AppendUint64(buf, uint64(k)), nil
+	case int64:
+		return binary.LittleEndian.AppendUint64(buf, uint64(k)), nil
+	case uint64:
+		return binary.LittleEndian.AppendUint64(buf, k), nil
+	case string:
+		return appendBytes(buf, k), nil
+	case nil:
+		return append(buf, 0), nil
+	}
+	v := reflect.ValueOf(key)
+	entry := encoderFor(v.Type())
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	if entry.addressable {
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+	return entry.enc(buf, v), nil
+}
+
+// CheckKeyType returns an error if AppendKey cannot encode values of type
+// T, so that collections can reject a key type when they are created rather
+// than when a key is first hashed. For interface types it returns nil, as
+// the values they hold are only known at run time.
+//
+// Example:
+//
+//	if err := hash.CheckKeyType[K](); err != nil {
+//		return nil, err
+//	}
+func CheckKeyType[T any]() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	return encoderFor(t).err
+}
+
+// encoderFunc appends the encoding of v to buf.
+type encoderFunc func(buf []byte, v reflect.Value) []byte
+
+// encoderEntry is a cached encoder, or the reason there is none.
+type encoderEntry struct {
+	enc encoderFunc
+	err error
+	// addressable is set if the encoder must be given an addressable value,
+	// to call HashInto on unexported fields
+	addressable bool
+}
+
+// encoders caches an encoderEntry for each type.
+var encoders sync.Map
+
+var hashableType = reflect.TypeOf((*Hashable)(nil)).Elem()
+
+// encoderFor returns the cached encoder entry for values of type t,
+// building it on first use.
+func encoderFor(t reflect.Type) encoderEntry {
+	if e, ok := encoders.Load(t); ok {
+		return e.(encoderEntry)
+	}
+	b := encoderBuilder{building: make(map[reflect.Type]bool)}
+	enc, err := b.build(t, true)
+	e, _ := encoders.LoadOrStore(t, encoderEntry{enc: enc, err: err, addressable: b.addressable})
+	return e.(encoderEntry)
+}
+
+// encoderBuilder builds the encoder for a type and the types it contains.
+type encoderBuilder struct {
+	// building holds the types whose encoders are being built, to stop at
+	// recursive types such as a struct with a slice of itself
+	building    map[reflect.Type]bool
+	addressable bool
+}
+
+// build builds the encoder for values of type t; exported reports whether
+// the values are reachable without going through unexported fields.
+func (b *encoderBuilder) build(t reflect.Type, exported bool) (encoderFunc, error) {
+	if b.building[t] {
+		// Defer to the cached encoder, which exists by the time it is called
+		return func(buf []byte, v reflect.Value) []byte {
+			return encoderFor(t).enc(buf, v)
+		}, nil
+	}
+	b.building[t] = true
+	defer delete(b.building, t)
+
+	if t.Implements(hashableType) && t.Kind() != reflect.Interface {
+		if !exported {
+			b.addressable = true
+		}
+		return func(buf []byte, v reflect.Value) []byte {
+			if !v.CanInterface() {
+				// Values read through unexported fields cannot be converted
+				// to an interface; reach them through their address instead
+				v = reflect.NewAt(v.Type(), v.Addr().UnsafePointer()).Elem()
+			}
+			var r recorder
+			v.Interface().(Hashable).HashInto(&r)
+			return appendBytes(buf, r.buf)
+		}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(buf []byte, v reflect.Value) []byte {
+			if v.Bool() {
+				return append(buf, 1)
+			}
+			return append(buf, 0)
+		}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(buf []byte, v reflect.Value) []byte {
+			return binary.LittleEndian.AppendUint64(buf, uint64(v.Int()))
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(buf []byte, v reflect.Value) []byte {
+			return binary.LittleEndian.AppendUint64(buf, v.Uint())
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(buf []byte, v reflect.Value) []byte {
+			return appendFloat(buf, v.Float())
+		}, nil
+	case reflect.Complex64, reflect.Complex128:
+		return func(buf []byte, v reflect.Value) []byte {
+			c := v.Complex()
+			return appendFloat(appendFloat(buf, real(c)), imag(c))
+		}, nil
+	case reflect.String:
+		return func(buf []byte, v reflect.Value) []byte {
+			return appendBytes(buf, v.String())
+		}, nil
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return func(buf []byte, v reflect.Value) []byte {
+			return binary.LittleEndian.AppendUint64(buf, uint64(v.Pointer()))
+		}, nil
+	case reflect.Interface:
+		return func(buf []byte, v reflect.Value) []byte {
+			if v.IsNil() {
+				return append(buf, 0)
+			}
+			// Values of types that cannot be encoded, such as maps, cannot
+			// be compared either, so they are never valid keys
+			elem := v.Elem()
+			if entry := encoderFor(elem.Type()); entry.err == nil {
+				if entry.addressable {
+					addressable := reflect.New(elem.Type()).Elem()
+					addressable.Set(elem)
+					elem = addressable
+				}
+				return entry.enc(append(buf, 1), elem)
+			}
+			return append(buf, 1)
+		}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return func(buf []byte, v reflect.Value) []byte {
+				return appendBytes(buf, v.Bytes())
+			}, nil
+		}
+		elem, err := b.build(t.Elem(), exported)
+		if err != nil {
+			return nil, err
+		}
+		return func(buf []byte, v reflect.Value) []byte {
+			n := v.Len()
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(n))
+			for i := 0; i < n; i++ {
+				buf = elem(buf, v.Index(i))
+			}
+			return buf
+		}, nil
+	case reflect.Struct:
+		fields := make([]encoderFunc, t.NumField())
+		for i := range fields {
+			field := t.Field(i)
+			enc, err := b.build(field.Type, exported && field.IsExported())
+			if err != nil {
+				return nil, errors.NewWithCause(errors.ErrInvalidArgument,
+					"cannot encode field "+field.Name+" of "+t.String(), err)
+			}
+			fields[i] = enc
+		}
+		return func(buf []byte, v reflect.Value) []byte {
+			for i, enc := range fields {
+				buf = enc(buf, v.Field(i))
+			}
+			return buf
+		}, nil
+	default:
+		return nil, errors.New(errors.ErrInvalidArgument, "cannot encode keys of type "+t.String())
+	}
+}
+
+// appendBytes appends the length of data followed by data.
+func appendBytes[S string | []byte](buf []byte, data S) []byte {
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+// appendFloat appends the bits of f, with -0 written as 0 since they are
+// equal.
+func appendFloat(buf []byte, f float64) []byte {
+	if f == 0 {
+		f = 0
+	}
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(f))
+}
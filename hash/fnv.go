@@ -0,0 +1,112 @@
+package hash
+
+import "encoding/binary"
+
+const (
+	fnv32Offset = 2166136261
+	fnv32Prime  = 16777619
+	fnv64Offset = 14695981039346656037
+	fnv64Prime  = 1099511628211
+)
+
+// FNVHasher implements the 32-bit or 64-bit FNV-1a hash. It is not keyed and
+// offers no protection against chosen inputs, but it is tiny and fast for
+// short keys, and its Write and Sum64 methods do not allocate. It implements
+// hash.Hash32 and hash.Hash64 in both sizes.
+//
+// Example:
+//
+//	m := maps.NewHashMapWithHasher[string, int](comp.GenericComparator[string](), hash.NewFNV64Hasher()).Unwrap()
+type FNVHasher struct {
+	BaseHasher
+	size int // 4 or 8
+	h32  uint32
+	h64  uint64
+}
+
+// NewFNV32Hasher creates a new 32-bit FNV-1a hasher
+func NewFNV32Hasher() *FNVHasher {
+	f := &FNVHasher{size: 4}
+	f.Reset()
+	return f
+}
+
+// NewFNV64Hasher creates a new 64-bit FNV-1a hasher
+func NewFNV64Hasher() *FNVHasher {
+	f := &FNVHasher{size: 8}
+	f.Reset()
+	return f
+}
+
+// Write adds more data to the running hash
+func (f *FNVHasher) Write(p []byte) (n int, err error) {
+	if f.size == 4 {
+		h := f.h32
+		for _, c := range p {
+			h ^= uint32(c)
+			h *= fnv32Prime
+		}
+		f.h32 = h
+	} else {
+		h := f.h64
+		for _, c := range p {
+			h ^= uint64(c)
+			h *= fnv64Prime
+		}
+		f.h64 = h
+	}
+	return len(p), nil
+}
+
+// Sum appends the current hash to b, in big-endian order like hash/fnv, and
+// returns the resulting slice
+func (f *FNVHasher) Sum(b []byte) []byte {
+	if f.size == 4 {
+		return binary.BigEndian.AppendUint32(b, f.h32)
+	}
+	return binary.BigEndian.AppendUint64(b, f.h64)
+}
+
+// Sum32 returns the current hash, truncated to 32 bits for the 64-bit hasher
+func (f *FNVHasher) Sum32() uint32 {
+	if f.size == 4 {
+		return f.h32
+	}
+	return uint32(f.h64)
+}
+
+// Sum64 returns the current hash
+func (f *FNVHasher) Sum64() uint64 {
+	if f.size == 4 {
+		return uint64(f.h32)
+	}
+	return f.h64
+}
+
+// Reset resets the hash to its initial state
+func (f *FNVHasher) Reset() {
+	f.h32 = fnv32Offset
+	f.h64 = fnv64Offset
+}
+
+// Size returns the number of bytes Sum will return
+func (f *FNVHasher) Size() int {
+	return f.size
+}
+
+// BlockSize returns the hash's underlying block size
+func (f *FNVHasher) BlockSize() int {
+	return 1
+}
+
+// HashKey computes the FNV-1a hash of the given key
+func (f *FNVHasher) HashKey(key any) ([]byte, error) {
+	data, err := keyToBytes(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Reset()
+	f.Write(data)
+	return f.Sum(nil), nil
+}
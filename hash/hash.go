@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"hash"
 	"io"
-	"unsafe"
 )
 
 // Hasher is an interface that extends the standard hash.Hash interface
@@ -34,9 +33,47 @@ func (bh *BaseHasher) HashKey(key any) ([]byte, error) {
 	return bh.Sum(nil), nil
 }
 
-// HashBytesToUint64 converts a byte slice to uint64
+// HashBytesToUint64 converts a byte slice to uint64, reading its first 8
+// bytes in little-endian order. A hash shorter than 8 bytes, such as a
+// CRC-32C checksum, is widened with a multiplicative mix, so that its high
+// bits vary too: hash tables use them.
 func HashBytesToUint64(data []byte) uint64 {
-	return binary.LittleEndian.Uint64(data)
+	if len(data) >= 8 {
+		return binary.LittleEndian.Uint64(data)
+	}
+	var buf [8]byte
+	copy(buf[:], data)
+	return widen(binary.LittleEndian.Uint64(buf[:]))
+}
+
+// widen spreads the bits of a hash shorter than 64 bits over all 64.
+func widen(x uint64) uint64 {
+	x *= 0x9E3779B97F4A7C15
+	return x ^ x>>32
+}
+
+// Hashable is implemented by types that define their own hashing. HashKey,
+// and collections that hash keys through it such as HashMap, hash a
+// Hashable key from what its HashInto method writes, rather than from its
+// memory representation, which is only meaningful for types without
+// pointers, strings or padding.
+//
+// HashInto must write the same bytes for keys that are equal, and should
+// write a field's length before variable-length data, so that different
+// keys do not write the same bytes.
+//
+// Example:
+//
+//	type Point struct{ X, Y int32 }
+//
+//	func (p Point) HashInto(h hash.Hasher) {
+//		var buf [8]byte
+//		binary.LittleEndian.PutUint32(buf[0:], uint32(p.X))
+//		binary.LittleEndian.PutUint32(buf[4:], uint32(p.Y))
+//		h.Write(buf[:])
+//	}
+type Hashable interface {
+	HashInto(h Hasher)
 }
 
 // keyToBytes converts a key of any type to a byte slice
@@ -46,31 +83,19 @@ func keyToBytes(key any) ([]byte, error) {
 		return []byte(k), nil
 	case []byte:
 		return k, nil
+	case Hashable:
+		var r recorder
+		k.HashInto(&r)
+		return r.buf, nil
 	default:
 		return ToBinary(k)
 	}
 }
 
-// ToBinary converts an interface{} to a byte slice
+// ToBinary converts a value to a byte slice with its canonical encoding,
+// as AppendKey does
 func ToBinary(v interface{}) ([]byte, error) {
-	size := int(unsafe.Sizeof(v))
-	if size > 1<<30 {
-		return nil, fmt.Errorf("input size too large: %d bytes", size)
-	}
-	b := make([]byte, size)
-	switch size {
-	case 1:
-		b[0] = *(*uint8)(unsafe.Pointer(&v))
-	case 2:
-		binary.LittleEndian.PutUint16(b, *(*uint16)(unsafe.Pointer(&v)))
-	case 4:
-		binary.LittleEndian.PutUint32(b, *(*uint32)(unsafe.Pointer(&v)))
-	case 8:
-		binary.LittleEndian.PutUint64(b, *(*uint64)(unsafe.Pointer(&v)))
-	default:
-		copy(b, (*[1 << 30]byte)(unsafe.Pointer(&v))[:size])
-	}
-	return b, nil
+	return AppendKey(nil, v)
 }
 
 // GenerateRandomKeys creates two cryptographically secure random uint64 values
@@ -89,3 +114,34 @@ func Uint64ToBytes(value uint64) []byte {
 	binary.LittleEndian.PutUint64(b, value)
 	return b
 }
+
+// recorder is a Hasher that records the bytes written to it, so that the
+// output of a Hashable can be hashed like any other key.
+type recorder struct {
+	buf []byte
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.buf = append(r.buf, p...)
+	return len(p), nil
+}
+
+func (r *recorder) Sum(b []byte) []byte {
+	return append(b, r.buf...)
+}
+
+func (r *recorder) Reset() {
+	r.buf = r.buf[:0]
+}
+
+func (r *recorder) Size() int {
+	return len(r.buf)
+}
+
+func (r *recorder) BlockSize() int {
+	return 1
+}
+
+func (r *recorder) HashKey(key any) ([]byte, error) {
+	return keyToBytes(key)
+}
@@ -0,0 +1,37 @@
+package hash
+
+import "encoding/binary"
+
+// Hasher128 is implemented by hashers that produce at least 128 bits, as two
+// independent 64-bit halves. Filters use them to draw bucket indexes and
+// fingerprints from different halves, so that the two are uncorrelated.
+type Hasher128 interface {
+	Hasher
+	Sum128() (h1, h2 uint64)
+}
+
+// Sum128 resets h, hashes data with it and returns a 128-bit hash as two
+// 64-bit halves. Hashers that implement Hasher128 supply the halves
+// directly; for other hashers with at least 16 bytes of output they are the
+// first two little-endian words of Sum. For hashers with less output, ok is
+// false, h1 is the hash Sum64 returns and h2 is zero.
+//
+// Example:
+//
+//	h1, h2, ok := hash.Sum128(hasher, data)
+//	if !ok {
+//		h2 = deriveSecondHash(h1)
+//	}
+func Sum128(h Hasher, data []byte) (h1, h2 uint64, ok bool) {
+	h.Reset()
+	h.Write(data)
+	if h128, ok := h.(Hasher128); ok {
+		h1, h2 = h128.Sum128()
+		return h1, h2, true
+	}
+	if h.Size() < 16 {
+		return Sum64(h, data), 0, false
+	}
+	sum := h.Sum(nil)
+	return binary.LittleEndian.Uint64(sum), binary.LittleEndian.Uint64(sum[8:]), true
+}
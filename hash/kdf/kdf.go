@@ -0,0 +1,147 @@
+// Package kdf derives keys from secrets with HKDF (RFC 5869) and PBKDF2
+// (RFC 8018), so that components needing several independent keys, such as
+// a SipHasher per tenant, can derive them from one master secret.
+//
+// Both take the hash to build on as a constructor, as crypto/hmac does. The
+// package's BLAKE3 hasher is available as Blake3; standard library hashes
+// such as sha256.New can be passed directly.
+package kdf
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	stdhash "hash"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+)
+
+// Blake3 returns a new hash.Blake3Hasher, in the form Extract, Expand and
+// PBKDF2 accept.
+func Blake3() stdhash.Hash {
+	return hash.NewBlake3Hasher()
+}
+
+// Extract is the HKDF extract step: it concentrates the entropy of secret,
+// which need not be uniformly random, into a pseudorandom key of one hash
+// output. The salt should be random or at least application specific; an
+// empty salt is treated as a string of zeros of the hash's size.
+//
+// Example:
+//
+//	prk := kdf.Extract(kdf.Blake3, masterSecret, []byte("myapp-v1"))
+func Extract(newHash func() stdhash.Hash, secret, salt []byte) []byte {
+	if len(salt) == 0 {
+		salt = make([]byte, newHash().Size())
+	}
+	mac := hmac.New(newHash, salt)
+	mac.Write(secret)
+	return mac.Sum(nil)
+}
+
+// Expand is the HKDF expand step: it derives length bytes of key material
+// from a pseudorandom key, as returned by Extract, and info, which binds
+// the output to its purpose so that different infos yield independent
+// keys. It returns an error if length is negative or more than 255 times
+// the hash's size.
+//
+// Example:
+//
+//	key, err := kdf.Expand(kdf.Blake3, prk, []byte("tenant:42"), 16)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func Expand(newHash func() stdhash.Hash, prk, info []byte, length int) ([]byte, error) {
+	mac := hmac.New(newHash, prk)
+	if length < 0 || length > 255*mac.Size() {
+		return nil, errors.New(errors.ErrInvalidArgument, "invalid HKDF output length")
+	}
+
+	okm := make([]byte, 0, length)
+	var block []byte
+	for counter := byte(1); len(okm) < length; counter++ {
+		// T(i) = HMAC(prk, T(i-1) || info || i)
+		mac.Reset()
+		mac.Write(block)
+		mac.Write(info)
+		mac.Write([]byte{counter})
+		block = mac.Sum(block[:0])
+		okm = append(okm, block[:min(len(block), length-len(okm))]...)
+	}
+	return okm, nil
+}
+
+// HKDF derives length bytes of key material from secret, salt and info by
+// running Extract and then Expand.
+//
+// Example:
+//
+//	key, err := kdf.HKDF(kdf.Blake3, masterSecret, nil, []byte("session"), 32)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func HKDF(newHash func() stdhash.Hash, secret, salt, info []byte, length int) ([]byte, error) {
+	return Expand(newHash, Extract(newHash, secret, salt), info, length)
+}
+
+// PBKDF2 derives length bytes of key material from a password and salt with
+// PBKDF2, using HMAC with the given hash as its pseudorandom function. The
+// iteration count sets the cost of each guess for an attacker, and should be
+// as high as the application can afford; the salt should be random and
+// stored with the derived key. It returns an error if iterations or length
+// is not positive.
+//
+// Example:
+//
+//	salt := make([]byte, 16)
+//	if _, err := rand.Read(salt); err != nil {
+//		log.Fatal(err)
+//	}
+//	key, err := kdf.PBKDF2(sha256.New, []byte(password), salt, 600000, 32)
+func PBKDF2(newHash func() stdhash.Hash, password, salt []byte, iterations, length int) ([]byte, error) {
+	if iterations <= 0 {
+		return nil, errors.New(errors.ErrInvalidArgument, "PBKDF2 iterations must be positive")
+	}
+	if length <= 0 {
+		return nil, errors.New(errors.ErrInvalidArgument, "PBKDF2 output length must be positive")
+	}
+
+	mac := hmac.New(newHash, password)
+	dk := make([]byte, 0, length)
+	var u, t []byte
+	for block := uint32(1); len(dk) < length; block++ {
+		// T(i) = U(1) ^ ... ^ U(c), where U(1) = HMAC(password, salt || i)
+		// and U(j) = HMAC(password, U(j-1))
+		mac.Reset()
+		mac.Write(salt)
+		mac.Write(binary.BigEndian.AppendUint32(nil, block))
+		u = mac.Sum(u[:0])
+		t = append(t[:0], u...)
+		for n := 1; n < iterations; n++ {
+			mac.Reset()
+			mac.Write(u)
+			u = mac.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		dk = append(dk, t[:min(len(t), length-len(dk))]...)
+	}
+	return dk, nil
+}
+
+// DeriveSipHasher derives a SipHasher keyed by HKDF over BLAKE3 from a
+// master secret and a label, such as a tenant ID. Hashers derived from the
+// same secret and label agree across processes, while those derived with
+// different labels are keyed independently, so one tenant's keys reveal
+// nothing about another's. The secret should hold at least 16 random bytes.
+//
+// Example:
+//
+//	h := kdf.DeriveSipHasher(masterSecret, "tenant:"+tenantID)
+//	m := maps.NewHashMapWithHasher[string, int](comp.GenericComparator[string](), h).Unwrap()
+func DeriveSipHasher(secret []byte, label string) *hash.SipHasher {
+	// 16 bytes is well within Expand's limit, so it cannot fail
+	key, _ := HKDF(Blake3, secret, []byte("neostd sip hasher"), []byte(label), 16)
+	return hash.NewSipHasherFromKey([16]byte(key))
+}
@@ -0,0 +1,81 @@
+package quality
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+	"github.com/ielm/neostd/res"
+)
+
+// CollisionResult holds the outcome of a collision count.
+type CollisionResult struct {
+	// Keys is the number of distinct keys hashed
+	Keys int
+	// Bits is the number of low hash bits compared
+	Bits int
+	// Collisions is the number of keys whose hash equals that of an earlier
+	// key, that is Keys minus the number of distinct hashes
+	Collisions int
+	// Expected is the mean number of collisions for random hashes of the
+	// same width
+	Expected float64
+}
+
+// String returns a one-line summary of the result
+func (r CollisionResult) String() string {
+	return fmt.Sprintf("collisions: %d keys, %d bits, %d collisions, %.2f expected",
+		r.Keys, r.Bits, r.Collisions, r.Expected)
+}
+
+// Collisions counts the distinct keys whose hashes collide in their low
+// bits, between 1 and 64. Duplicate keys are counted once.
+//
+// Full 64-bit hashes of a modest corpus should never collide, so comparing
+// fewer bits, enough for the expected count to be a few dozen, is more
+// telling: a count far above Expected means the hasher maps distinct keys
+// together more often than chance. It returns an error if bits is out of
+// range.
+//
+// Example:
+//
+//	r := quality.Collisions(h, keys, 32).Unwrap()
+//	if float64(r.Collisions) > 2*r.Expected+10 {
+//		log.Printf("too many collisions: %v", r)
+//	}
+func Collisions(h hash.Hasher, keys [][]byte, bits int) res.Result[CollisionResult] {
+	if bits < 1 || bits > 64 {
+		return res.Err[CollisionResult](errors.New(errors.ErrInvalidArgument, "bits must be between 1 and 64"))
+	}
+	mask := ^uint64(0) >> (64 - bits)
+
+	seen := make(map[string]struct{}, len(keys))
+	hashes := make(map[uint64]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[string(key)]; ok {
+			continue
+		}
+		seen[string(key)] = struct{}{}
+		hashes[hash.Sum64(h, key)&mask] = struct{}{}
+	}
+
+	return res.Ok(CollisionResult{
+		Keys:       len(seen),
+		Bits:       bits,
+		Collisions: len(seen) - len(hashes),
+		Expected:   expectedCollisions(float64(len(seen)), float64(mask)+1),
+	})
+}
+
+// expectedCollisions returns the mean number of collisions among n random
+// values drawn from space equally likely ones: n minus the expected number
+// of distinct values, n - space*(1 - (1-1/space)^n).
+func expectedCollisions(n, space float64) float64 {
+	if n/space < 1e-6 {
+		// The exact form cancels catastrophically; the birthday
+		// approximation is accurate here
+		return n * (n - 1) / (2 * space)
+	}
+	return n + space*math.Expm1(n*math.Log1p(-1/space))
+}
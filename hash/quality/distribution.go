@@ -0,0 +1,138 @@
+package quality
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+	"github.com/ielm/neostd/res"
+)
+
+// DistributionResult holds the outcome of a bucket distribution test.
+type DistributionResult struct {
+	// Keys and Buckets are the number of keys hashed and buckets filled
+	Keys    int
+	Buckets int
+	// MaxLoad is the number of keys in the fullest bucket, and Empty the
+	// number of buckets without keys
+	MaxLoad int
+	Empty   int
+	// ChiSquare is Pearson's chi-square statistic of the bucket counts
+	// against a uniform distribution, with Buckets-1 degrees of freedom;
+	// for uniform hashes it is close to Buckets-1
+	ChiSquare float64
+	// PValue is the probability that uniform hashes would give a chi-square
+	// statistic at least as large. Very small values, such as below 0.001,
+	// mean the buckets are filled unevenly; values very close to 1 mean they
+	// are filled suspiciously evenly, as by a hash that counts up.
+	PValue float64
+}
+
+// String returns a one-line summary of the result
+func (r DistributionResult) String() string {
+	return fmt.Sprintf("distribution: %d keys in %d buckets, max load %d, %d empty, chi-square %.1f, p-value %.4f",
+		r.Keys, r.Buckets, r.MaxLoad, r.Empty, r.ChiSquare, r.PValue)
+}
+
+// Distribution hashes each key into one of the given number of buckets, by
+// the 64-bit hash modulo buckets, and tests the bucket counts for
+// uniformity with a chi-square test. With a power of two number of buckets
+// it reads the low bits of the hash, as HashMap does.
+//
+// The test needs about five keys per bucket or more to be reliable. It
+// returns an error if buckets is less than 2 or there are no keys.
+//
+// Example:
+//
+//	r := quality.Distribution(h, keys, 1<<10).Unwrap()
+//	if r.PValue < 0.001 {
+//		log.Printf("uneven distribution: %v", r)
+//	}
+func Distribution(h hash.Hasher, keys [][]byte, buckets int) res.Result[DistributionResult] {
+	if buckets < 2 {
+		return res.Err[DistributionResult](errors.New(errors.ErrInvalidArgument, "distribution test needs at least 2 buckets"))
+	}
+	if len(keys) == 0 {
+		return res.Err[DistributionResult](errors.New(errors.ErrInvalidArgument, "distribution test needs at least one key"))
+	}
+
+	counts := make([]int, buckets)
+	for _, key := range keys {
+		counts[hash.Sum64(h, key)%uint64(buckets)]++
+	}
+
+	result := DistributionResult{Keys: len(keys), Buckets: buckets}
+	expected := float64(len(keys)) / float64(buckets)
+	for _, n := range counts {
+		d := float64(n) - expected
+		result.ChiSquare += d * d / expected
+		result.MaxLoad = max(result.MaxLoad, n)
+		if n == 0 {
+			result.Empty++
+		}
+	}
+	result.PValue = chiSquareSurvival(result.ChiSquare, float64(buckets-1))
+	return res.Ok(result)
+}
+
+// chiSquareSurvival returns the probability that a chi-square variable with
+// df degrees of freedom exceeds x.
+func chiSquareSurvival(x, df float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return upperGamma(df/2, x/2)
+}
+
+// upperGamma returns the regularized upper incomplete gamma function Q(a, x),
+// by its series for x < a+1 and its continued fraction otherwise.
+func upperGamma(a, x float64) float64 {
+	const (
+		epsilon = 1e-14
+		maxIter = 10000
+		tiny    = 1e-300
+	)
+	lg, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lg)
+
+	if x < a+1 {
+		// P(a, x) = prefix * sum x^n / (a (a+1) ... (a+n))
+		term := 1 / a
+		sum := term
+		for n := 1; n < maxIter; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if term < sum*epsilon {
+				break
+			}
+		}
+		return max(0, 1-prefix*sum)
+	}
+
+	// Q(a, x) = prefix / (x + 1 - a - 1(1-a) / (x + 3 - a - ...)), evaluated
+	// with the modified Lentz method
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	f := d
+	for n := 1; n < maxIter; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		f *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return prefix * f
+}
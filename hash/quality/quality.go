@@ -0,0 +1,127 @@
+// Package quality measures the statistical quality of hashers, so that a
+// custom hash.Hasher can be checked before it is plugged into a HashMap or a
+// filter. Each test takes the keys to hash, which should resemble the keys
+// the hasher will see in production: a hasher that spreads random keys well
+// may still cluster sequential integers or strings with a common prefix.
+//
+// Three properties are measured:
+//
+//   - Avalanche: flipping any input bit should flip each output bit with
+//     probability 1/2.
+//   - Distribution: hashes reduced to a number of buckets, as a hash table
+//     does, should fill the buckets evenly.
+//   - Collisions: distinct keys should collide no more often than random
+//     hashes of the same width would.
+//
+// The tests hash through hash.Sum64, so they measure the 64-bit hashes that
+// HashMap and the filters use.
+//
+// Example:
+//
+//	h := NewMyHasher()
+//	a := quality.Avalanche(h, keys).Unwrap()
+//	if a.MaxBias > 0.05 {
+//		log.Printf("weak avalanche: %v", a)
+//	}
+//	d := quality.Distribution(h, keys, 1024).Unwrap()
+//	if d.PValue < 0.001 {
+//		log.Printf("uneven distribution: %v", d)
+//	}
+package quality
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+	"github.com/ielm/neostd/res"
+)
+
+// AvalancheResult holds the outcome of an avalanche test.
+type AvalancheResult struct {
+	// Flips is the number of single-bit input changes tested
+	Flips int
+	// FlipRate is the mean fraction of output bits that changed per flip;
+	// ideally 0.5
+	FlipRate float64
+	// Matrix holds, for each input bit position and each of the 64 output
+	// bits, the fraction of flips of that input bit that changed the output
+	// bit; ideally all 0.5. Input bit i is bit i%8 of byte i/8 of the key.
+	Matrix [][64]float64
+	// MaxBias is the largest distance from 0.5 in Matrix
+	MaxBias float64
+}
+
+// String returns a one-line summary of the result
+func (r AvalancheResult) String() string {
+	return fmt.Sprintf("avalanche: %d flips, flip rate %.4f, max bias %.4f", r.Flips, r.FlipRate, r.MaxBias)
+}
+
+// Avalanche flips each bit of each key in turn and records which bits of
+// the 64-bit hash change. A good hasher changes each output bit half of the
+// time whichever input bit is flipped.
+//
+// The bias of each matrix entry is subject to sampling noise of about
+// 0.5/sqrt(n), where n is the number of keys long enough to have that input
+// bit, so MaxBias is only meaningful with many keys: with 10000 keys of 16
+// bytes, a good hasher has a MaxBias of around 0.02. Empty keys are skipped.
+// It returns an error if no key is non-empty.
+//
+// Example:
+//
+//	r := quality.Avalanche(h, keys).Unwrap()
+//	fmt.Println(r)
+func Avalanche(h hash.Hasher, keys [][]byte) res.Result[AvalancheResult] {
+	var counts [][64]int // Changes of each output bit, by input bit
+	var samples []int    // Flips of each input bit
+	var flips, changed int
+	var buf []byte
+	for _, key := range keys {
+		if len(key) == 0 {
+			continue
+		}
+		base := hash.Sum64(h, key)
+		buf = append(buf[:0], key...)
+		for i := 0; i < len(key)*8; i++ {
+			if i == len(counts) {
+				counts = append(counts, [64]int{})
+				samples = append(samples, 0)
+			}
+			buf[i/8] ^= 1 << (i % 8)
+			diff := base ^ hash.Sum64(h, buf)
+			buf[i/8] ^= 1 << (i % 8)
+
+			changed += bits.OnesCount64(diff)
+			for ; diff != 0; diff &= diff - 1 {
+				counts[i][bits.TrailingZeros64(diff)]++
+			}
+			samples[i]++
+			flips++
+		}
+	}
+	if flips == 0 {
+		return res.Err[AvalancheResult](errors.New(errors.ErrInvalidArgument, "avalanche test needs at least one non-empty key"))
+	}
+
+	result := AvalancheResult{
+		Flips:    flips,
+		FlipRate: float64(changed) / float64(flips*64),
+		Matrix:   make([][64]float64, len(counts)),
+	}
+	for i, row := range counts {
+		for j, n := range row {
+			p := float64(n) / float64(samples[i])
+			result.Matrix[i][j] = p
+			result.MaxBias = max(result.MaxBias, abs(p-0.5))
+		}
+	}
+	return res.Ok(result)
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
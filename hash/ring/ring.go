@@ -0,0 +1,295 @@
+// Package ring implements consistent hashing, which assigns keys to a
+// changing set of members, such as cache servers or shards, so that adding
+// or removing a member only moves the keys it gains or loses.
+package ring
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/hash"
+	"github.com/ielm/neostd/res"
+)
+
+// RingConfig holds the configuration for a ConsistentHash
+type RingConfig struct {
+	replicas int
+	hasher   hash.Hasher
+}
+
+// RingOption configures a ConsistentHash
+type RingOption func(*RingConfig)
+
+func defaultRingConfig() RingConfig {
+	return RingConfig{
+		replicas: 160,
+		hasher:   hash.NewWyHasherWithSeed(0),
+	}
+}
+
+// WithReplicas sets the number of virtual nodes placed on the ring for a
+// member of weight 1, which must be positive; the default is 160. More
+// virtual nodes spread keys more evenly across members, at the cost of
+// memory and slower changes.
+func WithReplicas(n int) RingOption {
+	return func(c *RingConfig) {
+		c.replicas = n
+	}
+}
+
+// WithHasher sets the hasher that places virtual nodes and keys on the ring.
+// Every process sharing a ring must use a hasher that agrees on all inputs,
+// so the default is a WyHasher with a fixed seed rather than a random one.
+func WithHasher(h hash.Hasher) RingOption {
+	return func(c *RingConfig) {
+		c.hasher = h
+	}
+}
+
+// point is a virtual node: a position on the ring owned by a member.
+type point struct {
+	hash   uint64
+	member string
+}
+
+// ConsistentHash maps keys to members with consistent hashing.
+//
+// Each member owns a number of virtual nodes, proportional to its weight,
+// at pseudo-random positions on a ring of 64-bit hashes, and a key belongs
+// to the member owning the first virtual node at or after the key's hash.
+// Adding a member only takes over keys from its new neighbours on the ring,
+// and removing one only hands its keys to theirs, so about 1/n of the keys
+// move when the n-th member joins or leaves.
+//
+// A ConsistentHash is safe for concurrent use. Rings built with the same
+// members, weights and options assign keys identically.
+//
+// Example:
+//
+//	r, err := ring.NewConsistentHash()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	r.Add("cache-a")
+//	r.Add("cache-b")
+//	r.AddWeighted("cache-c", 2) // Twice as large as the others
+//	server := r.Get("user:42").Unwrap()
+type ConsistentHash struct {
+	mu       sync.RWMutex
+	config   RingConfig
+	points   []point // Sorted by hash, then member
+	weights  map[string]int
+	hasherMu sync.Mutex // Serializes use of the hasher by concurrent readers
+}
+
+// NewConsistentHash creates a new, empty ConsistentHash. It returns an error
+// if the options are invalid.
+func NewConsistentHash(options ...RingOption) (*ConsistentHash, error) {
+	config := defaultRingConfig()
+	for _, option := range options {
+		option(&config)
+	}
+	if config.replicas <= 0 {
+		return nil, errors.New(errors.ErrInvalidArgument, "replicas must be positive")
+	}
+	return &ConsistentHash{
+		config:  config,
+		weights: make(map[string]int),
+	}, nil
+}
+
+// Add adds a member of weight 1, or sets the weight of an existing member
+// to 1.
+func (r *ConsistentHash) Add(member string) {
+	r.AddWeighted(member, 1)
+}
+
+// AddWeighted adds a member with the given weight, which must be positive,
+// or changes the weight of an existing member. A member of weight w owns w
+// times as many virtual nodes, and so receives about w times as many keys,
+// as a member of weight 1. Changing a weight only moves the keys of the
+// virtual nodes added or removed.
+//
+// Example:
+//
+//	if err := r.AddWeighted("cache-c", 2); err != nil {
+//		log.Fatal(err)
+//	}
+func (r *ConsistentHash) AddWeighted(member string, weight int) error {
+	if weight <= 0 {
+		return errors.New(errors.ErrInvalidArgument, "weight must be positive")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.weights[member]
+	r.weights[member] = weight
+	switch have, want := old*r.config.replicas, weight*r.config.replicas; {
+	case want > have:
+		// Virtual node i of a member is always at the same position, so
+		// growing a member only adds nodes and shrinking it only removes them
+		added := make([]point, 0, want-have)
+		for i := have; i < want; i++ {
+			added = append(added, point{hash: r.nodeHash(member, i), member: member})
+		}
+		slices.SortFunc(added, comparePoints)
+		r.points = mergePoints(r.points, added)
+	case want < have:
+		keep := make(map[uint64]bool, want)
+		for i := 0; i < want; i++ {
+			keep[r.nodeHash(member, i)] = true
+		}
+		r.points = slices.DeleteFunc(r.points, func(p point) bool {
+			return p.member == member && !keep[p.hash]
+		})
+	}
+	return nil
+}
+
+// Remove removes a member and its virtual nodes from the ring. It returns
+// false if the member was not on the ring.
+func (r *ConsistentHash) Remove(member string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.weights[member]; !ok {
+		return false
+	}
+	delete(r.weights, member)
+	r.points = slices.DeleteFunc(r.points, func(p point) bool {
+		return p.member == member
+	})
+	return true
+}
+
+// Get returns the member that owns key, or None if the ring is empty.
+func (r *ConsistentHash) Get(key string) res.Option[string] {
+	h := r.hash([]byte(key))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 {
+		return res.None[string]()
+	}
+	return res.Some(r.points[r.search(h)].member)
+}
+
+// GetN returns up to n distinct members for key, in order of preference:
+// the owner first, followed by the next distinct members clockwise on the
+// ring. It suits replication, where a key is stored on n members and
+// survives the loss of all but one. If the ring has fewer than n members,
+// all of them are returned.
+//
+// Example:
+//
+//	replicas := r.GetN("user:42", 3)
+func (r *ConsistentHash) GetN(key string, n int) []string {
+	h := r.hash([]byte(key))
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n = min(n, len(r.weights))
+	if n <= 0 {
+		return nil
+	}
+	members := make([]string, 0, n)
+	seen := make(map[string]bool, n)
+	for i, start := 0, r.search(h); len(members) < n; i++ {
+		member := r.points[(start+i)%len(r.points)].member
+		if !seen[member] {
+			seen[member] = true
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// Members returns the members on the ring, in no particular order.
+func (r *ConsistentHash) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	members := make([]string, 0, len(r.weights))
+	for member := range r.weights {
+		members = append(members, member)
+	}
+	return members
+}
+
+// Weight returns the weight of a member, or 0 if it is not on the ring.
+func (r *ConsistentHash) Weight(member string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.weights[member]
+}
+
+// Size returns the number of members on the ring.
+func (r *ConsistentHash) Size() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.weights)
+}
+
+// search returns the index of the first virtual node at or after h,
+// wrapping around to the first one; the caller must hold the lock and the
+// ring must not be empty.
+func (r *ConsistentHash) search(h uint64) int {
+	i, _ := slices.BinarySearchFunc(r.points, h, func(p point, h uint64) int {
+		switch {
+		case p.hash < h:
+			return -1
+		case p.hash > h:
+			return 1
+		}
+		return 0
+	})
+	if i == len(r.points) {
+		return 0
+	}
+	return i
+}
+
+// nodeHash returns the position of a member's i-th virtual node.
+func (r *ConsistentHash) nodeHash(member string, i int) uint64 {
+	buf := make([]byte, 0, len(member)+8)
+	buf = append(buf, member...)
+	buf = append(buf, '#')
+	buf = strconv.AppendInt(buf, int64(i), 10)
+	return r.hash(buf)
+}
+
+func (r *ConsistentHash) hash(data []byte) uint64 {
+	r.hasherMu.Lock()
+	defer r.hasherMu.Unlock()
+	return hash.Sum64(r.config.hasher, data)
+}
+
+// comparePoints orders virtual nodes by hash, breaking ties between members
+// by name so that the ring does not depend on the order members were added.
+func comparePoints(a, b point) int {
+	switch {
+	case a.hash < b.hash:
+		return -1
+	case a.hash > b.hash:
+		return 1
+	case a.member < b.member:
+		return -1
+	case a.member > b.member:
+		return 1
+	}
+	return 0
+}
+
+// mergePoints merges two sorted slices of virtual nodes.
+func mergePoints(a, b []point) []point {
+	merged := make([]point, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if comparePoints(a[0], b[0]) <= 0 {
+			merged = append(merged, a[0])
+			a = a[1:]
+		} else {
+			merged = append(merged, b[0])
+			b = b[1:]
+		}
+	}
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
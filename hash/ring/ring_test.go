@@ -0,0 +1,26 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/ielm/neostd/errors"
+)
+
+func TestNewConsistentHashRejectsNonPositiveReplicas(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		_, err := NewConsistentHash(WithReplicas(n))
+		if code, ok := errors.CodeOf(err); !ok || code != errors.ErrInvalidArgument {
+			t.Errorf("WithReplicas(%d): got error %v, want ErrInvalidArgument", n, err)
+		}
+	}
+
+	r, err := NewConsistentHash(WithReplicas(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Add("a")
+	r.Add("b")
+	if got := r.GetN("key", 2); len(got) != 2 {
+		t.Errorf("GetN with one replica: got %v, want 2 members", got)
+	}
+}
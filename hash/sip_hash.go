@@ -6,36 +6,189 @@ import (
 	"math/bits"
 )
 
-// SipHasher implements the SipHash 1-3 algorithm
+// SipHasher implements the SipHash-c-d family of algorithms, with c
+// compression rounds per 8-byte block and d finalization rounds. It uses
+// SipHash-1-3 by default, which is fast and sufficient for hash tables;
+// SipHash-2-4 is the conservative choice of the original design.
+//
+// The zero value, as decoded by UnmarshalBinary, uses SipHash-1-3.
 type SipHasher struct {
 	BaseHasher
 	k0, k1 uint64
+	c, d   int    // Round counts; zero means the default 1-3
+	buf    []byte // Data written since the last Reset
 }
 
-// NewSipHasher creates a new SipHasher with random keys
+// NewSipHasher creates a new SipHash-1-3 hasher with random keys
 func NewSipHasher() (*SipHasher, error) {
+	return NewSipHasherRounds(1, 3)
+}
+
+// NewSipHasher24 creates a new SipHash-2-4 hasher with random keys
+func NewSipHasher24() (*SipHasher, error) {
+	return NewSipHasherRounds(2, 4)
+}
+
+// NewSipHasherRounds creates a new SipHash-c-d hasher with random keys,
+// using c compression rounds and d finalization rounds, each between 1 and
+// 255. More rounds give a larger security margin at the cost of speed.
+//
+// Example:
+//
+//	h, err := hash.NewSipHasherRounds(4, 8)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func NewSipHasherRounds(c, d int) (*SipHasher, error) {
+	if c < 1 || c > 255 || d < 1 || d > 255 {
+		return nil, fmt.Errorf("invalid SipHash round counts: %d-%d", c, d)
+	}
 	k0, k1, err := GenerateRandomKeys()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate random keys: %w", err)
 	}
-	return &SipHasher{k0: k0, k1: k1}, nil
+	return &SipHasher{k0: k0, k1: k1, c: c, d: d}, nil
+}
+
+// NewSipHasherWithKeys creates a new SipHash-1-3 hasher with the given
+// keys. Unlike NewSipHasher, its hashes are reproducible across processes,
+// so structures that persist hashes can be rebuilt or queried elsewhere.
+// Keep the keys secret if inputs may be chosen by an attacker.
+//
+// Example:
+//
+//	h := hash.NewSipHasherWithKeys(config.HashKey0, config.HashKey1)
+func NewSipHasherWithKeys(k0, k1 uint64) *SipHasher {
+	return &SipHasher{k0: k0, k1: k1}
+}
+
+// NewSipHasherFromKey creates a new SipHash-1-3 hasher with the given
+// 128-bit key, read as two little-endian words as in the SipHash
+// specification.
+//
+// Example:
+//
+//	var key [16]byte
+//	copy(key[:], secret)
+//	h := hash.NewSipHasherFromKey(key)
+func NewSipHasherFromKey(key [16]byte) *SipHasher {
+	return NewSipHasherWithKeys(binary.LittleEndian.Uint64(key[0:8]), binary.LittleEndian.Uint64(key[8:16]))
+}
+
+// WithRounds returns a copy of the hasher, with the same keys and a fresh
+// state, that uses c compression rounds and d finalization rounds. It
+// combines with the deterministic constructors, as in
+// NewSipHasherFromKey(key).WithRounds(2, 4) for a keyed SipHash-2-4.
+func (s *SipHasher) WithRounds(c, d int) (*SipHasher, error) {
+	if c < 1 || c > 255 || d < 1 || d > 255 {
+		return nil, fmt.Errorf("invalid SipHash round counts: %d-%d", c, d)
+	}
+	return &SipHasher{k0: s.k0, k1: s.k1, c: c, d: d}, nil
+}
+
+// Key returns the hasher's 128-bit key, in the form NewSipHasherFromKey takes
+func (s *SipHasher) Key() [16]byte {
+	var key [16]byte
+	binary.LittleEndian.PutUint64(key[0:8], s.k0)
+	binary.LittleEndian.PutUint64(key[8:16], s.k1)
+	return key
+}
+
+// Rounds returns the number of compression and finalization rounds
+func (s *SipHasher) Rounds() (c, d int) {
+	if s.c == 0 {
+		return 1, 3
+	}
+	return s.c, s.d
+}
+
+// Clone returns a new SipHasher with the same keys and rounds and a fresh
+// state. This lets concurrent workers produce identical hashes without
+// sharing state.
+func (s *SipHasher) Clone() *SipHasher {
+	return &SipHasher{k0: s.k0, k1: s.k1, c: s.c, d: s.d}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// It encodes the hasher's 128-bit key, followed by its round counts unless
+// they are the default 1-3, so that structures which persist hashes, such
+// as filters, can restore a hasher that reproduces them. The running state
+// is not included.
+//
+// The key is what keeps SipHash outputs unpredictable; store the encoding
+// with the same care as the key itself.
+func (s *SipHasher) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 16, 18)
+	binary.LittleEndian.PutUint64(data[0:8], s.k0)
+	binary.LittleEndian.PutUint64(data[8:16], s.k1)
+	if c, d := s.Rounds(); c != 1 || d != 3 {
+		data = append(data, byte(c), byte(d))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It restores a key encoded by MarshalBinary.
+//
+// Example:
+//
+//	var h hash.SipHasher
+//	if err := h.UnmarshalBinary(key); err != nil {
+//		log.Fatal(err)
+//	}
+func (s *SipHasher) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 && len(data) != 18 {
+		return fmt.Errorf("invalid SipHasher key length: %d", len(data))
+	}
+	s.k0 = binary.LittleEndian.Uint64(data[0:8])
+	s.k1 = binary.LittleEndian.Uint64(data[8:16])
+	s.c, s.d = 0, 0
+	if len(data) == 18 {
+		if data[16] == 0 || data[17] == 0 {
+			return fmt.Errorf("invalid SipHash round counts: %d-%d", data[16], data[17])
+		}
+		s.c, s.d = int(data[16]), int(data[17])
+	}
+	return nil
 }
 
 // Write adds more data to the running hash
 func (s *SipHasher) Write(p []byte) (n int, err error) {
-	s.BaseHasher.Write(p)
+	s.buf = append(s.buf, p...)
 	return len(p), nil
 }
 
 // Sum appends the current hash to b and returns the resulting slice
 func (s *SipHasher) Sum(b []byte) []byte {
-	h := s.sipHash13(s.BaseHasher.Sum(nil))
+	h := s.sipHash(s.buf)
 	return append(b, Uint64ToBytes(h)...)
 }
 
+// Sum128 returns the SipHash-128 of the data written, as two 64-bit halves.
+// It is a different function from the 64-bit SipHash that Sum returns, so
+// h1 does not equal the value of Sum.
+func (s *SipHasher) Sum128() (h1, h2 uint64) {
+	return s.sipHash128(s.buf)
+}
+
 // Reset resets the hash to its initial state
 func (s *SipHasher) Reset() {
-	s.BaseHasher.Reset()
+	s.buf = s.buf[:0]
+}
+
+// HashKey computes the SipHash of the given key
+func (s *SipHasher) HashKey(key any) ([]byte, error) {
+	data, err := keyToBytes(key)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Reset()
+	_, err = s.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	return s.Sum(nil), nil
 }
 
 // Size returns the number of bytes Sum will return
@@ -48,25 +201,53 @@ func (s *SipHasher) BlockSize() int {
 	return 64
 }
 
-// sipHash13 implements the core SipHash-1-3 algorithm
-func (s *SipHasher) sipHash13(data []byte) uint64 {
+// sipHash implements the core SipHash-c-d algorithm
+func (s *SipHasher) sipHash(data []byte) uint64 {
+	_, d := s.Rounds()
+	v0, v1, v2, v3 := s.compress(data, 0)
+	v0, v1, v2, v3 = s.finalize(v0, v1, v2, v3, 0xff, d)
+	return v0 ^ v1 ^ v2 ^ v3
+}
+
+// sipHash128 implements SipHash-c-d with 128-bit output, which differs from
+// the 64-bit variant in its initialization and finalization constants and
+// runs a second finalization for the second half
+func (s *SipHasher) sipHash128(data []byte) (uint64, uint64) {
+	_, d := s.Rounds()
+	v0, v1, v2, v3 := s.compress(data, 0xee)
+	v0, v1, v2, v3 = s.finalize(v0, v1, v2, v3, 0xee, d)
+	h1 := v0 ^ v1 ^ v2 ^ v3
+	v1 ^= 0xdd
+	v0, v1, v2, v3 = s.finalize(v0, v1, v2, v3, 0, d)
+	return h1, v0 ^ v1 ^ v2 ^ v3
+}
+
+// compress initializes the state, with tweak XORed into v1, and absorbs
+// data, including the last block holding the remaining bytes and the length
+func (s *SipHasher) compress(data []byte, tweak uint64) (uint64, uint64, uint64, uint64) {
+	c, _ := s.Rounds()
 	v0, v1, v2, v3 := s.initializeState()
+	v1 ^= tweak
 	dataLen := uint64(len(data))
 
 	// Process full 64-bit blocks
 	for len(data) >= 8 {
 		m := binary.LittleEndian.Uint64(data)
 		v3 ^= m
-		s.sipRound(&v0, &v1, &v2, &v3)
+		for i := 0; i < c; i++ {
+			s.sipRound(&v0, &v1, &v2, &v3)
+		}
 		v0 ^= m
 		data = data[8:]
 	}
 
-	// Process remaining bytes and finalize
-	v3 ^= s.encodeLastBlock(data, dataLen)
-	v0, v1, v2, v3 = s.finalize(v0, v1, v2, v3)
-
-	return v0 ^ v1 ^ v2 ^ v3
+	m := s.encodeLastBlock(data, dataLen)
+	v3 ^= m
+	for i := 0; i < c; i++ {
+		s.sipRound(&v0, &v1, &v2, &v3)
+	}
+	v0 ^= m
+	return v0, v1, v2, v3
 }
 
 // initializeState sets up the initial state for SipHash
@@ -105,10 +286,10 @@ func (s *SipHasher) encodeLastBlock(data []byte, dataLen uint64) uint64 {
 	return t | (dataLen << 56)
 }
 
-// finalize performs the final rounds of SipHash
-func (s *SipHasher) finalize(v0, v1, v2, v3 uint64) (uint64, uint64, uint64, uint64) {
-	v2 ^= 0xff
-	for i := 0; i < 3; i++ {
+// finalize XORs tweak into v2 and performs the d final rounds of SipHash
+func (s *SipHasher) finalize(v0, v1, v2, v3, tweak uint64, d int) (uint64, uint64, uint64, uint64) {
+	v2 ^= tweak
+	for i := 0; i < d; i++ {
 		s.sipRound(&v0, &v1, &v2, &v3)
 	}
 	return v0, v1, v2, v3
@@ -0,0 +1,153 @@
+package hash
+
+const (
+	// tigerTreeLeafSize is the size of the blocks a TigerTreeHasher splits
+	// its input into, as THEX specifies
+	tigerTreeLeafSize = 1024
+
+	// THEX prefixes leaf and internal node data with these bytes, so that a
+	// leaf can never be mistaken for an internal node
+	tigerTreeLeafPrefix = 0x00
+	tigerTreeNodePrefix = 0x01
+)
+
+// TigerTreeHasher computes the Tiger tree hash (TTH) of its input, as
+// defined by the Tree Hash Exchange format (THEX) and used by file-sharing
+// protocols to verify downloads piece by piece.
+//
+// The input is split into 1 KiB leaves, each hashed with Tiger as
+// Tiger(0x00 || leaf), and the leaf hashes are combined pairwise, level by
+// level, as Tiger(0x01 || left || right) until a single root remains. A node
+// without a partner on its level is promoted to the next level unchanged,
+// rather than paired with itself as tree.MerkleTree and merkle.Builder do, so
+// roots from the two schemes differ. Empty input has a single empty leaf.
+//
+// Like merkle.Builder, it holds only O(log n) hashes however much is
+// written, and since it implements Hasher it can be used wherever a Hasher
+// is accepted. Sum returns the 24-byte root.
+//
+// Example:
+//
+//	h := hash.NewTigerTreeHasher()
+//	if _, err := io.Copy(h, file); err != nil {
+//		log.Fatal(err)
+//	}
+//	root := h.Sum(nil)
+type TigerTreeHasher struct {
+	BaseHasher
+	tiger    *TigerHasher
+	leaf     []byte   // Data of the current, incomplete leaf
+	frontier [][]byte // Roots of complete subtrees of 2^l leaves, by level l
+	leaves   uint64
+}
+
+// NewTigerTreeHasher creates a new TigerTreeHasher
+func NewTigerTreeHasher() *TigerTreeHasher {
+	return &TigerTreeHasher{
+		tiger: NewTigerHasher(),
+		leaf:  make([]byte, 0, tigerTreeLeafSize),
+	}
+}
+
+// Write adds more data to the running hash
+func (t *TigerTreeHasher) Write(p []byte) (n int, err error) {
+	n = len(p)
+	for len(p) > 0 {
+		copied := min(len(p), tigerTreeLeafSize-len(t.leaf))
+		t.leaf = append(t.leaf, p[:copied]...)
+		p = p[copied:]
+		if len(t.leaf) == tigerTreeLeafSize {
+			t.addLeaf(t.leafHash(t.leaf))
+			t.leaf = t.leaf[:0]
+		}
+	}
+	return n, nil
+}
+
+// Sum appends the root hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (t *TigerTreeHasher) Sum(b []byte) []byte {
+	// A partial leaf, or the empty leaf of empty input, is the last leaf
+	var carry []byte
+	if len(t.leaf) > 0 || t.leaves == 0 {
+		carry = t.leafHash(t.leaf)
+	}
+	// Fold the pending subtrees from the smallest up; a subtree without a
+	// partner on its level is promoted unchanged
+	for _, pending := range t.frontier {
+		switch {
+		case pending != nil && carry != nil:
+			carry = t.nodeHash(pending, carry)
+		case pending != nil:
+			carry = pending
+		}
+	}
+	return append(b, carry...)
+}
+
+// Reset resets the hash to its initial state
+func (t *TigerTreeHasher) Reset() {
+	t.leaf = t.leaf[:0]
+	t.frontier = t.frontier[:0]
+	t.leaves = 0
+}
+
+// Size returns the number of bytes Sum will return
+func (t *TigerTreeHasher) Size() int {
+	return tigerDigestSize
+}
+
+// BlockSize returns the size of the leaves the input is split into
+func (t *TigerTreeHasher) BlockSize() int {
+	return tigerTreeLeafSize
+}
+
+// HashKey computes the Tiger tree hash of the given key
+func (t *TigerTreeHasher) HashKey(key any) ([]byte, error) {
+	data, err := keyToBytes(key)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Reset()
+	t.Write(data)
+	return t.Sum(nil), nil
+}
+
+// addLeaf folds the hash of a complete leaf into the frontier, merging
+// subtrees of equal size as it carries.
+func (t *TigerTreeHasher) addLeaf(carry []byte) {
+	for level := 0; ; level++ {
+		if level == len(t.frontier) {
+			t.frontier = append(t.frontier, carry)
+			break
+		}
+		if t.frontier[level] == nil {
+			t.frontier[level] = carry
+			break
+		}
+		carry = t.nodeHash(t.frontier[level], carry)
+		t.frontier[level] = nil
+	}
+	t.leaves++
+}
+
+// leafHash returns Tiger(0x00 || data).
+func (t *TigerTreeHasher) leafHash(data []byte) []byte {
+	t.tiger.Reset()
+	t.tiger.Write([]byte{tigerTreeLeafPrefix})
+	t.tiger.Write(data)
+	return t.tiger.Sum(nil)
+}
+
+// nodeHash returns Tiger(0x01 || left || right).
+func (t *TigerTreeHasher) nodeHash(left, right []byte) []byte {
+	t.tiger.Reset()
+	t.tiger.Write([]byte{tigerTreeNodePrefix})
+	t.tiger.Write(left)
+	t.tiger.Write(right)
+	return t.tiger.Sum(nil)
+}
+
+// Ensure TigerTreeHasher implements the Hasher interface
+var _ Hasher = (*TigerTreeHasher)(nil)
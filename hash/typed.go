@@ -0,0 +1,128 @@
+package hash
+
+import (
+	"encoding/binary"
+	"hash"
+
+	"golang.org/x/exp/constraints"
+)
+
+// TypedHasher hashes values of type T to 64 bits, so that callers can hash
+// strings, integers or their own types without serializing them to bytes at
+// every call site.
+//
+// The implementations in this package wrap a streaming Hasher and share its
+// state, so like the Hasher itself they are not safe for concurrent use.
+type TypedHasher[T any] interface {
+	Hash(value T) uint64
+}
+
+// TypedHasherFunc adapts an ordinary function to the TypedHasher interface.
+//
+// Example:
+//
+//	h := hash.TypedHasherFunc[Point](func(p Point) uint64 {
+//		return uint64(p.X)<<32 | uint64(uint32(p.Y))
+//	})
+type TypedHasherFunc[T any] func(value T) uint64
+
+// Hash calls f(value).
+func (f TypedHasherFunc[T]) Hash(value T) uint64 {
+	return f(value)
+}
+
+// NewBytesHasher returns a TypedHasher for byte slices that hashes them with h.
+func NewBytesHasher(h Hasher) TypedHasher[[]byte] {
+	return TypedHasherFunc[[]byte](func(value []byte) uint64 {
+		return Sum64(h, value)
+	})
+}
+
+// NewStringHasher returns a TypedHasher for strings that hashes their bytes with h.
+//
+// Example:
+//
+//	h := hash.NewStringHasher(hash.NewTigerHasher())
+//	h.Hash("example")
+func NewStringHasher(h Hasher) TypedHasher[string] {
+	return TypedHasherFunc[string](func(value string) uint64 {
+		return Sum64(h, []byte(value))
+	})
+}
+
+// NewIntegerHasher returns a TypedHasher for integers that hashes their
+// 8-byte little-endian encoding with h, so equal values of different integer
+// types hash alike.
+//
+// Example:
+//
+//	h := hash.NewIntegerHasher[int](hash.NewTigerHasher())
+//	h.Hash(42)
+func NewIntegerHasher[T constraints.Integer](h Hasher) TypedHasher[T] {
+	return TypedHasherFunc[T](func(value T) uint64 {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], uint64(value))
+		return Sum64(h, buf[:])
+	})
+}
+
+// NewEncodingHasher returns a TypedHasher for any type that hashes the bytes
+// produced by encode with h. encode appends the encoding of value to buf and
+// returns the extended slice; values that are equal must encode identically.
+//
+// Example:
+//
+//	h := hash.NewEncodingHasher(hash.NewTigerHasher(), func(buf []byte, u User) []byte {
+//		buf = append(buf, u.Name...)
+//		return binary.LittleEndian.AppendUint64(buf, u.ID)
+//	})
+func NewEncodingHasher[T any](h Hasher, encode func(buf []byte, value T) []byte) TypedHasher[T] {
+	var buf []byte
+	return TypedHasherFunc[T](func(value T) uint64 {
+		buf = encode(buf[:0], value)
+		return Sum64(h, buf)
+	})
+}
+
+// NewKeyHasher returns a TypedHasher for keys of any type that hashes them
+// with h the way h.HashKey does: strings and byte slices by their bytes, and
+// other values by their binary representation. It panics if a key cannot be
+// converted to bytes.
+//
+// Example:
+//
+//	h := hash.NewKeyHasher[string](hash.NewFNV64Hasher())
+//	h.Hash("example")
+func NewKeyHasher[T any](h Hasher) TypedHasher[T] {
+	return TypedHasherFunc[T](func(value T) uint64 {
+		data, err := keyToBytes(value)
+		if err != nil {
+			panic(err)
+		}
+		return Sum64(h, data)
+	})
+}
+
+// Sum64 resets h, hashes data with it and returns the digest as a uint64,
+// as HashBytesToUint64 reads it. It is the bridge from the streaming Hasher
+// to the 64-bit hashes that collections use: hashers that implement
+// hash.Hash64 with an 8-byte digest are read through Sum64, and those that
+// implement hash.Hash32 with a 4-byte digest through Sum32, widened as
+// HashBytesToUint64 widens short digests. This avoids allocating the digest,
+// and does not depend on the byte order Sum writes it in.
+//
+// Example:
+//
+//	h := hash.NewFNV64Hasher()
+//	x := hash.Sum64(h, []byte("example"))
+func Sum64(h Hasher, data []byte) uint64 {
+	h.Reset()
+	h.Write(data)
+	if h64, ok := h.(hash.Hash64); ok && h.Size() == 8 {
+		return h64.Sum64()
+	}
+	if h32, ok := h.(hash.Hash32); ok && h.Size() == 4 {
+		return widen(uint64(h32.Sum32()))
+	}
+	return HashBytesToUint64(h.Sum(nil))
+}
@@ -0,0 +1,178 @@
+package hash
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// wyhash secret, as in the reference implementation
+var wyp = [4]uint64{0xa0761d6478bd642f, 0xe7037ed1a0b428db, 0x8ebc6af09c88c6e3, 0x589965cc75374cc3}
+
+// WyHasher implements the wyhash algorithm (final version 4), seeded with a
+// 64-bit seed. It is a fast non-cryptographic hash that reads its input 8 or
+// 16 bytes at a time, and is several times faster than SipHash on the short
+// integer and string keys typical of hash maps. A random seed makes its
+// outputs hard to predict, but unlike SipHash it is not designed to resist an
+// attacker who can observe them.
+//
+// Example:
+//
+//	h, err := hash.NewWyHasher()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	m := maps.NewHashMapWithHasher[string, int](comp.GenericComparator[string](), h).Unwrap()
+type WyHasher struct {
+	BaseHasher
+	seed uint64
+	buf  []byte // Data written since the last Reset
+}
+
+// NewWyHasher creates a new WyHasher with a random seed
+func NewWyHasher() (*WyHasher, error) {
+	seed, _, err := GenerateRandomKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate random seed: %w", err)
+	}
+	return NewWyHasherWithSeed(seed), nil
+}
+
+// NewWyHasherWithSeed creates a new WyHasher with the given seed, whose
+// outputs are reproducible across processes
+func NewWyHasherWithSeed(seed uint64) *WyHasher {
+	return &WyHasher{seed: seed}
+}
+
+// Clone returns a new WyHasher with the same seed and a fresh state
+func (w *WyHasher) Clone() *WyHasher {
+	return &WyHasher{seed: w.seed}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// It encodes the hasher's seed; the running state is not included.
+func (w *WyHasher) MarshalBinary() ([]byte, error) {
+	return Uint64ToBytes(w.seed), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It restores a seed encoded by MarshalBinary.
+func (w *WyHasher) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return fmt.Errorf("invalid WyHasher seed length: %d", len(data))
+	}
+	w.seed = binary.LittleEndian.Uint64(data)
+	return nil
+}
+
+// Write adds more data to the running hash
+func (w *WyHasher) Write(p []byte) (n int, err error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// Sum appends the current hash to b, in little-endian order, and returns the
+// resulting slice
+func (w *WyHasher) Sum(b []byte) []byte {
+	return binary.LittleEndian.AppendUint64(b, w.Sum64())
+}
+
+// Sum64 returns the current hash
+func (w *WyHasher) Sum64() uint64 {
+	return wyhash(w.buf, w.seed)
+}
+
+// Reset resets the hash to its initial state
+func (w *WyHasher) Reset() {
+	w.buf = w.buf[:0]
+}
+
+// Size returns the number of bytes Sum will return
+func (w *WyHasher) Size() int {
+	return 8
+}
+
+// BlockSize returns the hash's underlying block size
+func (w *WyHasher) BlockSize() int {
+	return 48
+}
+
+// HashKey computes the wyhash of the given key
+func (w *WyHasher) HashKey(key any) ([]byte, error) {
+	data, err := keyToBytes(key)
+	if err != nil {
+		return nil, err
+	}
+	return Uint64ToBytes(wyhash(data, w.seed)), nil
+}
+
+// Default returns the hasher that hash-based collections use unless they are
+// given one: currently a randomly seeded WyHasher. It was chosen by
+// benchmarking hash map keys of 8 to 32 bytes, on which it runs two to two
+// and a half times as fast as SipHash through Sum, and four to five times as
+// fast through Sum64, which allocates nothing; see BenchmarkSum and
+// BenchmarkSum64. Use a SipHasher instead where an attacker may choose keys
+// and observe timing.
+//
+// Example:
+//
+//	h, err := hash.Default()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func Default() (Hasher, error) {
+	return NewWyHasher()
+}
+
+// wyhash computes the wyhash of data with the given seed.
+func wyhash(data []byte, seed uint64) uint64 {
+	n := len(data)
+	seed ^= wymix(seed^wyp[0], wyp[1])
+	var a, b uint64
+	switch {
+	case n <= 16:
+		if n >= 4 {
+			a = uint64(wyr4(data))<<32 | uint64(wyr4(data[(n>>3)<<2:]))
+			b = uint64(wyr4(data[n-4:]))<<32 | uint64(wyr4(data[n-4-(n>>3)<<2:]))
+		} else if n > 0 {
+			a = uint64(data[0])<<16 | uint64(data[n>>1])<<8 | uint64(data[n-1])
+		}
+	default:
+		p := data
+		if len(p) > 48 {
+			see1, see2 := seed, seed
+			for len(p) > 48 {
+				seed = wymix(wyr8(p)^wyp[1], wyr8(p[8:])^seed)
+				see1 = wymix(wyr8(p[16:])^wyp[2], wyr8(p[24:])^see1)
+				see2 = wymix(wyr8(p[32:])^wyp[3], wyr8(p[40:])^see2)
+				p = p[48:]
+			}
+			seed ^= see1 ^ see2
+		}
+		for len(p) > 16 {
+			seed = wymix(wyr8(p)^wyp[1], wyr8(p[8:])^seed)
+			p = p[16:]
+		}
+		// The last 16 bytes of the input, which may overlap those consumed
+		a = wyr8(data[n-16:])
+		b = wyr8(data[n-8:])
+	}
+	a ^= wyp[1]
+	b ^= seed
+	hi, lo := bits.Mul64(a, b)
+	return wymix(lo^wyp[0]^uint64(n), hi^wyp[1])
+}
+
+// wymix multiplies a and b to 128 bits and folds the halves together.
+func wymix(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return hi ^ lo
+}
+
+func wyr8(p []byte) uint64 {
+	return binary.LittleEndian.Uint64(p)
+}
+
+func wyr4(p []byte) uint32 {
+	return binary.LittleEndian.Uint32(p)
+}
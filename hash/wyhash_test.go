@@ -0,0 +1,66 @@
+package hash
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchmarkHashers are the candidates for Default, measured on the key sizes
+// typical of hash maps.
+func benchmarkHashers(b *testing.B) []struct {
+	name string
+	h    Hasher
+} {
+	sip, err := NewSipHasher()
+	if err != nil {
+		b.Fatal(err)
+	}
+	wy, err := NewWyHasher()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return []struct {
+		name string
+		h    Hasher
+	}{
+		{"SipHash", sip},
+		{"WyHash", wy},
+	}
+}
+
+var benchmarkKeySizes = []int{8, 16, 32}
+
+// BenchmarkSum measures hashing through Write and Sum, which allocates the digest.
+func BenchmarkSum(b *testing.B) {
+	for _, size := range benchmarkKeySizes {
+		key := make([]byte, size)
+		for _, hasher := range benchmarkHashers(b) {
+			b.Run(fmt.Sprintf("%s/%d", hasher.name, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				for i := 0; i < b.N; i++ {
+					key[0] = byte(i)
+					hasher.h.Reset()
+					hasher.h.Write(key)
+					hasher.h.Sum(nil)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkSum64 measures hashing through Sum64, the path HashMap and the
+// filters take.
+func BenchmarkSum64(b *testing.B) {
+	for _, size := range benchmarkKeySizes {
+		key := make([]byte, size)
+		for _, hasher := range benchmarkHashers(b) {
+			b.Run(fmt.Sprintf("%s/%d", hasher.name, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				for i := 0; i < b.N; i++ {
+					key[0] = byte(i)
+					Sum64(hasher.h, key)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package res
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ielm/neostd/errors"
+)
+
+// Future is a Result that becomes available later, computed by a goroutine.
+// A Future is safe for concurrent use, and can be awaited any number of times.
+type Future[T any] struct {
+	done   chan struct{}
+	result Result[T]
+}
+
+// NewFuture runs f in a new goroutine and returns a Future for its Result.
+// If f panics, the Future holds an Err Result as Try would return.
+func NewFuture[T any](f func() Result[T]) *Future[T] {
+	fut := &Future[T]{done: make(chan struct{})}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				fut.complete(Err[T](panicError(r)))
+			}
+		}()
+		fut.complete(f())
+	}()
+	return fut
+}
+
+// Ready returns a Future that is already complete with the given Result.
+func Ready[T any](r Result[T]) *Future[T] {
+	fut := &Future[T]{done: make(chan struct{})}
+	fut.complete(r)
+	return fut
+}
+
+func (f *Future[T]) complete(r Result[T]) {
+	f.result = r
+	close(f.done)
+}
+
+// Done returns a channel that is closed when the Future completes.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await waits for the Future to complete and returns its Result. If ctx is done first,
+// it returns an Err Result with the context's error; the computation keeps running.
+func (f *Future[T]) Await(ctx context.Context) Result[T] {
+	select {
+	case <-f.done:
+		return f.result
+	case <-ctx.Done():
+		return Err[T](ctx.Err())
+	}
+}
+
+// Then returns a Future that calls op with the value of this one once it completes Ok,
+// or holds its error if it completes Err.
+func (f *Future[T]) Then(op func(T) Result[T]) *Future[T] {
+	return NewFuture(func() Result[T] {
+		<-f.done
+		return f.result.AndThen(op)
+	})
+}
+
+// Map returns a Future that applies fn to the value of this one once it completes Ok,
+// or holds its error if it completes Err.
+func (f *Future[T]) Map(fn func(T) T) *Future[T] {
+	return NewFuture(func() Result[T] {
+		<-f.done
+		return f.result.Map(fn)
+	})
+}
+
+// WhenAll returns a Future that completes with the values of all the given Futures, in
+// order, once they have all completed Ok, or with the first error as soon as any of them
+// completes Err.
+func WhenAll[T any](futures ...*Future[T]) *Future[[]T] {
+	return NewFuture(func() Result[[]T] {
+		failed := make(chan error, 1)
+		for _, fut := range futures {
+			go func(fut *Future[T]) {
+				<-fut.done
+				if fut.result.IsErr() {
+					select {
+					case failed <- fut.result.UnwrapErr():
+					default:
+					}
+				}
+			}(fut)
+		}
+
+		values := make([]T, len(futures))
+		for i, fut := range futures {
+			select {
+			case <-fut.done:
+			case err := <-failed:
+				return Err[[]T](err)
+			}
+			if fut.result.IsErr() {
+				return Err[[]T](fut.result.UnwrapErr())
+			}
+			values[i] = fut.result.Unwrap()
+		}
+		return Ok(values)
+	})
+}
+
+// WhenAny returns a Future that completes with the Result of whichever of the given
+// Futures completes first, whether Ok or Err. With no Futures, it completes Err.
+func WhenAny[T any](futures ...*Future[T]) *Future[T] {
+	if len(futures) == 0 {
+		return Ready(Err[T](errors.New(errors.ErrInvalidArgument, "WhenAny needs at least one future")))
+	}
+	return NewFuture(func() Result[T] {
+		first := make(chan Result[T], 1)
+		var once sync.Once
+		for _, fut := range futures {
+			go func(fut *Future[T]) {
+				<-fut.done
+				once.Do(func() { first <- fut.result })
+			}(fut)
+		}
+		return <-first
+	})
+}
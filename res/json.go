@@ -0,0 +1,102 @@
+package res
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ielm/neostd/errors"
+)
+
+// MarshalJSON implements the json.Marshaler interface.
+// None is encoded as null and Some as its contained value.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.isSome {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// null decodes to None and any other value to Some. A Some whose value encodes as null,
+// such as a nil pointer, therefore decodes to None.
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = None[T]()
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*o = Some(value)
+	return nil
+}
+
+// resultJSON is the tagged object a Result is encoded as: {"ok": value} for Ok, and
+// {"err": message} for Err, with the error's code if it is an *errors.Error.
+type resultJSON struct {
+	Ok   json.RawMessage   `json:"ok,omitempty"`
+	Err  *string           `json:"err,omitempty"`
+	Code *errors.ErrorCode `json:"code,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// Ok is encoded as {"ok": value} and Err as {"err": message}, with a "code" field holding
+// the error code if the error is an *errors.Error. Only the error's message is kept.
+func (r Result[T]) MarshalJSON() ([]byte, error) {
+	if r.isOk {
+		value, err := json.Marshal(r.value)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(resultJSON{Ok: value})
+	}
+
+	var encoded resultJSON
+	var message string
+	if e, ok := r.err.(*errors.Error); ok {
+		message = e.Message
+		encoded.Code = &e.Code
+	} else if r.err != nil {
+		message = r.err.Error()
+	}
+	encoded.Err = &message
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It decodes the tagged object written by MarshalJSON. An Err with a code decodes to an
+// *errors.Error with that code and message, and one without to an error with the message.
+func (r *Result[T]) UnmarshalJSON(data []byte) error {
+	var decoded resultJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	switch {
+	case decoded.Ok != nil && decoded.Err == nil:
+		var value T
+		if err := json.Unmarshal(decoded.Ok, &value); err != nil {
+			return err
+		}
+		*r = Ok(value)
+	case decoded.Err != nil && decoded.Ok == nil:
+		if decoded.Code != nil {
+			*r = Err[T](errors.New(*decoded.Code, *decoded.Err))
+		} else {
+			*r = Err[T](fmt.Errorf("%s", *decoded.Err))
+		}
+	default:
+		return errors.New(errors.ErrInvalidArgument, `Result must be encoded as an object with exactly one of "ok" and "err"`)
+	}
+	return nil
+}
+
+// Ensure Option and Result implement the json.Marshaler and json.Unmarshaler interfaces
+var (
+	_ json.Marshaler   = Option[any]{}
+	_ json.Unmarshaler = (*Option[any])(nil)
+	_ json.Marshaler   = Result[any]{}
+	_ json.Unmarshaler = (*Result[any])(nil)
+)
@@ -87,6 +87,47 @@ func (o Option[T]) OrElse(f func() Option[T]) Option[T] {
 	return f()
 }
 
+// Filter returns the Option if it is Some and its value satisfies pred, otherwise returns None.
+func (o Option[T]) Filter(pred func(T) bool) Option[T] {
+	if o.isSome && pred(o.value) {
+		return o
+	}
+	return None[T]()
+}
+
+// Xor returns whichever of the Option and optb is Some if exactly one of them is, otherwise returns None.
+func (o Option[T]) Xor(optb Option[T]) Option[T] {
+	switch {
+	case o.isSome && !optb.isSome:
+		return o
+	case !o.isSome && optb.isSome:
+		return optb
+	}
+	return None[T]()
+}
+
+// Take returns the Option's current value and leaves None in its place.
+func (o *Option[T]) Take() Option[T] {
+	taken := *o
+	*o = None[T]()
+	return taken
+}
+
+// Replace stores value in the Option and returns its previous value.
+func (o *Option[T]) Replace(value T) Option[T] {
+	old := *o
+	*o = Some(value)
+	return old
+}
+
+// GetOrInsert stores value in the Option if it is None, then returns a pointer to the contained value.
+func (o *Option[T]) GetOrInsert(value T) *T {
+	if !o.isSome {
+		*o = Some(value)
+	}
+	return &o.value
+}
+
 // Match applies the appropriate function based on the Option variant.
 func (o Option[T]) Match(someFn func(T), noneFn func()) {
 	if o.isSome {
@@ -96,6 +137,14 @@ func (o Option[T]) Match(someFn func(T), noneFn func()) {
 	}
 }
 
+// OkOrElse converts the Option to a Result type, calling f for the error only if the Option is None.
+func (o Option[T]) OkOrElse(f func() error) Result[T] {
+	if o.isSome {
+		return Ok(o.value)
+	}
+	return Err[T](f())
+}
+
 // ToResult converts the Option to a Result type.
 func (o Option[T]) ToResult(err error) Result[T] {
 	if o.isSome {
@@ -103,3 +152,102 @@ func (o Option[T]) ToResult(err error) Result[T] {
 	}
 	return Err[T](err)
 }
+
+// MatchOption calls someFn with the contained value if the Option is Some, or noneFn if
+// it is None, and returns what the called function returns. Unlike the Match method, it
+// produces a value, which may be of another type.
+func MatchOption[T, R any](o Option[T], someFn func(T) R, noneFn func() R) R {
+	if o.isSome {
+		return someFn(o.value)
+	}
+	return noneFn()
+}
+
+// MapOption applies a function to the contained value (if Some), producing an Option of
+// a different type, or returns None.
+// It is a function rather than a method because methods cannot have type parameters.
+func MapOption[T, U any](o Option[T], f func(T) U) Option[U] {
+	if o.isSome {
+		return Some(f(o.value))
+	}
+	return None[U]()
+}
+
+// FromOk creates an Option from Go's (value, ok) convention, as returned by map lookups
+// and type assertions: Some(v) if ok, otherwise None.
+func FromOk[T any](v T, ok bool) Option[T] {
+	if ok {
+		return Some(v)
+	}
+	return None[T]()
+}
+
+// ToTuple converts the Option to Go's (value, ok) convention: the contained value and
+// true if Some, or the zero value and false if None.
+func (o Option[T]) ToTuple() (T, bool) {
+	return o.value, o.isSome
+}
+
+// Pair holds two values, as combined by ZipOptions.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// ZipOptions combines two Options into an Option of their pair if both are Some,
+// otherwise returns None.
+func ZipOptions[A, B any](a Option[A], b Option[B]) Option[Pair[A, B]] {
+	if a.isSome && b.isSome {
+		return Some(Pair[A, B]{First: a.value, Second: b.value})
+	}
+	return None[Pair[A, B]]()
+}
+
+// UnzipOption splits an Option of a pair into a pair of Options, both Some if the Option
+// is Some and both None otherwise.
+func UnzipOption[A, B any](o Option[Pair[A, B]]) (Option[A], Option[B]) {
+	if o.isSome {
+		return Some(o.value.First), Some(o.value.Second)
+	}
+	return None[A](), None[B]()
+}
+
+// ZipWith combines the values of the Option and other with f if both are Some,
+// otherwise returns None.
+func (o Option[T]) ZipWith(other Option[T], f func(T, T) T) Option[T] {
+	if o.isSome && other.isSome {
+		return Some(f(o.value, other.value))
+	}
+	return None[T]()
+}
+
+// Flatten2 converts an Option[Option[T]] to Option[T].
+func Flatten2[T any](o Option[Option[T]]) Option[T] {
+	if o.isSome {
+		return o.value
+	}
+	return None[T]()
+}
+
+// Iter returns an iterator that yields the contained value if the Option is Some, and
+// nothing if it is None. It implements collections.Iterator[T], so optional values can
+// be passed wherever an iterator is expected.
+func (o Option[T]) Iter() *OptionIterator[T] {
+	return &OptionIterator[T]{next: o}
+}
+
+// OptionIterator is an iterator over zero or one value, returned by Option.Iter and
+// Result.Iter. It implements collections.Iterator[T].
+type OptionIterator[T any] struct {
+	next Option[T]
+}
+
+// HasNext returns true if the iterator has a value left to yield.
+func (it *OptionIterator[T]) HasNext() bool {
+	return it.next.isSome
+}
+
+// Next returns the iterator's value, or None once it has been yielded.
+func (it *OptionIterator[T]) Next() Option[T] {
+	return it.next.Take()
+}
@@ -1,7 +1,9 @@
 package res
 
 import (
+	stderrors "errors"
 	"fmt"
+	"runtime"
 
 	"github.com/ielm/neostd/errors"
 )
@@ -120,6 +122,24 @@ func (r Result[T]) OrElse(op func(error) Result[T]) Result[T] {
 	return op(r.err)
 }
 
+// Inspect calls f with the contained value (if Ok) and returns the Result unchanged,
+// for side effects such as logging in a chain of calls.
+func (r Result[T]) Inspect(f func(T)) Result[T] {
+	if r.isOk {
+		f(r.value)
+	}
+	return r
+}
+
+// InspectErr calls f with the contained error (if Err) and returns the Result unchanged,
+// for side effects such as logging in a chain of calls.
+func (r Result[T]) InspectErr(f func(error)) Result[T] {
+	if !r.isOk {
+		f(r.err)
+	}
+	return r
+}
+
 // Match applies the appropriate function based on the Result variant.
 func (r Result[T]) Match(okFn func(T), errFn func(error)) {
 	if r.isOk {
@@ -137,6 +157,12 @@ func (r Result[T]) ToOption() Option[T] {
 	return None[T]()
 }
 
+// Iter returns an iterator that yields the contained value if the Result is Ok, and
+// nothing if it is Err. It implements collections.Iterator[T].
+func (r Result[T]) Iter() *OptionIterator[T] {
+	return r.ToOption().Iter()
+}
+
 // NewResult creates a new Result based on the given value and error.
 // If err is nil, it returns an Ok Result, otherwise it returns an Err Result.
 func NewResult[T any](value T, err error) Result[T] {
@@ -147,11 +173,13 @@ func NewResult[T any](value T, err error) Result[T] {
 }
 
 // Try executes the given function and returns a Result.
-// If the function panics, it returns an Err Result with the panic value as the error.
+// If the function panics, it returns an Err Result holding an *errors.Error with the
+// ErrInternal code, whose stack trace is that of the panic. A panic value that is an
+// error becomes the cause.
 func Try[T any](f func() T) (result Result[T]) {
 	defer func() {
 		if r := recover(); r != nil {
-			result = Err[T](fmt.Errorf("%v", r))
+			result = Err[T](panicError(r))
 		}
 	}()
 	return Ok(f())
@@ -159,11 +187,81 @@ func Try[T any](f func() T) (result Result[T]) {
 
 // TryWithError executes the given function and returns a Result.
 // If the function returns an error, it returns an Err Result with that error.
-func TryWithError[T any](f func() (T, error)) Result[T] {
+// If the function panics, it returns an Err Result as Try does.
+func TryWithError[T any](f func() (T, error)) (result Result[T]) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = Err[T](panicError(r))
+		}
+	}()
 	value, err := f()
 	return NewResult(value, err)
 }
 
+// panicError converts a recovered panic value into an error. It must be called directly
+// from the deferred function, so that the stack it records starts at the panic.
+func panicError(r any) *errors.Error {
+	var pcs [32]uintptr
+	// Skip runtime.Callers, panicError, the deferred function and runtime.gopanic
+	n := runtime.Callers(4, pcs[:])
+	cause, _ := r.(error)
+	return &errors.Error{
+		Code:    errors.ErrInternal,
+		Message: fmt.Sprintf("panic: %v", r),
+		Cause:   cause,
+		Stack:   pcs[:n],
+	}
+}
+
+// MatchResult calls okFn with the contained value if the Result is Ok, or errFn with the
+// contained error if it is Err, and returns what the called function returns. Unlike the
+// Match method, it produces a value, which may be of another type.
+func MatchResult[T, R any](r Result[T], okFn func(T) R, errFn func(error) R) R {
+	if r.isOk {
+		return okFn(r.value)
+	}
+	return errFn(r.err)
+}
+
+// MapTo applies a function to the contained value (if Ok), producing a Result of a
+// different type, or returns the original error (if Err).
+// It is a function rather than a method because methods cannot have type parameters.
+func MapTo[T, U any](r Result[T], f func(T) U) Result[U] {
+	if r.isOk {
+		return Ok(f(r.value))
+	}
+	return Err[U](r.err)
+}
+
+// AndThenTo calls op with the contained value (if Ok) and returns its Result, which may
+// be of a different type, or returns the original error (if Err).
+func AndThenTo[T, U any](r Result[T], op func(T) Result[U]) Result[U] {
+	if r.isOk {
+		return op(r.value)
+	}
+	return Err[U](r.err)
+}
+
+// Must returns v if err is nil, otherwise panics with an error wrapping err. It suits
+// calls that cannot fail in practice, such as parsing a constant, at API boundaries that
+// return (value, error).
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(errors.NewWithCause(errors.ErrUnwrapOnErr, fmt.Sprintf("called Must with an error: %v", err), err))
+	}
+	return v
+}
+
+// ToTuple converts the Result to Go's (value, error) convention: the contained value and
+// nil if Ok, or the zero value and the contained error if Err.
+func (r Result[T]) ToTuple() (T, error) {
+	if r.isOk {
+		return r.value, nil
+	}
+	var zero T
+	return zero, r.err
+}
+
 // Flatten converts a Result[Result[T]] to Result[T].
 func Flatten[T any](r Result[Result[T]]) Result[T] {
 	if r.IsOk() {
@@ -198,6 +296,89 @@ func Collect[T, U any](values []T, f func(T) Result[U]) Result[[]U] {
 	return Ok(result)
 }
 
+// CollectAll applies a function that returns a Result to every value in a slice. Unlike
+// Collect, it does not stop at the first error: it returns either all the Ok values, or
+// an error joining every failure, each prefixed with the index of its value.
+func CollectAll[T, U any](values []T, f func(T) Result[U]) Result[[]U] {
+	result := make([]U, 0, len(values))
+	var errs []error
+	for i, v := range values {
+		r := f(v)
+		if r.IsErr() {
+			errs = append(errs, fmt.Errorf("index %d: %w", i, r.UnwrapErr()))
+			continue
+		}
+		result = append(result, r.Unwrap())
+	}
+	if len(errs) > 0 {
+		return Err[[]U](stderrors.Join(errs...))
+	}
+	return Ok(result)
+}
+
+// Join combines Results into a Result containing all their Ok values, in order, or the
+// first error among them.
+func Join[T any](results ...Result[T]) Result[[]T] {
+	values := make([]T, 0, len(results))
+	for _, r := range results {
+		if r.IsErr() {
+			return Err[[]T](r.UnwrapErr())
+		}
+		values = append(values, r.Unwrap())
+	}
+	return Ok(values)
+}
+
+// JoinLazy is like Join, but computes each Result by calling a function, and stops at the
+// first error without calling the remaining functions.
+func JoinLazy[T any](fs ...func() Result[T]) Result[[]T] {
+	values := make([]T, 0, len(fs))
+	for _, f := range fs {
+		r := f()
+		if r.IsErr() {
+			return Err[[]T](r.UnwrapErr())
+		}
+		values = append(values, r.Unwrap())
+	}
+	return Ok(values)
+}
+
+// FirstOk returns the first Ok Result among results. If there is none, it returns an
+// error joining all of their errors.
+func FirstOk[T any](results ...Result[T]) Result[T] {
+	errs := make([]error, 0, len(results))
+	for _, r := range results {
+		if r.IsOk() {
+			return r
+		}
+		errs = append(errs, r.UnwrapErr())
+	}
+	return Err[T](noneOk(errs))
+}
+
+// FirstOkLazy is like FirstOk, but computes each Result by calling a function, and stops
+// at the first Ok without calling the remaining functions, so that expensive alternatives
+// are only tried when the cheaper ones fail.
+func FirstOkLazy[T any](fs ...func() Result[T]) Result[T] {
+	errs := make([]error, 0, len(fs))
+	for _, f := range fs {
+		r := f()
+		if r.IsOk() {
+			return r
+		}
+		errs = append(errs, r.UnwrapErr())
+	}
+	return Err[T](noneOk(errs))
+}
+
+// noneOk returns the error for FirstOk and FirstOkLazy when no Result is Ok.
+func noneOk(errs []error) error {
+	if len(errs) == 0 {
+		return errors.New(errors.ErrInvalidArgument, "no results to choose from")
+	}
+	return stderrors.Join(errs...)
+}
+
 // Partition separates a slice of Results into a slice of Ok values and a slice of Err values.
 func Partition[T any](results []Result[T]) ([]T, []error) {
 	ok := make([]T, 0, len(results))
@@ -287,12 +468,20 @@ func (r Result[T]) Error() string {
 	return fmt.Sprintf("<Err: %v>", r.UnwrapErr())
 }
 
-// IsErrorCode checks if the Result contains an error with the specified error code.
-func (r Result[T]) IsErrorCode(code errors.ErrorCode) bool {
+// StackTrace returns the stack trace recorded in the contained error, if the Result is
+// Err and its error is an *errors.Error, such as one returned by Try for a panic.
+// Otherwise it returns an empty string.
+func (r Result[T]) StackTrace() string {
 	if r.IsErr() {
 		if err, ok := r.UnwrapErr().(*errors.Error); ok {
-			return err.Code == code
+			return err.StackTrace()
 		}
 	}
-	return false
+	return ""
+}
+
+// IsErrorCode checks if the Result contains an error with the specified error code,
+// either itself or among the errors it wraps.
+func (r Result[T]) IsErrorCode(code errors.ErrorCode) bool {
+	return r.IsErr() && errors.Is(r.UnwrapErr(), errors.Sentinel(code))
 }
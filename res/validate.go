@@ -0,0 +1,58 @@
+package res
+
+import (
+	"github.com/ielm/neostd/errors"
+)
+
+// Validator checks a value against a chain of conditions, collecting an error for each
+// one it fails. Create one with Validate.
+type Validator[T any] struct {
+	value T
+	errs  []error
+}
+
+// Validate starts a chain of checks on value. Every check runs, whether or not earlier
+// ones failed, so that Result reports all the problems at once.
+//
+// Example:
+//
+//	func NewBloomFilter(size uint, fpRate float64) res.Result[*BloomFilter] {
+//		params := res.Validate(bloomParams{size, fpRate}).
+//			Check(func(p bloomParams) bool { return p.size > 0 },
+//				errors.New(errors.ErrInvalidArgument, "size must be positive")).
+//			Check(func(p bloomParams) bool { return p.fpRate > 0 && p.fpRate < 1 },
+//				errors.New(errors.ErrInvalidArgument, "false positive rate must be in (0, 1)")).
+//			Result()
+//		return res.AndThenTo(params, newBloomFilter)
+//	}
+func Validate[T any](value T) *Validator[T] {
+	return &Validator[T]{value: value}
+}
+
+// Check records err if pred returns false for the value.
+func (v *Validator[T]) Check(pred func(T) bool, err error) *Validator[T] {
+	if !pred(v.value) {
+		v.errs = append(v.errs, err)
+	}
+	return v
+}
+
+// CheckWith records the error f returns for the value, if any.
+func (v *Validator[T]) CheckWith(f func(T) error) *Validator[T] {
+	if err := f(v.value); err != nil {
+		v.errs = append(v.errs, err)
+	}
+	return v
+}
+
+// Result returns the value if every check passed. Otherwise it returns the error of the
+// single failed check, or an *errors.Group of all of them, in order.
+func (v *Validator[T]) Result() Result[T] {
+	switch len(v.errs) {
+	case 0:
+		return Ok(v.value)
+	case 1:
+		return Err[T](v.errs[0])
+	}
+	return Err[T](errors.NewGroup(v.errs...))
+}
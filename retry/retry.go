@@ -0,0 +1,115 @@
+// Package retry retries operations that fail with transient errors, waiting with
+// exponential backoff and jitter between attempts.
+package retry
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand"
+	"time"
+
+	"github.com/ielm/neostd/errors"
+	"github.com/ielm/neostd/res"
+)
+
+// Policy controls how Do retries an operation. Zero fields take the values of
+// DefaultPolicy, so a Policy need only set the fields it changes.
+type Policy struct {
+	// MaxAttempts is the number of times the operation is tried, including the first
+	MaxAttempts int
+	// InitialDelay is the wait before the second attempt
+	InitialDelay time.Duration
+	// MaxDelay caps the wait between attempts
+	MaxDelay time.Duration
+	// Multiplier is the factor the wait grows by after each attempt
+	Multiplier float64
+	// Jitter is the fraction of each wait, between 0 and 1, that is randomized, so
+	// that clients failing together do not retry in lockstep. Set it negative for
+	// no jitter.
+	Jitter float64
+	// ShouldRetry reports whether an error is worth retrying
+	ShouldRetry func(error) bool
+}
+
+// DefaultPolicy returns the default policy: up to 4 attempts, waiting 100ms, then 200ms
+// and 400ms, each reduced by up to half at random, and retrying only errors that
+// errors.IsRetryable accepts.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:  4,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     10 * time.Second,
+		Multiplier:   2,
+		Jitter:       0.5,
+		ShouldRetry:  errors.IsRetryable,
+	}
+}
+
+// withDefaults returns the policy with its zero fields set from DefaultPolicy.
+func (p Policy) withDefaults() Policy {
+	d := DefaultPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = d.InitialDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = d.MaxDelay
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = d.Multiplier
+	}
+	switch {
+	case p.Jitter == 0:
+		p.Jitter = d.Jitter
+	case p.Jitter < 0:
+		p.Jitter = 0
+	case p.Jitter > 1:
+		p.Jitter = 1
+	}
+	if p.ShouldRetry == nil {
+		p.ShouldRetry = d.ShouldRetry
+	}
+	return p
+}
+
+// delay returns the wait after the given attempt, counted from 1.
+func (p Policy) delay(attempt int) time.Duration {
+	d := float64(p.InitialDelay)
+	for i := 1; i < attempt && d < float64(p.MaxDelay); i++ {
+		d *= p.Multiplier
+	}
+	d = min(d, float64(p.MaxDelay))
+	// Take a random part of the jitter fraction off the wait
+	d -= d * p.Jitter * rand.Float64()
+	return time.Duration(d)
+}
+
+// Do calls f until it returns Ok, it returns an error the policy does not retry, the
+// policy's attempts run out, or ctx is done, and returns the last Result. If ctx is done
+// while waiting between attempts, the error joins the context's error with the last one,
+// so that both can be matched with the standard library's errors.Is.
+//
+// Example:
+//
+//	r := retry.Do(ctx, retry.Policy{MaxAttempts: 5}, func() res.Result[*User] {
+//		return client.GetUser(ctx, id)
+//	})
+func Do[T any](ctx context.Context, policy Policy, f func() res.Result[T]) res.Result[T] {
+	policy = policy.withDefaults()
+	for attempt := 1; ; attempt++ {
+		r := f()
+		if r.IsOk() || attempt == policy.MaxAttempts || !policy.ShouldRetry(r.UnwrapErr()) {
+			return r
+		}
+
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return res.Err[T](stderrors.Join(ctx.Err(), r.UnwrapErr()))
+		}
+	}
+}